@@ -13,6 +13,8 @@ import (
 	"github.com/theretech/retech-core/internal/config"
 	nethttp "github.com/theretech/retech-core/internal/http"
 	"github.com/theretech/retech-core/internal/http/handlers"
+	"github.com/theretech/retech-core/internal/jobs"
+	"github.com/theretech/retech-core/internal/notify"
 	"github.com/theretech/retech-core/internal/observability"
 	"github.com/theretech/retech-core/internal/storage"
 )
@@ -92,6 +94,9 @@ func main() {
 		log.Warn().Err(err).Msg("failed to create activity logs indexes")
 	}
 
+	// ⏰ Avisos de expiração de API keys (activity log + webhook NOTIFY_WEBHOOK_URL)
+	jobs.NewAPIKeyExpiryNotifier(apikeys, activityLogs, notify.NewWebhookFromEnv(), log).Start(context.Background())
+
 	// 🎯 DESABILITADO: API Key Demo agora é criada manualmente via admin/settings
 	// Usar botão "Gerar Nova" ou "Rotacionar" em /admin/settings
 	// if err := bootstrap.EnsureDemoAPIKey(context.Background(), apikeys, tenants, settings, m.DB); err != nil {
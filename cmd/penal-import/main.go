@@ -0,0 +1,228 @@
+// penal-import converte o texto de uma lei publicado no Planalto (HTML ou texto) em registros
+// domain.ArtigoPenal e mostra as diferenças em relação à base atual (MongoDB ou arquivo JSON).
+//
+// Uso:
+//
+//	go run ./cmd/penal-import -in del2848compilado.htm -leg CP
+//	go run ./cmd/penal-import -in l11340.htm -leg MDP -out seeds/penal_mdp.json
+//	go run ./cmd/penal-import -in l10826.htm -leg DES -base seeds/penal.json -json
+//
+// Sem -base, compara com a collection penal_artigos (MONGO_URI / MONGO_DB).
+// Nada é gravado no banco: revise o diff e inclua os registros em seeds/penal.json.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/theretech/retech-core/internal/config"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// legislacoes metadados conhecidos por código curto (mesmos valores de seeds/penal.json)
+var legislacoes = map[string]domain.PenalImportLegislacao{
+	"CP":  {Codigo: "CP", Legislacao: "CP", LegislacaoNome: "Código Penal", Tipo: "crime"},
+	"LCP": {Codigo: "LCP", Legislacao: "LCP", LegislacaoNome: "Lei de Contravenções Penais", Tipo: "contravencao"},
+	"DRG": {Codigo: "DRG", Legislacao: "Lei 11.343/2006", LegislacaoNome: "Lei de Drogas", Tipo: "crime"},
+	"ECA": {Codigo: "ECA", Legislacao: "ECA", LegislacaoNome: "Estatuto da Criança e do Adolescente", Tipo: "crime"},
+	"CTB": {Codigo: "CTB", Legislacao: "CTB", LegislacaoNome: "Código de Trânsito Brasileiro", Tipo: "crime"},
+	"AMB": {Codigo: "AMB", Legislacao: "Lei 9.605/98", LegislacaoNome: "Lei de Crimes Ambientais", Tipo: "crime"},
+	"CDC": {Codigo: "CDC", Legislacao: "CDC", LegislacaoNome: "Código de Defesa do Consumidor", Tipo: "crime"},
+	"LVD": {Codigo: "LVD", Legislacao: "Lei 9.613/98", LegislacaoNome: "Lei de Lavagem de Dinheiro", Tipo: "crime"},
+	"MDP": {Codigo: "MDP", Legislacao: "Lei 11.340/2006", LegislacaoNome: "Lei Maria da Penha", Tipo: "crime"},
+	"DES": {Codigo: "DES", Legislacao: "Lei 10.826/2003", LegislacaoNome: "Estatuto do Desarmamento", Tipo: "crime"},
+}
+
+func main() {
+	in := flag.String("in", "", "Arquivo HTML/texto da lei (formato Planalto); \"-\" para stdin")
+	leg := flag.String("leg", "", "Código curto da legislação (CP, LCP, DRG, MDP, DES, ...)")
+	legislacao := flag.String("legislacao", "", "Nome curto da legislação (ex: \"Lei 14.132/2021\"); obrigatório para códigos desconhecidos")
+	nome := flag.String("nome", "", "Nome por extenso (ex: \"Lei do Stalking\")")
+	tipo := flag.String("tipo", "", "crime ou contravencao")
+	fonte := flag.String("fonte", "", "URL da fonte oficial")
+	dataAtualizacao := flag.String("data", time.Now().Format("01/2006"), "Data de atualização da fonte")
+	todos := flag.Bool("todos", false, "Incluir dispositivos sem pena (por padrão, apenas os que cominam pena)")
+	base := flag.String("base", "", "Comparar com um arquivo JSON (ex: seeds/penal.json) em vez do MongoDB")
+	out := flag.String("out", "", "Gravar os registros importados em JSON (formato de seeds/penal.json)")
+	asJSON := flag.Bool("json", false, "Imprimir o diff em JSON")
+	flag.Parse()
+
+	if *in == "" || *leg == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	info, ok := legislacoes[strings.ToUpper(*leg)]
+	if !ok {
+		info = domain.PenalImportLegislacao{Codigo: strings.ToUpper(*leg), Tipo: "crime"}
+	}
+	if *legislacao != "" {
+		info.Legislacao = *legislacao
+	}
+	if *nome != "" {
+		info.LegislacaoNome = *nome
+	}
+	if *tipo != "" {
+		info.Tipo = *tipo
+	}
+	if info.Legislacao == "" {
+		fatal("legislação %s desconhecida: informe -legislacao e -nome", info.Codigo)
+	}
+	info.Fonte = *fonte
+	info.DataAtualizacao = *dataAtualizacao
+
+	data, err := readInput(*in)
+	if err != nil {
+		fatal("erro ao ler %s: %v", *in, err)
+	}
+
+	importados, err := domain.ParsePlanalto(data, info, domain.PenalImportOptions{Todos: *todos})
+	if err != nil {
+		fatal("erro ao interpretar %s: %v", *in, err)
+	}
+
+	atuais, err := loadAtuais(*base, info.Codigo)
+	if err != nil {
+		fatal("erro ao carregar base atual: %v", err)
+	}
+
+	diff := domain.DiffArtigosPenais(atuais, importados)
+
+	if *out != "" {
+		payload, _ := json.MarshalIndent(importados, "", "  ")
+		if err := os.WriteFile(*out, payload, 0o644); err != nil {
+			fatal("erro ao gravar %s: %v", *out, err)
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(diff)
+		return
+	}
+	printDiff(info, importados, diff)
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// loadAtuais carrega os dispositivos da legislação do arquivo JSON ou da collection penal_artigos
+func loadAtuais(base, codigo string) ([]domain.ArtigoPenal, error) {
+	var artigos []domain.ArtigoPenal
+
+	if base != "" {
+		data, err := os.ReadFile(base)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &artigos); err != nil {
+			return nil, err
+		}
+	} else {
+		cfg := config.Load()
+		m, err := storage.NewMongo(cfg.MongoURI, cfg.MongoDB)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		filter := bson.M{"idUnico": bson.M{"$regex": "^" + regexp.QuoteMeta(codigo+":")}}
+		cursor, err := m.DB.Collection("penal_artigos").Find(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if err := cursor.All(ctx, &artigos); err != nil {
+			return nil, err
+		}
+	}
+
+	filtrados := make([]domain.ArtigoPenal, 0, len(artigos))
+	for _, artigo := range artigos {
+		if strings.HasPrefix(artigo.IdUnico, codigo+":") {
+			filtrados = append(filtrados, artigo)
+		}
+	}
+	return filtrados, nil
+}
+
+func printDiff(info domain.PenalImportLegislacao, importados []domain.ArtigoPenal, diff domain.PenalImportDiff) {
+	fmt.Printf("📥 %s (%s): %d dispositivos importados\n\n", info.LegislacaoNome, info.Codigo, len(importados))
+
+	fmt.Printf("➕ Novos (%d)\n", len(diff.Novos))
+	for _, artigo := range diff.Novos {
+		fmt.Printf("   %-14s %s | %s %s\n", artigo.IdUnico, artigo.Descricao, artigo.PenaMin, artigo.PenaMax)
+	}
+
+	fmt.Printf("\n✏️  Alterados (%d)\n", len(diff.Alterados))
+	for _, alteracao := range diff.Alterados {
+		fmt.Printf("   %s [%s]\n", alteracao.IdUnico, strings.Join(alteracao.Alterados, ", "))
+		campos := []struct {
+			nome    string
+			trechos []domain.DiffTrecho
+		}{
+			{"descricao", alteracao.Descricao},
+			{"textoCompleto", alteracao.Texto},
+			{"penaMin", alteracao.PenaMin},
+			{"penaMax", alteracao.PenaMax},
+		}
+		for _, campo := range campos {
+			if containsString(alteracao.Alterados, campo.nome) {
+				fmt.Printf("      %s: %s\n", campo.nome, formatTrechos(campo.trechos))
+			}
+		}
+	}
+
+	sort.Strings(diff.Ausentes)
+	fmt.Printf("\n➖ Na base mas não na importação (%d)\n", len(diff.Ausentes))
+	for _, id := range diff.Ausentes {
+		fmt.Printf("   %s\n", id)
+	}
+
+	fmt.Printf("\n= Sem alteração: %d\n", diff.Iguais)
+}
+
+// formatTrechos formato de diff legível: [-removido-] {+inserido+}
+func formatTrechos(trechos []domain.DiffTrecho) string {
+	parts := make([]string, 0, len(trechos))
+	for _, t := range trechos {
+		switch t.Op {
+		case "removido":
+			parts = append(parts, "[-"+t.Texto+"-]")
+		case "inserido":
+			parts = append(parts, "{+"+t.Texto+"+}")
+		default:
+			parts = append(parts, t.Texto)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func fatal(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "❌ "+format+"\n", args...)
+	os.Exit(1)
+}
@@ -4,12 +4,15 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/theretech/retech-core/internal/storage"
+	"github.com/theretech/retech-core/internal/domain"
 )
 
 func hashKey(secret, keyId, keySecret string) string {
@@ -19,12 +22,15 @@ func hashKey(secret, keyId, keySecret string) string {
 }
 
 // Espera header: X-API-Key: keyId.keySecret
-func AuthAPIKey(repo *storage.APIKeysRepo) gin.HandlerFunc {
+// Resolve o principal (key + tenant + limites) uma única vez e o guarda no contexto para RequireScope e RateLimiter
+func AuthAPIKey(principals *PrincipalResolver) gin.HandlerFunc {
 	secret := os.Getenv("APIKEY_HASH_SECRET")
 	if secret == "" {
 		panic("🔒 ERRO DE SEGURANÇA: APIKEY_HASH_SECRET não configurado! Configure esta variável de ambiente obrigatória.")
 	}
+	policy := domain.APIKeyExpiryPolicyFromEnv()
 	return func(c *gin.Context) {
+		inicio := time.Now()
 		raw := c.GetHeader("X-API-Key")
 		parts := strings.Split(raw, ".")
 		if len(parts) != 2 {
@@ -32,22 +38,67 @@ func AuthAPIKey(repo *storage.APIKeysRepo) gin.HandlerFunc {
 			return
 		}
 		keyId, keySecret := parts[0], parts[1]
-		k, err := repo.ByKeyID(c, keyId)
-		if err != nil || k == nil || k.Revoked {
+		p, source, err := principals.Resolve(c.Request.Context(), keyId)
+		if err != nil || p == nil || p.Key.Revoked {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unknown api key"})
 			return
 		}
-	if k.KeyHash != hashKey(secret, keyId, keySecret) {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
-		return
-	}
+		k := &p.Key
+		if !principals.VerifyKey(p, hashKey(secret, keyId, keySecret)) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid api key"})
+			return
+		}
+
+		// ⏰ Expiração (o índice TTL não remove mais as keys: a validade é conferida aqui)
+		now := time.Now().UTC()
+		var expired *domain.APIKeyExpiredError
+		if err := k.CheckExpiry(now, policy); errors.As(err, &expired) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"type":       "https://retech-core/errors/api-key-expired",
+				"title":      "API Key Expired",
+				"status":     http.StatusUnauthorized,
+				"detail":     fmt.Sprintf("API key expirou em %s. Estenda ou rotacione a key em /me/apikeys", expired.ExpiresAt.Format(time.RFC3339)),
+				"keyId":      expired.KeyID,
+				"expiresAt":  expired.ExpiresAt,
+				"graceUntil": expired.GraceUntil,
+			})
+			return
+		}
+		// 🔒 Restrições da key (IP/CIDR, Origin/Referer e User-Agent)
+		if violacao := k.Restrictions.Check(c.ClientIP(), c.GetHeader("Origin"), c.GetHeader("Referer"), c.Request.UserAgent()); violacao != nil {
+			titles := map[string]string{
+				domain.APIKeyRestrictionIP:        "IP Not Allowed",
+				domain.APIKeyRestrictionOrigin:    "Origin Not Allowed",
+				domain.APIKeyRestrictionUserAgent: "User-Agent Not Allowed",
+			}
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"type":   "https://retech-core/errors/" + violacao.Code,
+				"title":  titles[violacao.Code],
+				"status": http.StatusForbidden,
+				"detail": violacao.Message,
+				"keyId":  k.KeyID,
+				"value":  violacao.Value,
+			})
+			return
+		}
+		switch k.Status(now, policy) {
+		case domain.APIKeyStatusGrace:
+			c.Header("X-API-Key-Expires-At", k.ExpiresAt.Format(time.RFC3339))
+			c.Header("Warning", fmt.Sprintf(`299 retech-core "API key expirada em %s; periodo de carencia ate %s"`,
+				k.ExpiresAt.Format(time.RFC3339), k.GraceUntil(policy).Format(time.RFC3339)))
+		case domain.APIKeyStatusExpiring:
+			c.Header("X-API-Key-Expires-At", k.ExpiresAt.Format(time.RFC3339))
+		}
 
-	// ✅ Adicionar API key e tenant_id ao contexto para outros middlewares
-	// NOTA: OwnerID na prática contém o TenantID (não o UserID)
-	// TODO: Futuramente, migrar para usar UserID e buscar o tenant do usuário
-	c.Set("api_key", raw)
-	c.Set("tenant_id", k.OwnerID) // OwnerID = TenantID na implementação atual
+		// ⏱️ Custo da autenticação e origem do principal (local, redis ou mongo)
+		c.Header("Server-Timing", fmt.Sprintf(`auth;desc="%s";dur=%.3f`, source, float64(time.Since(inicio))/float64(time.Millisecond)))
 
-	c.Next()
-}
+		// ✅ Principal + API key e tenant_id no contexto para outros middlewares
+		// NOTA: OwnerID na prática contém o TenantID (não o UserID)
+		c.Set(PrincipalContextKey, p)
+		c.Set("api_key", raw)
+		c.Set("tenant_id", p.TenantID)
+
+		c.Next()
+	}
 }
@@ -15,10 +15,11 @@ var (
 
 // JWTClaims representa as claims customizadas do JWT
 type JWTClaims struct {
-	UserID   string          `json:"userId"`
-	Email    string          `json:"email"`
-	Role     domain.UserRole `json:"role"`
-	TenantID string          `json:"tenantId,omitempty"`
+	UserID     string            `json:"userId"`
+	Email      string            `json:"email"`
+	Role       domain.UserRole   `json:"role"`
+	TenantID   string            `json:"tenantId,omitempty"`
+	TenantRole domain.TenantRole `json:"tenantRole,omitempty"` // Papel no tenant (owner, admin, developer, billing, viewer)
 	jwt.RegisteredClaims
 }
 
@@ -44,10 +45,11 @@ func NewJWTService(accessSecret, refreshSecret string, accessTTL, refreshTTL tim
 func (s *JWTService) GenerateAccessToken(user *domain.User) (string, error) {
 	now := time.Now()
 	claims := JWTClaims{
-		UserID:   user.ID,
-		Email:    user.Email,
-		Role:     user.Role,
-		TenantID: user.TenantID,
+		UserID:     user.ID,
+		Email:      user.Email,
+		Role:       user.Role,
+		TenantID:   user.TenantID,
+		TenantRole: user.EffectiveTenantRole(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTTL)),
 			IssuedAt:  jwt.NewNumericDate(now),
@@ -6,6 +6,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
 )
 
 // AuthJWT middleware para validar JWT e extrair claims
@@ -64,6 +65,7 @@ func AuthJWT(jwtService *JWTService) gin.HandlerFunc {
 		c.Set("email", claims.Email)
 		c.Set("role", claims.Role)
 		c.Set("tenantID", claims.TenantID)
+		c.Set("tenantRole", claims.TenantRole)
 
 		c.Next()
 	}
@@ -99,8 +101,8 @@ func RequireSuperAdmin() gin.HandlerFunc {
 	}
 }
 
-// RequireTenantUser middleware que requer role TENANT_USER
-func RequireTenantUser() gin.HandlerFunc {
+// RequireTenantUser middleware que requer role TENANT_USER e, se informados, um dos papéis no tenant
+func RequireTenantUser(roles ...domain.TenantRole) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
@@ -125,6 +127,58 @@ func RequireTenantUser() gin.HandlerFunc {
 			return
 		}
 
+		if tenantRole := GetTenantRole(c); len(roles) > 0 && !tenantRole.In(roles) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"type":   "https://retech-core/errors/forbidden",
+				"title":  "Insufficient Role",
+				"status": http.StatusForbidden,
+				"detail": "Seu papel no tenant não permite esta operação",
+				"meta": gin.H{
+					"requiredRoles": roles,
+					"yourRole":      tenantRole,
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireTenantRole middleware que requer um dos papéis no tenant conferindo o papel atual no banco
+// (o claim tenantRole do JWT só é atualizado no próximo refresh). Usar após RequireTenantUser().
+func RequireTenantRole(users *storage.UsersRepo, roles ...domain.TenantRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := users.FindByID(c.Request.Context(), GetUserID(c))
+		if err != nil || !user.Active || user.TenantID != GetTenantID(c) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"type":   "https://retech-core/errors/unauthorized",
+				"title":  "Unauthorized",
+				"status": http.StatusUnauthorized,
+				"detail": "Usuário não encontrado ou inativo",
+			})
+			c.Abort()
+			return
+		}
+
+		tenantRole := user.EffectiveTenantRole()
+		if !tenantRole.In(roles) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"type":   "https://retech-core/errors/forbidden",
+				"title":  "Insufficient Role",
+				"status": http.StatusForbidden,
+				"detail": "Seu papel no tenant não permite esta operação",
+				"meta": gin.H{
+					"requiredRoles": roles,
+					"yourRole":      tenantRole,
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("tenantRole", tenantRole) // Handlers seguintes veem o papel atual
 		c.Next()
 	}
 }
@@ -153,6 +207,19 @@ func GetRole(c *gin.Context) domain.UserRole {
 	return ""
 }
 
+// GetTenantRole helper para extrair o papel no tenant (tokens emitidos antes dos papéis = owner)
+func GetTenantRole(c *gin.Context) domain.TenantRole {
+	if GetRole(c) != domain.RoleTenantUser {
+		return ""
+	}
+	if role, exists := c.Get("tenantRole"); exists {
+		if r, ok := role.(domain.TenantRole); ok && r != "" {
+			return r
+		}
+	}
+	return domain.TenantRoleOwner
+}
+
 // IsSuperAdmin helper para verificar se é super admin
 func IsSuperAdmin(c *gin.Context) bool {
 	return GetRole(c) == domain.RoleSuperAdmin
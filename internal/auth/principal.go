@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/cache"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+)
+
+// PrincipalContextKey chave do principal autenticado no contexto do gin
+const PrincipalContextKey = "principal"
+
+// TTLs do cache de principals: o LRU local é curto (limita a defasagem se o pub/sub do Redis falhar)
+const (
+	principalLocalTTL = time.Minute
+	principalRedisTTL = 10 * time.Minute
+)
+
+// Origem da resolução (header Server-Timing e estatísticas)
+const principalSourceMongo = "mongo"
+
+type resolveTiming struct {
+	count atomic.Int64
+	nanos atomic.Int64
+}
+
+// PrincipalResolver resolve a identidade da API key uma vez por requisição: LRU em memória → Redis → MongoDB.
+// Revogação/rotação/alteração de keys, atualização de tenants e das configurações invalidam o cache (hooks dos repos).
+type PrincipalResolver struct {
+	apikeys  *storage.APIKeysRepo
+	tenants  *storage.TenantsRepo
+	settings *storage.SettingsRepo
+	cache    *cache.PrincipalCache
+	secret   []byte // Segredo do KeyVerifier
+	timings  map[string]*resolveTiming
+}
+
+// NewPrincipalResolver capacidade do LRU em PRINCIPAL_CACHE_SIZE (padrão: 10000); redisClient pode ser nil.
+// O KeyVerifier usa PRINCIPAL_CACHE_SECRET (sem ele, um segredo derivado de APIKEY_HASH_SECRET).
+func NewPrincipalResolver(apikeys *storage.APIKeysRepo, tenants *storage.TenantsRepo, settings *storage.SettingsRepo, redisClient interface{}) *PrincipalResolver {
+	size := 10000
+	if v, err := strconv.Atoi(os.Getenv("PRINCIPAL_CACHE_SIZE")); err == nil && v > 0 {
+		size = v
+	}
+	secret := []byte(os.Getenv("PRINCIPAL_CACHE_SECRET"))
+	if len(secret) == 0 {
+		h := hmac.New(sha256.New, []byte(os.Getenv("APIKEY_HASH_SECRET")))
+		h.Write([]byte("principal-cache"))
+		secret = h.Sum(nil)
+	}
+
+	r := &PrincipalResolver{
+		apikeys:  apikeys,
+		tenants:  tenants,
+		settings: settings,
+		cache:    cache.NewPrincipalCache(redisClient, size, principalLocalTTL, principalRedisTTL),
+		secret:   secret,
+		timings: map[string]*resolveTiming{
+			cache.PrincipalSourceLocal: {},
+			cache.PrincipalSourceRedis: {},
+			principalSourceMongo:       {},
+		},
+	}
+
+	apikeys.OnChange(func(ctx context.Context, keyID string) {
+		r.cache.Invalidate(ctx, keyID)
+	})
+	tenants.OnChange(r.InvalidateTenant)
+	settings.OnChange(r.cache.InvalidateAll) // Limites padrão podem ter mudado
+	return r
+}
+
+// Listen aplica as invalidações publicadas por outras instâncias
+func (r *PrincipalResolver) Listen(ctx context.Context) {
+	r.cache.Listen(ctx)
+}
+
+// Resolve retorna o principal da key (nil se não existe ou foi revogada) e a origem (local, redis ou mongo)
+func (r *PrincipalResolver) Resolve(ctx context.Context, keyID string) (*domain.Principal, string, error) {
+	inicio := time.Now()
+
+	// Geração lida antes da busca: se uma invalidação ocorrer durante a carga, o principal não é gravado
+	generation := r.cache.Generation()
+	p, source := r.cache.Get(ctx, keyID)
+	if p != nil && source == cache.PrincipalSourceRedis {
+		r.cache.SetLocal(p, generation)
+	}
+	if p == nil {
+		var err error
+		if p, err = r.load(ctx, keyID); err != nil || p == nil {
+			return nil, "", err
+		}
+		p.KeyVerifier = r.keyVerifier(p.Key.KeyHash)
+		r.cache.Set(ctx, p, generation)
+		source = principalSourceMongo
+	}
+
+	t := r.timings[source]
+	t.count.Add(1)
+	t.nanos.Add(int64(time.Since(inicio)))
+	return p, source, nil
+}
+
+// VerifyKey confere o hash da key recebida: com o hash do MongoDB quando disponível, senão
+// (principal vindo do Redis) pelo KeyVerifier
+func (r *PrincipalResolver) VerifyKey(p *domain.Principal, keyHash string) bool {
+	if p.Key.KeyHash != "" {
+		return hmac.Equal([]byte(p.Key.KeyHash), []byte(keyHash))
+	}
+	return p.KeyVerifier != "" && hmac.Equal([]byte(p.KeyVerifier), []byte(r.keyVerifier(keyHash)))
+}
+
+// keyVerifier HMAC-SHA256 do hash da key com o segredo do cache (o hash em si não vai para o Redis)
+func (r *PrincipalResolver) keyVerifier(keyHash string) string {
+	h := hmac.New(sha256.New, r.secret)
+	h.Write([]byte(keyHash))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (r *PrincipalResolver) load(ctx context.Context, keyID string) (*domain.Principal, error) {
+	k, err := r.apikeys.ByKeyID(ctx, keyID)
+	if err != nil || k == nil {
+		return nil, err
+	}
+
+	p := &domain.Principal{
+		Key:        *k,
+		TenantID:   k.OwnerID, // OwnerID = TenantID na implementação atual
+		ResolvedAt: time.Now().UTC(),
+	}
+
+	tenant, err := r.tenants.ByTenantID(ctx, k.OwnerID)
+	if err == nil && tenant != nil {
+		p.TenantName = tenant.Name
+		if tenant.RateLimit != nil {
+			p.RateLimit, p.CustomRate = *tenant.RateLimit, true
+			return p, nil
+		}
+	}
+
+	// Padrão do sistema (com fallback fixo se as configurações não carregarem)
+	p.RateLimit = domain.RateLimitConfig{RequestsPerDay: 1000, RequestsPerMinute: 60}
+	if settings, err := r.settings.Get(ctx); err == nil && settings != nil {
+		p.RateLimit = settings.DefaultRateLimit
+	}
+	return p, nil
+}
+
+// InvalidateTenant remove do cache os principals de todas as keys do tenant
+func (r *PrincipalResolver) InvalidateTenant(ctx context.Context, tenantID string) {
+	keyIDs, _ := r.apikeys.KeyIDsByOwner(ctx, tenantID)
+	r.cache.InvalidateTenant(ctx, tenantID, keyIDs)
+}
+
+// GetStats acertos do cache e tempo médio de resolução por origem
+// GET /admin/cache/principals/stats
+func (r *PrincipalResolver) GetStats(c *gin.Context) {
+	medias := gin.H{}
+	for source, t := range r.timings {
+		media := 0.0
+		if n := t.count.Load(); n > 0 {
+			media = float64(t.nanos.Load()) / float64(n) / float64(time.Microsecond)
+		}
+		medias[source] = gin.H{"count": t.count.Load(), "avgMicros": media}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cache":   r.cache.Stats(),
+		"resolve": medias,
+	})
+}
+
+// GetPrincipal principal autenticado pelo AuthAPIKey (nil se a rota não usa API key)
+func GetPrincipal(c *gin.Context) *domain.Principal {
+	if v, ok := c.Get(PrincipalContextKey); ok {
+		if p, ok := v.(*domain.Principal); ok {
+			return p
+		}
+	}
+	return nil
+}
@@ -2,19 +2,19 @@ package auth
 
 import (
 	"net/http"
+	"path"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/theretech/retech-core/internal/domain"
-	"github.com/theretech/retech-core/internal/storage"
 )
 
 // RequireScope verifica se a API key tem o scope necessário para acessar a rota
-func RequireScope(repo *storage.APIKeysRepo, requiredScope string) gin.HandlerFunc {
+// Usa o principal resolvido pelo AuthAPIKey (sem nova consulta ao banco)
+func RequireScope(requiredScope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Pegar API Key do header
-		raw := c.GetHeader("X-API-Key")
-		if raw == "" {
+		p := GetPrincipal(c)
+		if p == nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"type":   "https://retech-core/errors/unauthorized",
 				"title":  "API Key Required",
@@ -23,45 +23,32 @@ func RequireScope(repo *storage.APIKeysRepo, requiredScope string) gin.HandlerFu
 			})
 			return
 		}
+		k := &p.Key
 
-		parts := strings.Split(raw, ".")
-		if len(parts) != 2 {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"type":   "https://retech-core/errors/unauthorized",
-				"title":  "Invalid API Key Format",
-				"status": http.StatusUnauthorized,
-				"detail": "API Key deve estar no formato: keyId.keySecret",
-			})
-			return
+		// Permissão da rota: explícita ("cnpj:lookup") ou pelo registro rota → permissão
+		permission := domain.FindPermission(requiredScope)
+		if permission == nil {
+			permission = domain.PermissionForRoute(c.Request.Method, c.FullPath())
 		}
 
-		keyId := parts[0]
-		k, err := repo.ByKeyID(c, keyId)
-		if err != nil || k == nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"type":   "https://retech-core/errors/unauthorized",
-				"title":  "Unknown API Key",
-				"status": http.StatusUnauthorized,
-				"detail": "API Key não encontrada",
-			})
-			return
-		}
-
-		// Verificar se tem scope 'all' (acesso total)
-		if hasScope(k.Scopes, "all") {
-			c.Next()
-			return
+		allowed := false
+		required := requiredScope
+		if permission != nil {
+			allowed = domain.ScopesGrant(k.Scopes, permission)
+			required = permission.Scope
+		} else {
+			// Rota fora do registro: exige a API inteira ("cep", "cep:*" ou "all")
+			allowed = hasScope(k.Scopes, requiredScope)
 		}
 
-		// Verificar se tem o scope específico
-		if !hasScope(k.Scopes, requiredScope) {
+		if !allowed {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
 				"type":   "https://retech-core/errors/forbidden",
 				"title":  "Insufficient Permissions",
 				"status": http.StatusForbidden,
-				"detail": "API Key não tem permissão para acessar este recurso. Scope necessário: " + requiredScope,
+				"detail": "API Key não tem permissão para acessar este recurso. Scope necessário: " + required,
 				"meta": gin.H{
-					"requiredScope": requiredScope,
+					"requiredScope": required,
 					"yourScopes":    k.Scopes,
 				},
 			})
@@ -72,36 +59,51 @@ func RequireScope(repo *storage.APIKeysRepo, requiredScope string) gin.HandlerFu
 	}
 }
 
-// hasScope verifica se um slice de scopes contém um scope específico
-func hasScope(scopes []string, scope string) bool {
-	// Extrair apenas o nome do scope (ex: "geo:read" → "geo")
-	scopeName := scope
-	if idx := strings.Index(scope, ":"); idx != -1 {
-		scopeName = scope[:idx]
-	}
-
+// hasScope verifica se os scopes concedem a API inteira (sem registro de permissão para a rota)
+func hasScope(scopes []string, api string) bool {
 	for _, s := range scopes {
-		// Comparar sem sufixo :read/:write
-		sName := s
-		if idx := strings.Index(s, ":"); idx != -1 {
-			sName = s[:idx]
-		}
-		
-		if sName == scopeName || s == scope || s == "all" {
+		if s == "all" || s == "*" || s == api || s == api+":*" {
 			return true
 		}
 	}
 	return false
 }
 
+// UnregisteredRoutes rotas das APIs (inclusive /public/<api>) sem permissão no registro.
+// Essas rotas só aceitam keys com a API inteira ("cep", "cep:*" ou "all").
+func UnregisteredRoutes(routes gin.RoutesInfo) []string {
+	apis := map[string]bool{}
+	for _, p := range domain.Permissions {
+		apis[p.API] = true
+	}
+
+	var out []string
+	for _, r := range routes {
+		segmentos := strings.Split(strings.TrimPrefix(r.Path, "/public"), "/")
+		if len(segmentos) < 2 || !apis[segmentos[1]] {
+			continue
+		}
+		if domain.PermissionForRoute(r.Method, r.Path) == nil {
+			out = append(out, r.Method+" "+r.Path)
+		}
+	}
+	return out
+}
+
 // ValidateAPIKeyScopes valida scopes ao criar API Key
 func ValidateAPIKeyScopes(scopes []string) error {
 	validScopes := map[string]bool{
-		"geo":   true,
-		"cep":   true,
-		"cnpj":  true,
-		"penal": true, // ✅ NOVO: API de Artigos Penais
-		"all":   true,
+		"geo":      true,
+		"cep":      true,
+		"cnpj":     true,
+		"penal":    true, // ✅ NOVO: API de Artigos Penais
+		"nfe":      true, // ✅ NOVO: Chave de acesso NF-e/CT-e (offline)
+		"boleto":   true, // ✅ NOVO: Decodificação de boletos (offline)
+		"pix":      true, // ✅ NOVO: BR Code e classificação de chaves PIX (offline)
+		"placa":    true, // ✅ NOVO: Validação/conversão de placas (offline)
+		"ncm":      true, // ✅ NOVO: Classificação fiscal NCM/CEST
+		"endereco": true, // ✅ NOVO: Normalização/validação de endereços (CEP + IBGE)
+		"all":      true,
 		// Futuros:
 		"cpf":    false, // ainda não implementado
 		"fipe":   false,
@@ -110,21 +112,56 @@ func ValidateAPIKeyScopes(scopes []string) error {
 	}
 
 	for _, scope := range scopes {
-		// Remover sufixo :read/:write se houver
-		scopeName := scope
-		if idx := strings.Index(scope, ":"); idx != -1 {
-			scopeName = scope[:idx]
+		if scope == "all" {
+			continue
 		}
 
-		if implemented, exists := validScopes[scopeName]; !exists {
-			return &domain.ValidationError{
-				Field:   "scopes",
-				Message: "Scope '" + scope + "' não reconhecido",
+		// Padrão glob malformado ("cep:[") nunca casaria com nada em runtime
+		if strings.ContainsAny(scope, "*?[") {
+			if _, err := path.Match(scope, ""); err != nil {
+				return &domain.ValidationError{
+					Field:   "scopes",
+					Message: "Scope '" + scope + "' possui padrão inválido",
+				}
 			}
-		} else if !implemented {
+		}
+
+		// API ("cep" em "cep:search") precisa existir e estar implementada (padrões como "p*" são
+		// validados abaixo pelas permissões que concedem)
+		api, action, hasAction := strings.Cut(scope, ":")
+		if !strings.ContainsAny(api, "*?[") {
+			if implemented, exists := validScopes[api]; !exists {
+				return &domain.ValidationError{
+					Field:   "scopes",
+					Message: "Scope '" + scope + "' não reconhecido",
+				}
+			} else if !implemented {
+				return &domain.ValidationError{
+					Field:   "scopes",
+					Message: "Scope '" + scope + "' ainda não está disponível",
+				}
+			}
+			if !hasAction || action == "*" {
+				continue
+			}
+
+			// Ação específica: "cnpj:lookup" (existente e disponível)
+			if p := domain.FindPermission(scope); p != nil {
+				if !p.Available {
+					return &domain.ValidationError{
+						Field:   "scopes",
+						Message: "Scope '" + scope + "' ainda não está disponível",
+					}
+				}
+				continue
+			}
+		}
+
+		// Nível de acesso ("cep:read") ou padrão ("*:lookup", "p*"): precisa conceder ao menos uma permissão
+		if len(domain.PermissionsGranted(scope)) == 0 {
 			return &domain.ValidationError{
 				Field:   "scopes",
-				Message: "Scope '" + scope + "' ainda não está disponível",
+				Message: "Scope '" + scope + "' não corresponde a nenhuma permissão (veja GET /admin/permissions)",
 			}
 		}
 	}
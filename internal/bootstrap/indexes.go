@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -38,10 +40,9 @@ func EnsureIndexes(db *mongo.Database) error {
 		return err
 	}
 
-	// api_keys: TTL
+	// api_keys: expiresAt (sem TTL: keys expiradas continuam visíveis para o erro de expiração e a carência)
 	_, err = db.Collection("api_keys").Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
-		Options: options.Index().SetExpireAfterSeconds(0),
+		Keys: bson.D{{Key: "expiresAt", Value: 1}},
 	})
 	if err != nil {
 		return err
@@ -183,6 +184,65 @@ func CreateIndexes(ctx context.Context, db *mongo.Database, log zerolog.Logger)
 		return err
 	}
 
+	// Municípios: índices por divisão regional (/geo/<nível>/:id/municipios)
+	for nivel, campo := range domain.GeoCampoMunicipio {
+		if err := createIndex("municipios", mongo.IndexModel{
+			Keys: bson.D{{Key: campo, Value: 1}},
+		}, nivel+"_id"); err != nil {
+			return err
+		}
+	}
+
+	// Dados complementares dos municípios: por ID e pelos códigos alternativos
+	if err := createIndex("municipios_dados", mongo.IndexModel{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "id_unique"); err != nil {
+		return err
+	}
+
+	for _, campo := range []string{"siafi", "tse"} {
+		if err := createIndex("municipios_dados", mongo.IndexModel{
+			Keys: bson.D{{Key: campo, Value: 1}},
+		}, campo); err != nil {
+			return err
+		}
+	}
+
+	// Histórico de municípios: por código vigente e pelo código extinto
+	for _, campo := range []string{"municipio", "codigoAnterior"} {
+		if err := createIndex("municipios_alteracoes", mongo.IndexModel{
+			Keys: bson.D{{Key: campo, Value: 1}},
+		}, campo); err != nil {
+			return err
+		}
+	}
+
+	// Divisões regionais pré-agregadas: índice único por ID
+	for _, coll := range storage.GeoDivisoesCollections {
+		if err := createIndex(coll, mongo.IndexModel{
+			Keys:    bson.D{{Key: "id", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		}, "id_unique"); err != nil {
+			return err
+		}
+	}
+
+	// Malhas: uma por município/UF + 2dsphere para o /geo/reverse (point-in-polygon)
+	if err := createIndex("geo_malhas", mongo.IndexModel{
+		Keys:    bson.D{{Key: "tipo", Value: 1}, {Key: "codigo", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "tipo_codigo_unique"); err != nil {
+		return err
+	}
+
+	// Geometria inválida no seed impede o 2dsphere: segue sem ele ($geoIntersects funciona, com varredura)
+	if err := createIndex("geo_malhas", mongo.IndexModel{
+		Keys: bson.D{{Key: "geometria", Value: "2dsphere"}},
+	}, "geometria_2dsphere"); err != nil {
+		log.Warn().Err(err).Msg("geo_malhas sem índice 2dsphere: /geo/reverse fará varredura da collection")
+	}
+
 	// ✅ PERFORMANCE: Índice único para CEP cache (hot path)
 	if err := createIndex("cep_cache", mongo.IndexModel{
 		Keys:    bson.D{{Key: "cep", Value: 1}},
@@ -275,6 +335,150 @@ func CreateIndexes(ctx context.Context, db *mongo.Database, log zerolog.Logger)
 		return err
 	}
 
+	// Estrutura penal: um sumário por legislação
+	if err := createIndex("penal_estrutura", mongo.IndexModel{
+		Keys:    bson.D{{Key: "codigo", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "codigo_unique"); err != nil {
+		return err
+	}
+
+	// Árvores penais: uma por artigo (caput)
+	if err := createIndex("penal_arvores", mongo.IndexModel{
+		Keys:    bson.D{{Key: "idUnico", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "idUnico_unique"); err != nil {
+		return err
+	}
+
+	// Versões penais: uma redação por número de versão
+	if err := createIndex("penal_artigos_versoes", mongo.IndexModel{
+		Keys:    bson.D{{Key: "idUnico", Value: 1}, {Key: "versao", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "idUnico_versao_unique"); err != nil {
+		return err
+	}
+
+	// Referências penais: navegação nos dois sentidos do grafo
+	if err := createIndex("penal_referencias", mongo.IndexModel{
+		Keys: bson.D{{Key: "origem", Value: 1}},
+	}, "origem_1"); err != nil {
+		return err
+	}
+	if err := createIndex("penal_referencias", mongo.IndexModel{
+		Keys: bson.D{{Key: "destino", Value: 1}},
+	}, "destino_1"); err != nil {
+		return err
+	}
+
+	// Bancos: índice único por código COMPE
+	if err := createIndex("bancos", mongo.IndexModel{
+		Keys:    bson.D{{Key: "codigo", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "codigo_unique"); err != nil {
+		return err
+	}
+
+	// Faixas de placas: busca pelo maior início <= prefixo
+	if err := createIndex("placas_faixas", mongo.IndexModel{
+		Keys:    bson.D{{Key: "inicio", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "inicio_unique"); err != nil {
+		return err
+	}
+
+	// NCM: código único + navegação da hierarquia
+	if err := createIndex("ncm", mongo.IndexModel{
+		Keys:    bson.D{{Key: "codigo", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "codigo_unique"); err != nil {
+		return err
+	}
+
+	if err := createIndex("ncm", mongo.IndexModel{
+		Keys: bson.D{{Key: "codigoPai", Value: 1}},
+	}, "codigoPai_1"); err != nil {
+		return err
+	}
+
+	// CEST: busca pelos prefixos do NCM
+	if err := createIndex("cest", mongo.IndexModel{
+		Keys: bson.D{{Key: "ncm", Value: 1}},
+	}, "ncm_1"); err != nil {
+		return err
+	}
+
+	// API keys: o antigo índice TTL em expiresAt apagava as keys ao expirar (sem erro próprio nem carência)
+	apikeysColl := db.Collection("api_keys")
+	if cursor, err := apikeysColl.Indexes().List(ctx); err == nil {
+		var existentes []bson.M
+		if cursor.All(ctx, &existentes) == nil {
+			for _, idx := range existentes {
+				name, _ := idx["name"].(string)
+				if _, ttl := idx["expireAfterSeconds"]; ttl && name != "" {
+					log.Info().Str("index", name).Msg("[index] Removendo índice TTL antigo de api_keys...")
+					if _, err := apikeysColl.Indexes().DropOne(ctx, name); err != nil {
+						log.Warn().Err(err).Str("index", name).Msg("[index] Falha ao remover índice TTL de api_keys")
+					}
+				}
+			}
+		}
+	}
+
+	// API keys: busca por keyId (hot path - AuthAPIKey) e por validade (agendador de avisos)
+	if err := createIndex("api_keys", mongo.IndexModel{
+		Keys: bson.D{{Key: "keyId", Value: 1}},
+	}, "keyId"); err != nil {
+		return err
+	}
+
+	if err := createIndex("api_keys", mongo.IndexModel{
+		Keys: bson.D{{Key: "revoked", Value: 1}, {Key: "expiresAt", Value: 1}},
+	}, "revoked_expiresAt"); err != nil {
+		return err
+	}
+
+	// Usuários por tenant (/me/users) e convites: token (aceite) e em aberto por tenant/email
+	if err := createIndex("users", mongo.IndexModel{
+		Keys: bson.D{{Key: "tenantId", Value: 1}},
+	}, "tenantId"); err != nil {
+		return err
+	}
+
+	if err := createIndex("tenant_invitations", mongo.IndexModel{
+		Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "tokenHash_unique"); err != nil {
+		return err
+	}
+
+	if err := createIndex("tenant_invitations", mongo.IndexModel{
+		Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "email", Value: 1}},
+	}, "tenantId_email"); err != nil {
+		return err
+	}
+
+	// Tokens de redefinição de senha/verificação de email: busca pelo hash, por usuário e limpeza 7 dias após expirar
+	if err := createIndex("user_tokens", mongo.IndexModel{
+		Keys:    bson.D{{Key: "tokenHash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}, "tokenHash_unique"); err != nil {
+		return err
+	}
+
+	if err := createIndex("user_tokens", mongo.IndexModel{
+		Keys: bson.D{{Key: "userId", Value: 1}, {Key: "purpose", Value: 1}, {Key: "createdAt", Value: -1}},
+	}, "userId_purpose_createdAt"); err != nil {
+		return err
+	}
+
+	if err := createIndex("user_tokens", mongo.IndexModel{
+		Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(7 * 24 * 60 * 60),
+	}, "ttl_7days"); err != nil {
+		return err
+	}
+
 	// ✅ PERFORMANCE: Índice para tenant_id (hot path - rate limiting)
 	if err := createIndex("rate_limits", mongo.IndexModel{
 		Keys: bson.D{{Key: "tenantId", Value: 1}, {Key: "resetAt", Value: 1}},
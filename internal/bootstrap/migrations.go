@@ -2,9 +2,8 @@ package bootstrap
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -26,6 +25,10 @@ type Migration struct {
 	Apply       func(ctx context.Context, db *mongo.Database, log zerolog.Logger) error
 }
 
+// errSeedPendente indica que o seed não carregou nada (arquivo ausente): a migration
+// não é registrada e volta a ser tentada no próximo start
+var errSeedPendente = errors.New("seed pendente")
+
 // MigrationRecord registra migrations executadas
 type MigrationRecord struct {
 	Version     string    `bson:"version"`
@@ -61,6 +64,61 @@ func NewMigrationManager(db *mongo.Database, log zerolog.Logger) *MigrationManag
 				Description: "Popular artigos penais brasileiros",
 				Apply:       seedPenal,
 			},
+			{
+				Version:     "004_seed_bancos",
+				Description: "Popular bancos (códigos COMPE)",
+				Apply:       seedBancos,
+			},
+			{
+				Version:     "005_seed_placas_faixas",
+				Description: "Popular faixas de placas (formato antigo) por UF",
+				Apply:       seedPlacasFaixas,
+			},
+			{
+				Version:     "006_seed_ncm",
+				Description: "Popular tabela NCM (Siscomex) e CEST",
+				Apply:       seedNCM,
+			},
+			{
+				Version:     "007_penal_hierarquia",
+				Description: "Reprocessar artigos penais com hierarquia (árvore de cada artigo e sumário por legislação)",
+				Apply:       buildPenalHierarquia,
+			},
+			{
+				Version:     "008_penal_pena_estruturada",
+				Description: "Reprocessar artigos penais com pena estruturada (tipo, mínimo/máximo em dias, multa)",
+				Apply:       buildPenalPenas,
+			},
+			{
+				Version:     "009_penal_versoes",
+				Description: "Versionar artigos penais (redações anteriores e vigência)",
+				Apply:       syncPenalVersoes,
+			},
+			{
+				Version:     "010_penal_referencias",
+				Description: "Extrair referências cruzadas entre artigos penais (grafo de citações)",
+				Apply:       buildPenalReferencias,
+			},
+			{
+				Version:     "011_geo_divisoes",
+				Description: "Pré-agregar regiões, meso/microrregiões e regiões intermediárias/imediatas",
+				Apply:       buildGeoDivisoes,
+			},
+			{
+				Version:     "012_geo_malhas",
+				Description: "Popular malhas (contornos) de municípios e UFs com versões simplificadas",
+				Apply:       seedGeoMalhas,
+			},
+			{
+				Version:     "013_geo_municipios_dados",
+				Description: "Popular dados complementares dos municípios (população, área, SIAFI, TSE, DDD, fuso)",
+				Apply:       seedMunicipiosDados,
+			},
+			{
+				Version:     "014_geo_municipios_alteracoes",
+				Description: "Popular histórico da divisão municipal (criações, renomeações, incorporações)",
+				Apply:       seedMunicipiosAlteracoes,
+			},
 		},
 	}
 }
@@ -86,6 +144,10 @@ func (m *MigrationManager) Run(ctx context.Context) error {
 		start := time.Now()
 
 		if err := migration.Apply(ctx, m.db, m.log); err != nil {
+			if errors.Is(err, errSeedPendente) {
+				m.log.Warn().Msgf("[migration] %s não registrada: %v (será tentada novamente no próximo start)", migration.Version, err)
+				continue
+			}
 			return fmt.Errorf("erro ao aplicar migration %s: %w", migration.Version, err)
 		}
 
@@ -190,6 +252,326 @@ func seedMunicipios(ctx context.Context, db *mongo.Database, log zerolog.Logger)
 	}
 
 	log.Info().Msgf("[seed] %d municípios inseridos com sucesso", len(municipios))
+
+	// Divisões regionais pré-agregadas (regiões, mesorregiões, ...)
+	if err := buildGeoDivisoes(ctx, db, log); err != nil {
+		return fmt.Errorf("erro ao agregar divisões regionais: %w", err)
+	}
+	return nil
+}
+
+// buildGeoDivisoes agrega os municípios cadastrados em uma collection por nível da divisão regional do IBGE
+func buildGeoDivisoes(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	municipios, err := storage.NewMunicipiosRepo(db).FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	if len(municipios) == 0 {
+		log.Warn().Msg("[seed] Nenhum município cadastrado, divisões regionais não agregadas")
+		return nil
+	}
+
+	repo := storage.NewGeoDivisoesRepo(db)
+	divisoes := domain.BuildGeoDivisoes(municipios)
+	for _, nivel := range domain.GeoNiveis {
+		if err := repo.ReplaceAll(ctx, nivel, divisoes[nivel]); err != nil {
+			return fmt.Errorf("erro ao gravar %s: %w", storage.GeoDivisoesCollections[nivel], err)
+		}
+		log.Info().Msgf("[seed] %s: %d divisões", storage.GeoDivisoesCollections[nivel], len(divisoes[nivel]))
+	}
+	return nil
+}
+
+// seedMunicipiosDados popula os dados complementares dos municípios (opcional: sem o arquivo,
+// ?include= retorna o município sem "dados", as buscas por SIAFI/TSE respondem 404 e a migration
+// fica pendente até o arquivo existir)
+func seedMunicipiosDados(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	repo := storage.NewMunicipiosRepo(db)
+
+	// Verifica se já existem dados
+	count, err := repo.CountDados(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Info().Msgf("[seed] Dados complementares de municípios já populados (%d registros), pulando", count)
+		return nil
+	}
+
+	seedFile := findSeedFile("municipios_dados.json")
+	if seedFile == "" {
+		log.Warn().Msg("[seed] Arquivo municipios_dados.json não encontrado, municípios sem dados complementares")
+		return fmt.Errorf("%w: municipios_dados.json não encontrado", errSeedPendente)
+	}
+
+	log.Info().Msgf("[seed] Carregando dados complementares de municípios de: %s", seedFile)
+
+	data, err := os.ReadFile(seedFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo municipios_dados.json: %w", err)
+	}
+
+	var seeds []domain.MunicipioDadosSeed
+	if err := json.Unmarshal(data, &seeds); err != nil {
+		return fmt.Errorf("erro ao fazer parse de municipios_dados.json: %w", err)
+	}
+
+	dados := make([]domain.MunicipioDados, 0, len(seeds))
+	for _, seed := range seeds {
+		if seed.ID == 0 {
+			continue
+		}
+		dados = append(dados, seed.ToDados())
+	}
+
+	if err := repo.UpsertDados(ctx, dados); err != nil {
+		return fmt.Errorf("erro ao gravar dados complementares: %w", err)
+	}
+
+	log.Info().Msgf("[seed] Dados complementares de %d municípios gravados", len(dados))
+	return nil
+}
+
+// seedMunicipiosAlteracoes popula o histórico de alterações da divisão municipal. Sem o arquivo a migration
+// fica pendente (reexecutada no próximo boot); até lá /historico retorna lista vazia e
+// /geo/municipios/legado resolve apenas códigos vigentes
+func seedMunicipiosAlteracoes(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	seedFile := findSeedFile("municipios_alteracoes.json")
+	if seedFile == "" {
+		log.Warn().Msg("[seed] Arquivo municipios_alteracoes.json não encontrado, municípios sem histórico")
+		return fmt.Errorf("%w: municipios_alteracoes.json não encontrado", errSeedPendente)
+	}
+
+	log.Info().Msgf("[seed] Carregando histórico de municípios de: %s", seedFile)
+
+	data, err := os.ReadFile(seedFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo municipios_alteracoes.json: %w", err)
+	}
+
+	var alteracoes []domain.MunicipioAlteracao
+	if err := json.Unmarshal(data, &alteracoes); err != nil {
+		return fmt.Errorf("erro ao fazer parse de municipios_alteracoes.json: %w", err)
+	}
+
+	validas := make([]domain.MunicipioAlteracao, 0, len(alteracoes))
+	for i, a := range alteracoes {
+		if err := a.Validar(); err != nil {
+			log.Warn().Msgf("[seed] municipios_alteracoes.json item %d ignorado: %v", i, err)
+			continue
+		}
+		validas = append(validas, a)
+	}
+
+	if err := storage.NewMunicipiosRepo(db).ReplaceAlteracoes(ctx, validas); err != nil {
+		return fmt.Errorf("erro ao gravar histórico de municípios: %w", err)
+	}
+
+	log.Info().Msgf("[seed] %d alterações de municípios gravadas", len(validas))
+	return nil
+}
+
+// seedGeoMalhas popula os contornos de municípios e UFs (opcional: sem os arquivos, /geo/reverse e
+// /geo/.../geojson respondem 404 e a migration fica pendente até os arquivos existirem).
+// Arquivos no formato da API de malhas do IBGE (FeatureCollection com codarea).
+func seedGeoMalhas(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	repo := storage.NewGeoMalhasRepo(db)
+
+	arquivos := []struct {
+		nome string
+		tipo string
+	}{
+		{"malhas_ufs.json", domain.GeoMalhaUF},
+		{"malhas_municipios.json", domain.GeoMalhaMunicipio},
+	}
+
+	pendentes := []string{}
+	for _, arquivo := range arquivos {
+		// Verifica se já existem malhas do tipo (execução anterior com o arquivo presente)
+		count, err := repo.Count(ctx, arquivo.tipo)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Info().Msgf("[seed] Malhas do tipo %s já populadas (%d registros), pulando", arquivo.tipo, count)
+			continue
+		}
+
+		seedFile := findSeedFile(arquivo.nome)
+		if seedFile == "" {
+			log.Warn().Msgf("[seed] Arquivo %s não encontrado, malhas do tipo %s não carregadas", arquivo.nome, arquivo.tipo)
+			pendentes = append(pendentes, arquivo.nome)
+			continue
+		}
+
+		log.Info().Msgf("[seed] Carregando malhas de: %s", seedFile)
+
+		data, err := os.ReadFile(seedFile)
+		if err != nil {
+			return fmt.Errorf("erro ao ler arquivo %s: %w", arquivo.nome, err)
+		}
+
+		malhas, err := domain.ParseMalhasGeoJSON(data, arquivo.tipo)
+		if err != nil {
+			return fmt.Errorf("erro ao fazer parse de %s: %w", arquivo.nome, err)
+		}
+
+		// Uma a uma: geometria inválida para o índice 2dsphere não impede as demais
+		falhas := 0
+		for _, malha := range malhas {
+			if err := repo.Upsert(ctx, malha); err != nil {
+				falhas++
+				log.Warn().Err(err).Msgf("[seed] Malha %s %d ignorada", malha.Tipo, malha.Codigo)
+			}
+		}
+
+		log.Info().Msgf("[seed] %d malhas (%s) inseridas, %d ignoradas", len(malhas)-falhas, arquivo.tipo, falhas)
+	}
+
+	if len(pendentes) > 0 {
+		return fmt.Errorf("%w: %s não encontrado(s)", errSeedPendente, strings.Join(pendentes, ", "))
+	}
+	return nil
+}
+
+// seedBancos popula os bancos (opcional: sem o arquivo, registra aviso e a migration fica pendente)
+func seedBancos(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	repo := storage.NewBancosRepo(db)
+
+	// Verifica se já existem dados
+	count, err := repo.Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Info().Msgf("[seed] Bancos já populados (%d registros), pulando", count)
+		return nil
+	}
+
+	// Procura o arquivo bancos.json
+	seedFile := findSeedFile("bancos.json")
+	if seedFile == "" {
+		log.Warn().Msg("[seed] Arquivo bancos.json não encontrado, boletos serão decodificados sem nome do banco")
+		return fmt.Errorf("%w: bancos.json não encontrado", errSeedPendente)
+	}
+
+	log.Info().Msgf("[seed] Carregando bancos de: %s", seedFile)
+
+	data, err := os.ReadFile(seedFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo bancos.json: %w", err)
+	}
+
+	var bancos []domain.Banco
+	if err := json.Unmarshal(data, &bancos); err != nil {
+		return fmt.Errorf("erro ao fazer parse de bancos.json: %w", err)
+	}
+
+	if err := repo.InsertMany(ctx, bancos); err != nil {
+		return fmt.Errorf("erro ao inserir bancos: %w", err)
+	}
+
+	log.Info().Msgf("[seed] %d bancos inseridos com sucesso", len(bancos))
+	return nil
+}
+
+// seedPlacasFaixas popula as faixas de placas por UF (opcional: sem o arquivo, registra aviso e a migration fica pendente)
+func seedPlacasFaixas(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	repo := storage.NewPlacasFaixasRepo(db)
+
+	// Verifica se já existem dados
+	count, err := repo.Count(ctx)
+	if err != nil {
+		return err
+	}
+
+	if count > 0 {
+		log.Info().Msgf("[seed] Faixas de placas já populadas (%d registros), pulando", count)
+		return nil
+	}
+
+	// Procura o arquivo placas_faixas.json
+	seedFile := findSeedFile("placas_faixas.json")
+	if seedFile == "" {
+		log.Warn().Msg("[seed] Arquivo placas_faixas.json não encontrado, placas serão validadas sem UF de emissão")
+		return fmt.Errorf("%w: placas_faixas.json não encontrado", errSeedPendente)
+	}
+
+	log.Info().Msgf("[seed] Carregando faixas de placas de: %s", seedFile)
+
+	data, err := os.ReadFile(seedFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo placas_faixas.json: %w", err)
+	}
+
+	var faixas []domain.PlacaFaixa
+	if err := json.Unmarshal(data, &faixas); err != nil {
+		return fmt.Errorf("erro ao fazer parse de placas_faixas.json: %w", err)
+	}
+
+	if err := repo.InsertMany(ctx, faixas); err != nil {
+		return fmt.Errorf("erro ao inserir faixas de placas: %w", err)
+	}
+
+	log.Info().Msgf("[seed] %d faixas de placas inseridas com sucesso", len(faixas))
+	return nil
+}
+
+// seedNCM popula a tabela NCM (formato do Siscomex) e o mapeamento CEST
+// Opcional: se os arquivos não existirem, apenas registra aviso (a tabela pode ser importada via /admin/ncm/reimport)
+func seedNCM(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	repo := storage.NewNCMRepo(db)
+
+	// Procura o arquivo ncm.json
+	seedFile := findSeedFile("ncm.json")
+	if seedFile == "" {
+		log.Warn().Msg("[seed] Arquivo ncm.json não encontrado, tabela NCM ficará vazia até a importação manual")
+		return nil
+	}
+
+	log.Info().Msgf("[seed] Carregando NCM de: %s", seedFile)
+
+	data, err := os.ReadFile(seedFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo ncm.json: %w", err)
+	}
+
+	ncms, err := domain.ParseNCMSiscomex(data)
+	if err != nil {
+		return fmt.Errorf("erro ao fazer parse de ncm.json: %w", err)
+	}
+
+	inserted, updated, _, err := repo.UpsertMany(ctx, ncms)
+	if err != nil {
+		return fmt.Errorf("erro ao inserir NCMs: %w", err)
+	}
+
+	log.Info().Msgf("[seed] NCM: %d inseridos, %d atualizados", inserted, updated)
+
+	// CEST (opcional)
+	cestFile := findSeedFile("cest.json")
+	if cestFile == "" {
+		log.Warn().Msg("[seed] Arquivo cest.json não encontrado, NCMs ficarão sem mapeamento CEST")
+		return nil
+	}
+
+	data, err = os.ReadFile(cestFile)
+	if err != nil {
+		return fmt.Errorf("erro ao ler arquivo cest.json: %w", err)
+	}
+
+	var cests []domain.CEST
+	if err := json.Unmarshal(data, &cests); err != nil {
+		return fmt.Errorf("erro ao fazer parse de cest.json: %w", err)
+	}
+
+	if err := repo.ReplaceCEST(ctx, cests); err != nil {
+		return fmt.Errorf("erro ao inserir CESTs: %w", err)
+	}
+
+	log.Info().Msgf("[seed] %d CESTs inseridos com sucesso", len(cests))
 	return nil
 }
 
@@ -306,9 +688,7 @@ func seedPenal(ctx context.Context, db *mongo.Database, log zerolog.Logger) erro
 		
 		// Gerar hashConteudo se não existir
 		if artigo.HashConteudo == "" {
-			conteudoHash := fmt.Sprintf("%s:%s:%s", artigo.Legislacao, artigo.Codigo, artigo.TextoCompleto)
-			hash := sha256.Sum256([]byte(conteudoHash))
-			artigo.HashConteudo = hex.EncodeToString(hash[:])
+			artigo.HashConteudo = domain.HashConteudoPenal(artigo.Legislacao, artigo.Codigo, artigo.TextoCompleto)
 		}
 		
 		// Validar idUnico antes de fazer upsert
@@ -509,7 +889,348 @@ func seedPenal(ctx context.Context, db *mongo.Database, log zerolog.Logger) erro
 			log.Info().Msgf("[seed] ✅ Todos os %d artigos foram processados com sucesso", finalCount)
 		}
 	}
+
+	// Versões: preserva a redação anterior quando o texto/pena do seed muda
+	if err := syncPenalVersoes(ctx, db, log); err != nil {
+		return fmt.Errorf("erro ao registrar versões dos artigos penais: %w", err)
+	}
+
+	// Hierarquia: nível/pai de cada dispositivo + sumário (títulos, capítulos) por legislação
+	if err := buildPenalHierarquia(ctx, db, log); err != nil {
+		return fmt.Errorf("erro ao montar hierarquia dos artigos penais: %w", err)
+	}
+
+	// Pena estruturada: tipo, mínimo/máximo em dias e multa (dosimetria, prescrição)
+	if err := buildPenalPenas(ctx, db, log); err != nil {
+		return fmt.Errorf("erro ao estruturar penas dos artigos penais: %w", err)
+	}
+
+	// Referências: citações entre dispositivos ("nas penas do art. 121", "arts. 33 a 37 desta Lei")
+	if err := buildPenalReferencias(ctx, db, log); err != nil {
+		return fmt.Errorf("erro ao extrair referências dos artigos penais: %w", err)
+	}
 	
 	return nil
 }
 
+// syncPenalVersoes registra a redação atual de cada artigo em penal_artigos_versoes.
+// Na primeira carga inclui as redações anteriores de penal_versoes.json; depois, quando o conteúdo
+// (texto/pena) muda, encerra a versão vigente na véspera da nova vigência e abre uma nova versão.
+func syncPenalVersoes(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	collection := db.Collection("penal_artigos")
+	versoesColl := db.Collection("penal_artigos_versoes")
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var artigos []domain.ArtigoPenal
+	if err := cursor.All(ctx, &artigos); err != nil {
+		return err
+	}
+
+	// Histórico cadastrado (opcional: sem o arquivo, a versão 1 é a redação atual)
+	seeds := map[string]domain.PenalVersoesSeed{}
+	if seedFile := findSeedFile("penal_versoes.json"); seedFile != "" {
+		data, err := os.ReadFile(seedFile)
+		if err != nil {
+			return fmt.Errorf("erro ao ler arquivo penal_versoes.json: %w", err)
+		}
+		var lista []domain.PenalVersoesSeed
+		if err := json.Unmarshal(data, &lista); err != nil {
+			return fmt.Errorf("erro ao fazer parse de penal_versoes.json: %w", err)
+		}
+		for _, seed := range lista {
+			seeds[seed.IdUnico] = seed
+		}
+	} else {
+		log.Warn().Msg("[seed] Arquivo penal_versoes.json não encontrado, artigos sem redações anteriores")
+	}
+
+	now := time.Now()
+	hoje := now.Format(domain.DataLayout)
+	criadas, alteradas := 0, 0
+	models := make([]mongo.WriteModel, 0, len(artigos))
+
+	for _, artigo := range artigos {
+		seed, temSeed := seeds[artigo.IdUnico]
+		if temSeed {
+			artigo.VigenteDesde = seed.VigenteDesde
+			artigo.LeiAlteradora = seed.LeiAlteradora
+		}
+		atual := domain.NewVersaoFromArtigo(artigo)
+		atual.CreatedAt = now
+
+		var ultima domain.ArtigoPenalVersao
+		err := versoesColl.FindOne(ctx, bson.M{"idUnico": artigo.IdUnico},
+			options.FindOne().SetSort(bson.D{{Key: "versao", Value: -1}})).Decode(&ultima)
+
+		switch {
+		case err == mongo.ErrNoDocuments:
+			// Primeira carga: redações anteriores + redação atual
+			versoes := make([]interface{}, 0, len(seed.Anteriores)+1)
+			for i, v := range seed.Anteriores {
+				v.IdUnico = artigo.IdUnico
+				v.Versao = i + 1
+				v.HashConteudo = domain.HashVersaoPenal(v.Descricao, v.TextoCompleto, v.PenaMin, v.PenaMax)
+				v.CreatedAt = now
+				versoes = append(versoes, v)
+			}
+			atual.Versao = len(versoes) + 1
+			versoes = append(versoes, atual)
+			if _, err := versoesColl.InsertMany(ctx, versoes); err != nil {
+				return fmt.Errorf("erro ao gravar versões de %s: %w", artigo.IdUnico, err)
+			}
+			criadas += len(versoes)
+
+		case err != nil:
+			return err
+
+		case ultima.HashConteudo != atual.HashConteudo:
+			// Redação mudou. Sem data nova no seed, a vigência começa hoje e a lei alteradora fica em aberto
+			if atual.VigenteDesde == "" || atual.VigenteDesde <= ultima.VigenteDesde {
+				atual.VigenteDesde = hoje
+				if atual.LeiAlteradora == ultima.LeiAlteradora {
+					atual.LeiAlteradora = ""
+				}
+			}
+			if _, err := versoesColl.UpdateOne(ctx, bson.M{"idUnico": ultima.IdUnico, "versao": ultima.Versao},
+				bson.M{"$set": bson.M{"vigenteAte": domain.DiaAnterior(atual.VigenteDesde)}}); err != nil {
+				return fmt.Errorf("erro ao encerrar versão de %s: %w", artigo.IdUnico, err)
+			}
+			atual.Versao = ultima.Versao + 1
+			if _, err := versoesColl.InsertOne(ctx, atual); err != nil {
+				return fmt.Errorf("erro ao gravar versão de %s: %w", artigo.IdUnico, err)
+			}
+			alteradas++
+
+		default:
+			// Mesma redação: só atualiza a vigência se o seed passou a informá-la
+			if temSeed && (ultima.VigenteDesde != seed.VigenteDesde || ultima.LeiAlteradora != seed.LeiAlteradora) {
+				if _, err := versoesColl.UpdateOne(ctx, bson.M{"idUnico": ultima.IdUnico, "versao": ultima.Versao},
+					bson.M{"$set": bson.M{"vigenteDesde": seed.VigenteDesde, "leiAlteradora": seed.LeiAlteradora}}); err != nil {
+					return fmt.Errorf("erro ao atualizar vigência de %s: %w", artigo.IdUnico, err)
+				}
+				ultima.VigenteDesde, ultima.LeiAlteradora = seed.VigenteDesde, seed.LeiAlteradora
+			}
+			atual = ultima
+		}
+
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"idUnico": artigo.IdUnico}).
+			SetUpdate(bson.M{"$set": bson.M{
+				"vigenteDesde":  atual.VigenteDesde,
+				"leiAlteradora": atual.LeiAlteradora,
+				"versao":        atual.Versao,
+			}}))
+	}
+
+	if len(models) > 0 {
+		if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("[seed] Versões penais: %d versões criadas, %d artigos com nova redação", criadas, alteradas)
+	return nil
+}
+
+// buildPenalHierarquia calcula nível, pai e localização (parte/título/capítulo) de cada artigo,
+// grava o sumário de cada legislação em penal_estrutura e a árvore de cada artigo em penal_arvores
+func buildPenalHierarquia(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	collection := db.Collection("penal_artigos")
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var artigos []domain.ArtigoPenal
+	if err := cursor.All(ctx, &artigos); err != nil {
+		return err
+	}
+
+	// Divisões cadastradas (opcional: sem o arquivo, o sumário lista os artigos sem títulos/capítulos)
+	divisoesPorLegislacao := map[string][]domain.PenalDivisao{}
+	if seedFile := findSeedFile("penal_estrutura.json"); seedFile != "" {
+		data, err := os.ReadFile(seedFile)
+		if err != nil {
+			return fmt.Errorf("erro ao ler arquivo penal_estrutura.json: %w", err)
+		}
+		var seeds []domain.PenalEstruturaSeed
+		if err := json.Unmarshal(data, &seeds); err != nil {
+			return fmt.Errorf("erro ao fazer parse de penal_estrutura.json: %w", err)
+		}
+		for _, seed := range seeds {
+			divisoesPorLegislacao[seed.Legislacao] = seed.Divisoes
+		}
+	} else {
+		log.Warn().Msg("[seed] Arquivo penal_estrutura.json não encontrado, sumários sem títulos/capítulos")
+	}
+
+	// Agrupar por código curto da legislação (prefixo do idUnico)
+	porLegislacao := map[string][]domain.ArtigoPenal{}
+	existentes := map[string]bool{}
+	for _, artigo := range artigos {
+		parts := strings.SplitN(artigo.IdUnico, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		porLegislacao[parts[0]] = append(porLegislacao[parts[0]], artigo)
+		existentes[artigo.IdUnico] = true
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(artigos))
+	arvoreModels := []mongo.WriteModel{}
+	estruturaColl := db.Collection("penal_estrutura")
+
+	for legCode, lista := range porLegislacao {
+		divisoes, ok := divisoesPorLegislacao[legCode]
+		if !ok && len(lista) > 0 {
+			divisoes = divisoesPorLegislacao[lista[0].Legislacao]
+		}
+
+		for _, artigo := range lista {
+			// Pai = dispositivo superior mais próximo que exista na base
+			codigoPai := ""
+			for pai := domain.PenalCodigoPai(artigo.Codigo); pai != ""; pai = domain.PenalCodigoPai(pai) {
+				if id := legCode + ":" + pai; existentes[id] {
+					codigoPai = id
+					break
+				}
+			}
+
+			models = append(models, mongo.NewUpdateOneModel().
+				SetFilter(bson.M{"idUnico": artigo.IdUnico}).
+				SetUpdate(bson.M{"$set": bson.M{
+					"nivel":       domain.PenalNivel(artigo.Codigo),
+					"codigoPai":   codigoPai,
+					"ordem":       domain.PenalOrdem(artigo.Codigo),
+					"localizacao": domain.LocalizarArtigo(divisoes, domain.PenalArtigoBase(artigo.Codigo)),
+				}}))
+		}
+
+		// Árvore de cada artigo (caput + dispositivos), servida por GET /penal/artigos/:codigo/arvore
+		porArtigo := map[string][]domain.ArtigoPenal{}
+		for _, artigo := range lista {
+			base := domain.PenalArtigoBase(artigo.Codigo)
+			porArtigo[base] = append(porArtigo[base], artigo)
+		}
+		for base, dispositivos := range porArtigo {
+			arvore := domain.PenalArvore{
+				IdUnico:        legCode + ":" + base,
+				Legislacao:     dispositivos[0].Legislacao,
+				LegislacaoNome: dispositivos[0].LegislacaoNome,
+				Localizacao:    domain.LocalizarArtigo(divisoes, base),
+				Arvore:         domain.BuildPenalArvore(dispositivos),
+				UpdatedAt:      now,
+			}
+			arvoreModels = append(arvoreModels, mongo.NewReplaceOneModel().
+				SetFilter(bson.M{"idUnico": arvore.IdUnico}).
+				SetReplacement(arvore).
+				SetUpsert(true))
+		}
+
+		estrutura := domain.BuildPenalEstrutura(legCode, lista, divisoes)
+		estrutura.UpdatedAt = now
+		if _, err := estruturaColl.ReplaceOne(ctx, bson.M{"codigo": legCode}, estrutura, options.Replace().SetUpsert(true)); err != nil {
+			return fmt.Errorf("erro ao gravar estrutura de %s: %w", legCode, err)
+		}
+	}
+
+	if len(models) > 0 {
+		if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+	if len(arvoreModels) > 0 {
+		if _, err := db.Collection("penal_arvores").BulkWrite(ctx, arvoreModels, options.BulkWrite().SetOrdered(false)); err != nil {
+			return fmt.Errorf("erro ao gravar árvores dos artigos: %w", err)
+		}
+	}
+
+	log.Info().Msgf("[seed] Hierarquia penal montada: %d dispositivos, %d árvores em %d legislações", len(models), len(arvoreModels), len(porLegislacao))
+	return nil
+}
+
+// buildPenalPenas grava a pena estruturada (tipo, mínimo/máximo em dias, multa) de cada artigo
+func buildPenalPenas(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	collection := db.Collection("penal_artigos")
+
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"idUnico": 1, "penaMin": 1, "penaMax": 1}))
+	if err != nil {
+		return err
+	}
+	var artigos []domain.ArtigoPenal
+	if err := cursor.All(ctx, &artigos); err != nil {
+		return err
+	}
+
+	models := make([]mongo.WriteModel, 0, len(artigos))
+	for _, artigo := range artigos {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"idUnico": artigo.IdUnico}).
+			SetUpdate(bson.M{"$set": bson.M{"pena": domain.ParsePena(artigo.PenaMin, artigo.PenaMax)}}))
+	}
+	if len(models) > 0 {
+		if _, err := collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("[seed] Penas estruturadas: %d dispositivos", len(models))
+	return nil
+}
+
+
+// buildPenalReferencias extrai as citações do texto de cada artigo e regrava as arestas
+// na collection penal_referencias (origem → destino)
+func buildPenalReferencias(ctx context.Context, db *mongo.Database, log zerolog.Logger) error {
+	cursor, err := db.Collection("penal_artigos").Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var artigos []domain.ArtigoPenal
+	if err := cursor.All(ctx, &artigos); err != nil {
+		return err
+	}
+
+	existentes := map[string]bool{}
+	for _, artigo := range artigos {
+		existentes[artigo.IdUnico] = true
+	}
+
+	now := time.Now()
+	docs := []interface{}{}
+	naoCadastradas := 0
+	for _, artigo := range artigos {
+		parts := strings.SplitN(artigo.IdUnico, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		for _, ref := range domain.ResolverReferencias(domain.ExtrairReferencias(artigo, parts[0]), existentes) {
+			if ref.Destino == artigo.IdUnico {
+				continue // Citação a um parágrafo/inciso próprio ainda não cadastrado
+			}
+			if !ref.Existe {
+				naoCadastradas++
+			}
+			ref.CreatedAt = now
+			docs = append(docs, ref)
+		}
+	}
+
+	// Arestas são derivadas do texto: regravar tudo mantém o grafo consistente com o seed
+	refsColl := db.Collection("penal_referencias")
+	if _, err := refsColl.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	if len(docs) > 0 {
+		if _, err := refsColl.InsertMany(ctx, docs); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msgf("[seed] Referências penais: %d citações (%d a dispositivos não cadastrados)", len(docs), naoCadastradas)
+	return nil
+}
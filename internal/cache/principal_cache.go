@@ -0,0 +1,324 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/theretech/retech-core/internal/domain"
+)
+
+const (
+	principalRedisPrefix       = "principal:"
+	principalInvalidateChannel = "principal:invalidate" // Mensagens: key:<keyId>, tenant:<tenantId>, all
+)
+
+// Origem de um principal encontrado no cache
+const (
+	PrincipalSourceLocal = "local"
+	PrincipalSourceRedis = "redis"
+)
+
+// PrincipalCacheStats contadores do cache de principals
+type PrincipalCacheStats struct {
+	LocalHits     int64   `json:"localHits"`
+	RedisHits     int64   `json:"redisHits"`
+	Misses        int64   `json:"misses"`
+	Invalidations int64   `json:"invalidations"`
+	HitRate       float64 `json:"hitRate"`
+	Size          int     `json:"size"`
+	Capacity      int     `json:"capacity"`
+	Redis         bool    `json:"redis"`
+}
+
+type principalEntry struct {
+	keyID     string
+	principal *domain.Principal
+	expiresAt time.Time
+}
+
+// PrincipalCache LRU em memória (TTL curto) + Redis (compartilhado entre instâncias).
+// Invalidações apagam as duas camadas e são publicadas via pub/sub para as demais instâncias.
+//
+// Cada invalidação (local ou recebida via pub/sub) incrementa a geração: Set/SetLocal recebem a geração
+// lida antes da carga e descartam o principal se houve invalidação no meio (evita regravar dado defasado).
+type PrincipalCache struct {
+	mu       sync.Mutex
+	items    map[string]*list.Element
+	order    *list.List // Mais recente na frente
+	capacity int
+	localTTL time.Duration
+	redisTTL time.Duration
+	redis    *RedisClient
+
+	generation atomic.Uint64
+	genMu      sync.RWMutex // Set (leitura) x invalidação (escrita): a geração não muda durante uma gravação
+
+	localHits     atomic.Int64
+	redisHits     atomic.Int64
+	misses        atomic.Int64
+	invalidations atomic.Int64
+}
+
+// NewPrincipalCache redisClient pode ser nil (apenas LRU local)
+func NewPrincipalCache(redisClient interface{}, capacity int, localTTL, redisTTL time.Duration) *PrincipalCache {
+	pc := &PrincipalCache{
+		items:    make(map[string]*list.Element, capacity),
+		order:    list.New(),
+		capacity: capacity,
+		localTTL: localTTL,
+		redisTTL: redisTTL,
+	}
+	if rc, ok := redisClient.(*RedisClient); ok {
+		pc.redis = rc
+	}
+	return pc
+}
+
+// Generation geração atual (ler antes de Get/carga e repassar a Set/SetLocal)
+func (pc *PrincipalCache) Generation() uint64 {
+	return pc.generation.Load()
+}
+
+// Get busca o principal da key (LRU → Redis); source vazio = não encontrado.
+// Do Redis o principal vem sem Key.KeyHash (não é serializado): a key é conferida pelo KeyVerifier.
+func (pc *PrincipalCache) Get(ctx context.Context, keyID string) (*domain.Principal, string) {
+	if p := pc.getLocal(keyID); p != nil {
+		pc.localHits.Add(1)
+		return p, PrincipalSourceLocal
+	}
+
+	if pc.redis != nil {
+		if cached, err := pc.redis.Get(ctx, principalRedisPrefix+keyID); err == nil && cached != "" {
+			var p domain.Principal
+			if json.Unmarshal([]byte(cached), &p) == nil {
+				pc.redisHits.Add(1)
+				return &p, PrincipalSourceRedis
+			}
+		}
+	}
+
+	pc.misses.Add(1)
+	return nil, ""
+}
+
+// Set grava o principal nas duas camadas (no Redis sem Key.KeyHash); false = houve invalidação
+// desde generation e o principal foi descartado
+func (pc *PrincipalCache) Set(ctx context.Context, p *domain.Principal, generation uint64) bool {
+	pc.genMu.RLock()
+	defer pc.genMu.RUnlock()
+
+	if pc.generation.Load() != generation {
+		return false
+	}
+	pc.setLocal(p.Key.KeyID, p)
+	if pc.redis != nil {
+		_ = pc.redis.Set(ctx, principalRedisPrefix+p.Key.KeyID, p, pc.redisTTL)
+	}
+	return true
+}
+
+// SetLocal grava o principal apenas no LRU (ex: vindo do Redis); false = houve invalidação desde generation
+func (pc *PrincipalCache) SetLocal(p *domain.Principal, generation uint64) bool {
+	pc.genMu.RLock()
+	defer pc.genMu.RUnlock()
+
+	if pc.generation.Load() != generation {
+		return false
+	}
+	pc.setLocal(p.Key.KeyID, p)
+	return true
+}
+
+// Invalidate remove as keys (revogação, rotação, alteração de validade/restrições)
+func (pc *PrincipalCache) Invalidate(ctx context.Context, keyIDs ...string) {
+	if len(keyIDs) == 0 {
+		return
+	}
+	pc.deleteKeys(ctx, keyIDs...)
+	pc.invalidations.Add(int64(len(keyIDs)))
+
+	if pc.redis != nil {
+		for _, keyID := range keyIDs {
+			_ = pc.redis.Publish(ctx, principalInvalidateChannel, "key:"+keyID)
+		}
+	}
+}
+
+// InvalidateTenant remove os principals do tenant (keyIDs: keys do tenant, para limpar o Redis)
+func (pc *PrincipalCache) InvalidateTenant(ctx context.Context, tenantID string, keyIDs []string) {
+	pc.deleteTenant(tenantID)
+	pc.deleteKeys(ctx, keyIDs...)
+	pc.invalidations.Add(1)
+
+	if pc.redis != nil {
+		for _, keyID := range keyIDs {
+			_ = pc.redis.Publish(ctx, principalInvalidateChannel, "key:"+keyID)
+		}
+		_ = pc.redis.Publish(ctx, principalInvalidateChannel, "tenant:"+tenantID)
+	}
+}
+
+// InvalidateAll limpa o cache inteiro (ex: limites padrão alterados nas configurações)
+func (pc *PrincipalCache) InvalidateAll(ctx context.Context) {
+	pc.deleteAll(ctx)
+	pc.invalidations.Add(1)
+
+	if pc.redis != nil {
+		_ = pc.redis.Publish(ctx, principalInvalidateChannel, "all")
+	}
+}
+
+// Listen aplica no LRU local as invalidações publicadas por outras instâncias (sem Redis, não faz nada).
+// Keys e "all" também são apagados do Redis de novo: uma carga desta instância iniciada antes da
+// invalidação pode ter regravado o principal depois da remoção feita pela instância de origem.
+func (pc *PrincipalCache) Listen(ctx context.Context) {
+	if pc.redis == nil {
+		return
+	}
+	go func() {
+		for msg := range pc.redis.Subscribe(ctx, principalInvalidateChannel) {
+			tipo, id, _ := strings.Cut(msg, ":")
+			switch tipo {
+			case "key":
+				pc.deleteKeys(ctx, id)
+			case "tenant":
+				pc.deleteTenant(id)
+			case "all":
+				pc.deleteAll(ctx)
+			}
+		}
+	}()
+}
+
+// Stats contadores de acerto/erro (para medir o ganho no hot path)
+func (pc *PrincipalCache) Stats() PrincipalCacheStats {
+	pc.mu.Lock()
+	size := pc.order.Len()
+	pc.mu.Unlock()
+
+	stats := PrincipalCacheStats{
+		LocalHits:     pc.localHits.Load(),
+		RedisHits:     pc.redisHits.Load(),
+		Misses:        pc.misses.Load(),
+		Invalidations: pc.invalidations.Load(),
+		Size:          size,
+		Capacity:      pc.capacity,
+		Redis:         pc.redis != nil,
+	}
+	if total := stats.LocalHits + stats.RedisHits + stats.Misses; total > 0 {
+		stats.HitRate = float64(stats.LocalHits+stats.RedisHits) / float64(total)
+	}
+	return stats
+}
+
+// deleteKeys remove as keys do LRU e do Redis, avançando a geração (sem Set concorrente no meio)
+func (pc *PrincipalCache) deleteKeys(ctx context.Context, keyIDs ...string) {
+	if len(keyIDs) == 0 {
+		return
+	}
+	pc.genMu.Lock()
+	defer pc.genMu.Unlock()
+
+	pc.generation.Add(1)
+	redisKeys := make([]string, len(keyIDs))
+	for i, keyID := range keyIDs {
+		pc.deleteLocal(keyID)
+		redisKeys[i] = principalRedisPrefix + keyID
+	}
+	if pc.redis != nil {
+		_ = pc.redis.Delete(ctx, redisKeys...)
+	}
+}
+
+// deleteAll limpa o LRU e o Redis, avançando a geração
+func (pc *PrincipalCache) deleteAll(ctx context.Context) {
+	pc.genMu.Lock()
+	defer pc.genMu.Unlock()
+
+	pc.generation.Add(1)
+	pc.clearLocal()
+	if pc.redis != nil {
+		_ = pc.redis.FlushPattern(ctx, principalRedisPrefix+"*")
+	}
+}
+
+// deleteTenant remove os principals do tenant do LRU, avançando a geração
+func (pc *PrincipalCache) deleteTenant(tenantID string) {
+	pc.genMu.Lock()
+	defer pc.genMu.Unlock()
+
+	pc.generation.Add(1)
+	pc.deleteLocalTenant(tenantID)
+}
+
+func (pc *PrincipalCache) getLocal(keyID string) *domain.Principal {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	el, ok := pc.items[keyID]
+	if !ok {
+		return nil
+	}
+	entry := el.Value.(*principalEntry)
+	if time.Now().After(entry.expiresAt) {
+		pc.order.Remove(el)
+		delete(pc.items, keyID)
+		return nil
+	}
+	pc.order.MoveToFront(el)
+	return entry.principal
+}
+
+func (pc *PrincipalCache) setLocal(keyID string, p *domain.Principal) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	entry := &principalEntry{keyID: keyID, principal: p, expiresAt: time.Now().Add(pc.localTTL)}
+	if el, ok := pc.items[keyID]; ok {
+		el.Value = entry
+		pc.order.MoveToFront(el)
+		return
+	}
+	pc.items[keyID] = pc.order.PushFront(entry)
+
+	for pc.order.Len() > pc.capacity {
+		oldest := pc.order.Back()
+		pc.order.Remove(oldest)
+		delete(pc.items, oldest.Value.(*principalEntry).keyID)
+	}
+}
+
+func (pc *PrincipalCache) deleteLocal(keyID string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	if el, ok := pc.items[keyID]; ok {
+		pc.order.Remove(el)
+		delete(pc.items, keyID)
+	}
+}
+
+func (pc *PrincipalCache) deleteLocalTenant(tenantID string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	for keyID, el := range pc.items {
+		if el.Value.(*principalEntry).principal.TenantID == tenantID {
+			pc.order.Remove(el)
+			delete(pc.items, keyID)
+		}
+	}
+}
+
+func (pc *PrincipalCache) clearLocal() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	pc.items = make(map[string]*list.Element, pc.capacity)
+	pc.order.Init()
+}
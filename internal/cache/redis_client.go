@@ -138,6 +138,38 @@ func (r *RedisClient) FlushPattern(ctx context.Context, pattern string) error {
 	return nil
 }
 
+// Publish publica uma mensagem no canal (pub/sub)
+func (r *RedisClient) Publish(ctx context.Context, channel, message string) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe assina o canal e entrega as mensagens até o contexto ser cancelado
+func (r *RedisClient) Subscribe(ctx context.Context, channel string) <-chan string {
+	out := make(chan string, 64)
+	sub := r.client.Subscribe(ctx, channel)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		ch := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case out <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out
+}
+
 // Exists verifica se uma chave existe
 func (r *RedisClient) Exists(ctx context.Context, key string) (bool, error) {
 	val, err := r.client.Exists(ctx, key).Result()
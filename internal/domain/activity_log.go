@@ -45,20 +45,42 @@ const (
 	ActivityTypeTenantDeleted     = "tenant.deleted"
 	ActivityTypeTenantActivated   = "tenant.activated"
 	ActivityTypeTenantDeactivated = "tenant.deactivated"
+	ActivityTypeTenantTransferred = "tenant.ownership_transferred"
 
 	// API Key events
-	ActivityTypeAPIKeyCreated = "apikey.created"
-	ActivityTypeAPIKeyRevoked = "apikey.revoked"
-	ActivityTypeAPIKeyRotated = "apikey.rotated"
+	ActivityTypeAPIKeyCreated  = "apikey.created"
+	ActivityTypeAPIKeyRevoked  = "apikey.revoked"
+	ActivityTypeAPIKeyRotated  = "apikey.rotated"
+	ActivityTypeAPIKeyExtended = "apikey.extended"
+	ActivityTypeAPIKeyUpdated  = "apikey.updated"
+	ActivityTypeAPIKeyExpiring = "apikey.expiring" // Aviso do agendador (expira em breve)
+	ActivityTypeAPIKeyExpired  = "apikey.expired"  // Aviso do agendador (expirou)
 
 	// Settings events
 	ActivityTypeSettingsUpdated = "settings.updated"
 
 	// User events
 	ActivityTypeUserCreated = "user.created"
+	ActivityTypeUserUpdated = "user.updated" // Papel no tenant alterado
+	ActivityTypeUserRemoved = "user.removed" // Removido do tenant
 	ActivityTypeUserLogin   = "user.login"
 	ActivityTypeUserLogout  = "user.logout"
 
+	// Account events (senha e verificação de email)
+	ActivityTypePasswordResetRequested = "user.password_reset_requested"
+	ActivityTypePasswordReset          = "user.password_reset"
+	ActivityTypePasswordChanged        = "user.password_changed"
+	ActivityTypeEmailVerificationSent  = "user.email_verification_sent"
+	ActivityTypeEmailVerified          = "user.email_verified"
+
+	// Invitation events (convites para o tenant)
+	ActivityTypeInvitationCreated  = "invitation.created"
+	ActivityTypeInvitationRevoked  = "invitation.revoked"
+	ActivityTypeInvitationAccepted = "invitation.accepted"
+
+	// Dataset events
+	ActivityTypeNCMReimported = "ncm.reimported"
+
 	// System events
 	ActivityTypeSystemStartup  = "system.startup"
 	ActivityTypeSystemShutdown = "system.shutdown"
@@ -66,11 +88,12 @@ const (
 
 // ResourceType constantes para tipos de recursos
 const (
-	ResourceTypeTenant   = "tenant"
-	ResourceTypeAPIKey   = "apikey"
-	ResourceTypeSettings = "settings"
-	ResourceTypeUser     = "user"
-	ResourceTypeSystem   = "system"
+	ResourceTypeTenant     = "tenant"
+	ResourceTypeAPIKey     = "apikey"
+	ResourceTypeSettings   = "settings"
+	ResourceTypeUser       = "user"
+	ResourceTypeInvitation = "invitation"
+	ResourceTypeSystem     = "system"
 )
 
 // Action constantes para ações
@@ -82,6 +105,13 @@ const (
 	ActionDeactivate = "deactivate"
 	ActionRevoke     = "revoke"
 	ActionRotate     = "rotate"
+	ActionExtend     = "extend"
+	ActionNotify     = "notify"
+	ActionInvite     = "invite"
+	ActionAccept     = "accept"
+	ActionTransfer   = "transfer"
+	ActionRequest    = "request"
+	ActionVerify     = "verify"
 	ActionLogin      = "login"
 	ActionLogout     = "logout"
 	ActionStartup    = "startup"
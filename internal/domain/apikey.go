@@ -1,15 +1,89 @@
 package domain
 
-import "time"
+import (
+	"os"
+	"strconv"
+	"time"
+)
 
 type APIKey struct {
 	ID        string    `bson:"_id,omitempty"`
 	KeyID     string    `bson:"keyId"`       // público
-	KeyHash   string    `bson:"keyHash"`     // HMAC(keyId.keySecret)
+	KeyHash   string    `bson:"keyHash" json:"-"` // HMAC(keyId.keySecret); fora do JSON (não vai para o cache Redis)
 	Scopes    []string  `bson:"scopes"`
 	OwnerID   string    `bson:"ownerId"`
-	ExpiresAt time.Time `bson:"expiresAt"`   // TTL index
+	ExpiresAt time.Time `bson:"expiresAt"`   // Verificado no AuthAPIKey (com período de carência)
 	Revoked   bool      `bson:"revoked"`
 	CreatedAt time.Time `bson:"createdAt"`
+
+	// Restrições de uso (IP, origem e user agent), conferidas no AuthAPIKey
+	Restrictions *APIKeyRestrictions `bson:"restrictions,omitempty"`
+
+	// Avisos de expiração já enviados (zerados ao estender a key)
+	ExpiringNotifiedAt *time.Time `bson:"expiringNotifiedAt,omitempty"`
+	ExpiredNotifiedAt  *time.Time `bson:"expiredNotifiedAt,omitempty"`
+}
+
+// Status de uma API key em relação à expiração
+const (
+	APIKeyStatusActive   = "active"
+	APIKeyStatusExpiring = "expiring" // Expira dentro da janela de aviso
+	APIKeyStatusGrace    = "grace"    // Expirada, mas ainda aceita no período de carência
+	APIKeyStatusExpired  = "expired"
+	APIKeyStatusRevoked  = "revoked"
+)
+
+// APIKeyExpiryPolicy política de expiração das API keys
+type APIKeyExpiryPolicy struct {
+	Grace  time.Duration // Tempo após ExpiresAt em que a key continua aceita (com aviso)
+	Notice time.Duration // Antecedência do aviso "expira em breve"
+	MaxTTL time.Duration // Validade máxima a partir de agora ao estender uma key
+}
+
+// APIKeyExpiryPolicyFromEnv lê APIKEY_GRACE_HOURS (0), APIKEY_EXPIRY_NOTICE_DAYS (7) e APIKEY_MAX_TTL_DAYS (365)
+func APIKeyExpiryPolicyFromEnv() APIKeyExpiryPolicy {
+	return APIKeyExpiryPolicy{
+		Grace:  time.Duration(envIntDomain("APIKEY_GRACE_HOURS", 0)) * time.Hour,
+		Notice: time.Duration(envIntDomain("APIKEY_EXPIRY_NOTICE_DAYS", 7)) * 24 * time.Hour,
+		MaxTTL: time.Duration(envIntDomain("APIKEY_MAX_TTL_DAYS", 365)) * 24 * time.Hour,
+	}
 }
 
+// GraceUntil momento a partir do qual a key deixa de ser aceita
+func (k *APIKey) GraceUntil(p APIKeyExpiryPolicy) time.Time {
+	return k.ExpiresAt.Add(p.Grace)
+}
+
+// Status situação da key no instante informado
+func (k *APIKey) Status(now time.Time, p APIKeyExpiryPolicy) string {
+	switch {
+	case k.Revoked:
+		return APIKeyStatusRevoked
+	case k.ExpiresAt.IsZero(): // Keys antigas sem validade
+		return APIKeyStatusActive
+	case !now.Before(k.GraceUntil(p)):
+		return APIKeyStatusExpired
+	case !now.Before(k.ExpiresAt):
+		return APIKeyStatusGrace
+	case k.ExpiresAt.Sub(now) <= p.Notice:
+		return APIKeyStatusExpiring
+	}
+	return APIKeyStatusActive
+}
+
+// CheckExpiry retorna *APIKeyExpiredError se a key expirou e já passou do período de carência
+func (k *APIKey) CheckExpiry(now time.Time, p APIKeyExpiryPolicy) error {
+	if k.Status(now, p) == APIKeyStatusExpired {
+		return &APIKeyExpiredError{KeyID: k.KeyID, ExpiresAt: k.ExpiresAt, GraceUntil: k.GraceUntil(p)}
+	}
+	return nil
+}
+
+func envIntDomain(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			return n
+		}
+	}
+	return def
+}
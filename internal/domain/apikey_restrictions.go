@@ -0,0 +1,253 @@
+package domain
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+)
+
+// Limites das listas de restrição (por lista)
+const APIKeyRestrictionsMax = 50
+
+// APIKeyRestrictions restrições opcionais de uso de uma API key (listas vazias = sem restrição)
+type APIKeyRestrictions struct {
+	AllowedIPs        []string `bson:"allowedIps,omitempty" json:"allowedIps,omitempty"`               // IPs ou CIDRs (203.0.113.0/24, 2001:db8::/32)
+	AllowedOrigins    []string `bson:"allowedOrigins,omitempty" json:"allowedOrigins,omitempty"`       // Origin/Referer: https://app.exemplo.com.br, *.exemplo.com.br, localhost:*
+	AllowedUserAgents []string `bson:"allowedUserAgents,omitempty" json:"allowedUserAgents,omitempty"` // Padrões com * (case-insensitive): MeuApp/*
+}
+
+// Violações de restrição (usadas no type do problem+json)
+const (
+	APIKeyRestrictionIP        = "ip-not-allowed"
+	APIKeyRestrictionOrigin    = "origin-not-allowed"
+	APIKeyRestrictionUserAgent = "user-agent-not-allowed"
+)
+
+// APIKeyRestrictionError requisição fora das restrições da key
+type APIKeyRestrictionError struct {
+	Code    string // ip-not-allowed | origin-not-allowed | user-agent-not-allowed
+	Value   string // Valor recebido (IP, origem ou user agent)
+	Message string
+}
+
+func (e *APIKeyRestrictionError) Error() string {
+	return e.Message
+}
+
+// Empty indica que nenhuma restrição está configurada
+func (r *APIKeyRestrictions) Empty() bool {
+	return r == nil || (len(r.AllowedIPs) == 0 && len(r.AllowedOrigins) == 0 && len(r.AllowedUserAgents) == 0)
+}
+
+// Normalize valida as listas, converte IPs soltos em CIDR (/32 ou /128) e remove duplicatas
+func (r *APIKeyRestrictions) Normalize() error {
+	if r == nil {
+		return nil
+	}
+
+	ips, err := normalizarLista("allowedIps", r.AllowedIPs, func(s string) (string, error) {
+		if !strings.Contains(s, "/") {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return "", &ValidationError{Field: "allowedIps", Message: "IP inválido: " + s}
+			}
+			if ip.To4() != nil {
+				return ip.String() + "/32", nil
+			}
+			return ip.String() + "/128", nil
+		}
+		_, rede, err := net.ParseCIDR(s)
+		if err != nil {
+			return "", &ValidationError{Field: "allowedIps", Message: "CIDR inválido: " + s}
+		}
+		return rede.String(), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	origins, err := normalizarLista("allowedOrigins", r.AllowedOrigins, func(s string) (string, error) {
+		s = strings.TrimSuffix(strings.ToLower(s), "/")
+		scheme, host := splitOrigemPadrao(s)
+		if host == "" || strings.ContainsAny(host, "/?# ") || (scheme != "" && scheme != "http" && scheme != "https") {
+			return "", &ValidationError{Field: "allowedOrigins", Message: "Origem inválida: " + s + " (ex: https://app.exemplo.com.br, *.exemplo.com.br)"}
+		}
+		return s, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	agents, err := normalizarLista("allowedUserAgents", r.AllowedUserAgents, func(s string) (string, error) {
+		return s, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.AllowedIPs, r.AllowedOrigins, r.AllowedUserAgents = ips, origins, agents
+	return nil
+}
+
+// Check confere IP, origem (Origin ou, na ausência, Referer) e user agent da requisição
+func (r *APIKeyRestrictions) Check(ip, origin, referer, userAgent string) *APIKeyRestrictionError {
+	if r.Empty() {
+		return nil
+	}
+
+	if len(r.AllowedIPs) > 0 && !ipPermitido(r.AllowedIPs, ip) {
+		return &APIKeyRestrictionError{
+			Code:    APIKeyRestrictionIP,
+			Value:   ip,
+			Message: "IP " + ip + " não está na lista de IPs permitidos desta API key",
+		}
+	}
+
+	if len(r.AllowedOrigins) > 0 {
+		origem := origemRequisicao(origin, referer)
+		if origem == "" {
+			return &APIKeyRestrictionError{
+				Code:    APIKeyRestrictionOrigin,
+				Message: "Esta API key só pode ser usada a partir de sites autorizados (header Origin ou Referer ausente)",
+			}
+		}
+		if !origemPermitida(r.AllowedOrigins, origem) {
+			return &APIKeyRestrictionError{
+				Code:    APIKeyRestrictionOrigin,
+				Value:   origem,
+				Message: "Origem " + origem + " não está autorizada a usar esta API key",
+			}
+		}
+	}
+
+	if len(r.AllowedUserAgents) > 0 {
+		permitido := false
+		for _, padrao := range r.AllowedUserAgents {
+			if wildcardMatch(strings.ToLower(padrao), strings.ToLower(userAgent)) {
+				permitido = true
+				break
+			}
+		}
+		if !permitido {
+			return &APIKeyRestrictionError{
+				Code:    APIKeyRestrictionUserAgent,
+				Value:   userAgent,
+				Message: "User-Agent não autorizado para esta API key",
+			}
+		}
+	}
+
+	return nil
+}
+
+func normalizarLista(campo string, itens []string, normalizar func(string) (string, error)) ([]string, error) {
+	if len(itens) > APIKeyRestrictionsMax {
+		return nil, &ValidationError{Field: campo, Message: fmt.Sprintf("máximo de %d itens", APIKeyRestrictionsMax)}
+	}
+	out := []string{}
+	vistos := map[string]bool{}
+	for _, item := range itens {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		n, err := normalizar(item)
+		if err != nil {
+			return nil, err
+		}
+		if !vistos[n] {
+			vistos[n] = true
+			out = append(out, n)
+		}
+	}
+	return out, nil
+}
+
+func ipPermitido(cidrs []string, ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, cidr := range cidrs {
+		if _, rede, err := net.ParseCIDR(cidr); err == nil && rede.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// origemRequisicao "scheme://host[:porta]" do Origin (preferido) ou do Referer
+func origemRequisicao(origin, referer string) string {
+	for _, bruto := range []string{origin, referer} {
+		if bruto == "" || bruto == "null" {
+			continue
+		}
+		if u, err := url.Parse(bruto); err == nil && u.Scheme != "" && u.Host != "" {
+			return strings.ToLower(u.Scheme + "://" + u.Host)
+		}
+	}
+	return ""
+}
+
+// origemPermitida padrões com scheme opcional e * no host ou na porta (sem porta no padrão = qualquer porta)
+func origemPermitida(padroes []string, origem string) bool {
+	scheme, host := splitOrigemPadrao(origem)
+	hostname, porta := splitHostPorta(host)
+	for _, padrao := range padroes {
+		pScheme, pHost := splitOrigemPadrao(padrao)
+		if pScheme != "" && pScheme != scheme {
+			continue
+		}
+		pHostname, pPorta := splitHostPorta(pHost)
+		if !wildcardMatch(pHostname, hostname) {
+			continue
+		}
+		if pPorta != "" && !wildcardMatch(pPorta, porta) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func splitOrigemPadrao(s string) (scheme, host string) {
+	if i := strings.Index(s, "://"); i >= 0 {
+		return s[:i], s[i+3:]
+	}
+	return "", s
+}
+
+func splitHostPorta(host string) (hostname, porta string) {
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.HasSuffix(host, "]") {
+		return host[:i], host[i+1:]
+	}
+	return host, ""
+}
+
+// wildcardMatch casamento inteiro com * (qualquer sequência, inclusive vazia), sem regexp:
+// varredura linear com retrocesso apenas até o último *
+func wildcardMatch(padrao, s string) bool {
+	p, i := 0, 0
+	estrela, marca := -1, 0
+	for i < len(s) {
+		switch {
+		case p < len(padrao) && padrao[p] == '*':
+			estrela, marca = p, i
+			p++
+		case p < len(padrao) && padrao[p] == s[i]:
+			p++
+			i++
+		case estrela >= 0:
+			p = estrela + 1
+			marca++
+			i = marca
+		default:
+			return false
+		}
+	}
+	for p < len(padrao) && padrao[p] == '*' {
+		p++
+	}
+	return p == len(padrao)
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// Banco representa uma instituição financeira (código COMPE)
+type Banco struct {
+	Codigo    string    `bson:"codigo" json:"codigo"` // "001", "341"
+	Nome      string    `bson:"nome" json:"nome"`
+	ISPB      string    `bson:"ispb,omitempty" json:"ispb,omitempty"`
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"createdAt,omitempty"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,333 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tipos de boleto
+const (
+	BoletoTipoBancario    = "bancario"    // Boleto de cobrança bancária (44/47 dígitos)
+	BoletoTipoArrecadacao = "arrecadacao" // Concessionárias, tributos e convênios (44/48 dígitos, começa com 8)
+)
+
+// Boleto representa um boleto decodificado (código de barras + linha digitável)
+type Boleto struct {
+	Tipo            string             `json:"tipo"`
+	CodigoBarras    string             `json:"codigoBarras"`   // 44 dígitos
+	LinhaDigitavel  string             `json:"linhaDigitavel"` // 47 (bancário) ou 48 (arrecadação) dígitos
+	LinhaFormatada  string             `json:"linhaFormatada"`
+	Valor           float64            `json:"valor"`
+	ValorEhEfetivo  bool               `json:"valorEfetivo"`              // Arrecadação: false quando o valor é referência (não em reais)
+	Vencimento      *string            `json:"vencimento,omitempty"`      // YYYY-MM-DD
+	FatorVencimento *int               `json:"fatorVencimento,omitempty"` // Bancário
+	CampoLivre      string             `json:"campoLivre"`
+	Bancario        *BoletoBancario    `json:"bancario,omitempty"`
+	Arrecadacao     *BoletoArrecadacao `json:"arrecadacao,omitempty"`
+}
+
+// BoletoBancario dados específicos de boleto de cobrança
+type BoletoBancario struct {
+	CodigoBanco string `json:"codigoBanco"`
+	Moeda       string `json:"moeda"` // "9" = Real
+	Banco       *Banco `json:"banco,omitempty"`
+}
+
+// BoletoArrecadacao dados específicos de boleto de arrecadação/concessionária
+type BoletoArrecadacao struct {
+	Segmento             string `json:"segmento"`
+	SegmentoDescricao    string `json:"segmentoDescricao"`
+	IdentificadorValor   string `json:"identificadorValor"`   // 6/7 = módulo 10, 8/9 = módulo 11
+	IdentificacaoEmpresa string `json:"identificacaoEmpresa"` // 4 dígitos (ou CNPJ base de 8 dígitos no segmento 6)
+}
+
+// SegmentosArrecadacao mapeia o segmento (posição 2) para a descrição
+var SegmentosArrecadacao = map[string]string{
+	"1": "Prefeituras",
+	"2": "Saneamento",
+	"3": "Energia elétrica e gás",
+	"4": "Telecomunicações",
+	"5": "Órgãos governamentais",
+	"6": "Carnês e assemelhados (identificação por CNPJ)",
+	"7": "Multas de trânsito",
+	"9": "Uso exclusivo do banco",
+}
+
+var (
+	// fatorBaseOriginal data base FEBRABAN (fator 0000)
+	fatorBaseOriginal = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+	// fatorBaseReset após o fator 9999 (21/02/2025) o fator volta a 1000 em 22/02/2025
+	fatorBaseReset = time.Date(2025, 2, 22, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -1000)
+)
+
+// DecodeBoleto valida e decodifica um boleto a partir da linha digitável (47/48) ou do código de barras (44).
+// referencia é usada para resolver a ambiguidade do fator de vencimento após o reset de 2025.
+func DecodeBoleto(codigo string, referencia time.Time) (*Boleto, error) {
+	cleaned := onlyDigits(codigo)
+
+	switch len(cleaned) {
+	case 44:
+		if cleaned[0] == '8' {
+			return decodeArrecadacaoBarras(cleaned)
+		}
+		return decodeBancarioBarras(cleaned, referencia)
+	case 47:
+		barras, err := linhaBancariaParaBarras(cleaned)
+		if err != nil {
+			return nil, err
+		}
+		return decodeBancarioBarras(barras, referencia)
+	case 48:
+		if cleaned[0] != '8' {
+			return nil, &ValidationError{Field: "codigo", Message: "linha digitável de 48 dígitos deve começar com 8 (arrecadação)"}
+		}
+		barras, err := linhaArrecadacaoParaBarras(cleaned)
+		if err != nil {
+			return nil, err
+		}
+		return decodeArrecadacaoBarras(barras)
+	default:
+		return nil, &ValidationError{Field: "codigo", Message: fmt.Sprintf("código deve ter 44 (código de barras), 47 ou 48 dígitos (linha digitável); recebido: %d", len(cleaned))}
+	}
+}
+
+// ========================================
+// Boleto bancário
+// ========================================
+
+func decodeBancarioBarras(barras string, referencia time.Time) (*Boleto, error) {
+	dv := int(barras[4] - '0')
+	if expected := boletoBancarioDV(barras[:4] + barras[5:]); dv != expected {
+		return nil, &ValidationError{Field: "codigo", Message: fmt.Sprintf("dígito verificador geral inválido (esperado %d, recebido %d)", expected, dv)}
+	}
+
+	campoLivre := barras[19:44]
+	fator := atoiDigits(barras[5:9])
+
+	boleto := &Boleto{
+		Tipo:           BoletoTipoBancario,
+		CodigoBarras:   barras,
+		LinhaDigitavel: barrasParaLinhaBancaria(barras),
+		Valor:          float64(atoiDigits(barras[9:19])) / 100,
+		ValorEhEfetivo: true,
+		CampoLivre:     campoLivre,
+		Bancario: &BoletoBancario{
+			CodigoBanco: barras[0:3],
+			Moeda:       barras[3:4],
+		},
+	}
+	boleto.LinhaFormatada = formatLinhaBancaria(boleto.LinhaDigitavel)
+
+	if fator > 0 {
+		boleto.FatorVencimento = &fator
+		venc := FatorVencimentoParaData(fator, referencia).Format("2006-01-02")
+		boleto.Vencimento = &venc
+	}
+
+	return boleto, nil
+}
+
+// FatorVencimentoParaData converte o fator de vencimento em data.
+// Após 21/02/2025 o fator reinicia em 1000; a data escolhida é a mais próxima da referência.
+func FatorVencimentoParaData(fator int, referencia time.Time) time.Time {
+	original := fatorBaseOriginal.AddDate(0, 0, fator)
+	reset := fatorBaseReset.AddDate(0, 0, fator)
+	if fator < 1000 {
+		return original
+	}
+	if absDuration(reset.Sub(referencia)) < absDuration(original.Sub(referencia)) {
+		return reset
+	}
+	return original
+}
+
+// linhaBancariaParaBarras converte linha digitável (47) em código de barras (44), validando os DVs dos campos
+func linhaBancariaParaBarras(linha string) (string, error) {
+	campos := []struct {
+		nome  string
+		dados string
+		dv    byte
+	}{
+		{"campo 1", linha[0:9], linha[9]},
+		{"campo 2", linha[10:20], linha[20]},
+		{"campo 3", linha[21:31], linha[31]},
+	}
+	for _, campo := range campos {
+		if expected := Modulo10(campo.dados); int(campo.dv-'0') != expected {
+			return "", &ValidationError{Field: "codigo", Message: fmt.Sprintf("dígito verificador do %s inválido (esperado %d, recebido %c)", campo.nome, expected, campo.dv)}
+		}
+	}
+
+	// banco+moeda(4) + DV geral(1) + fator+valor(14) + campo livre(25)
+	barras := linha[0:4] + linha[32:33] + linha[33:47] + linha[4:9] + linha[10:20] + linha[21:31]
+	return barras, nil
+}
+
+// barrasParaLinhaBancaria converte código de barras (44) em linha digitável (47)
+func barrasParaLinhaBancaria(barras string) string {
+	campo1 := barras[0:4] + barras[19:24]
+	campo2 := barras[24:34]
+	campo3 := barras[34:44]
+	return campo1 + fmt.Sprint(Modulo10(campo1)) +
+		campo2 + fmt.Sprint(Modulo10(campo2)) +
+		campo3 + fmt.Sprint(Modulo10(campo3)) +
+		barras[4:5] + barras[5:19]
+}
+
+// formatLinhaBancaria formata: AAAAA.AAAAA BBBBB.BBBBBB CCCCC.CCCCCC D EEEEEEEEEEEEEE
+func formatLinhaBancaria(l string) string {
+	return fmt.Sprintf("%s.%s %s.%s %s.%s %s %s", l[0:5], l[5:10], l[10:15], l[15:21], l[21:26], l[26:32], l[32:33], l[33:47])
+}
+
+// boletoBancarioDV DV geral do boleto bancário (módulo 11, pesos 2-9; resultados 0, 10 e 11 viram 1)
+func boletoBancarioDV(digits43 string) int {
+	dv := 11 - modulo11Soma(digits43)%11
+	if dv == 0 || dv == 10 || dv == 11 {
+		return 1
+	}
+	return dv
+}
+
+// ========================================
+// Arrecadação / concessionárias
+// ========================================
+
+func decodeArrecadacaoBarras(barras string) (*Boleto, error) {
+	idValor := barras[2:3]
+	mod, err := arrecadacaoModulo(idValor)
+	if err != nil {
+		return nil, err
+	}
+
+	dv := int(barras[3] - '0')
+	if expected := mod(barras[:3] + barras[4:]); dv != expected {
+		return nil, &ValidationError{Field: "codigo", Message: fmt.Sprintf("dígito verificador geral inválido (esperado %d, recebido %d)", expected, dv)}
+	}
+
+	segmento := barras[1:2]
+	identificacao := barras[15:19]
+	campoLivre := barras[19:44]
+	if segmento == "6" {
+		identificacao = barras[15:23]
+		campoLivre = barras[23:44]
+	}
+
+	linha := ""
+	for i := 0; i < 4; i++ {
+		bloco := barras[i*11 : (i+1)*11]
+		linha += bloco + fmt.Sprint(mod(bloco))
+	}
+
+	descricao := SegmentosArrecadacao[segmento]
+	if descricao == "" {
+		descricao = "Segmento desconhecido"
+	}
+
+	boleto := &Boleto{
+		Tipo:           BoletoTipoArrecadacao,
+		CodigoBarras:   barras,
+		LinhaDigitavel: linha,
+		LinhaFormatada: fmt.Sprintf("%s-%s %s-%s %s-%s %s-%s", linha[0:11], linha[11:12], linha[12:23], linha[23:24], linha[24:35], linha[35:36], linha[36:47], linha[47:48]),
+		Valor:          float64(atoiDigits(barras[4:15])) / 100,
+		ValorEhEfetivo: idValor == "6" || idValor == "8",
+		CampoLivre:     campoLivre,
+		Arrecadacao: &BoletoArrecadacao{
+			Segmento:             segmento,
+			SegmentoDescricao:    descricao,
+			IdentificadorValor:   idValor,
+			IdentificacaoEmpresa: identificacao,
+		},
+	}
+	return boleto, nil
+}
+
+// linhaArrecadacaoParaBarras converte linha digitável (48) em código de barras (44), validando os DVs dos blocos
+func linhaArrecadacaoParaBarras(linha string) (string, error) {
+	mod, err := arrecadacaoModulo(linha[2:3])
+	if err != nil {
+		return "", err
+	}
+
+	barras := ""
+	for i := 0; i < 4; i++ {
+		bloco := linha[i*12 : i*12+11]
+		dv := int(linha[i*12+11] - '0')
+		if expected := mod(bloco); dv != expected {
+			return "", &ValidationError{Field: "codigo", Message: fmt.Sprintf("dígito verificador do bloco %d inválido (esperado %d, recebido %d)", i+1, expected, dv)}
+		}
+		barras += bloco
+	}
+	return barras, nil
+}
+
+// arrecadacaoModulo escolhe o módulo de cálculo pelo identificador de valor (posição 3)
+func arrecadacaoModulo(idValor string) (func(string) int, error) {
+	switch idValor {
+	case "6", "7":
+		return Modulo10, nil
+	case "8", "9":
+		return arrecadacaoModulo11, nil
+	default:
+		return nil, &ValidationError{Field: "codigo", Message: fmt.Sprintf("identificador de valor inválido: %s (esperado 6, 7, 8 ou 9)", idValor)}
+	}
+}
+
+// arrecadacaoModulo11 módulo 11 da arrecadação (restos 0 e 1 viram 0)
+func arrecadacaoModulo11(digits string) int {
+	remainder := modulo11Soma(digits) % 11
+	if remainder == 0 || remainder == 1 {
+		return 0
+	}
+	return 11 - remainder
+}
+
+// ========================================
+// Helpers de cálculo
+// ========================================
+
+// Modulo10 calcula o DV módulo 10 (pesos 2 e 1 alternados da direita para a esquerda)
+func Modulo10(digits string) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		product := int(digits[i]-'0') * weight
+		sum += product/10 + product%10
+		if weight == 2 {
+			weight = 1
+		} else {
+			weight = 2
+		}
+	}
+	return (10 - sum%10) % 10
+}
+
+// modulo11Soma soma ponderada com pesos 2 a 9 da direita para a esquerda
+func modulo11Soma(digits string) int {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	return sum
+}
+
+func onlyDigits(s string) string {
+	cleaned := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			cleaned = append(cleaned, s[i])
+		}
+	}
+	return string(cleaned)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,45 @@
+package domain
+
+// ValidateCPF valida o formato e dígitos verificadores de um CPF
+func ValidateCPF(cpf string) bool {
+	cleaned := NormalizeCPF(cpf)
+
+	// CPF deve ter 11 dígitos
+	if len(cleaned) != 11 {
+		return false
+	}
+
+	// CPF não pode ser sequência de números iguais
+	allSame := true
+	for i := 1; i < len(cleaned); i++ {
+		if cleaned[i] != cleaned[0] {
+			allSame = false
+			break
+		}
+	}
+	if allSame {
+		return false
+	}
+
+	// Validar os dois dígitos verificadores (pesos 10..2 e 11..2)
+	for pos := 9; pos <= 10; pos++ {
+		sum := 0
+		for i := 0; i < pos; i++ {
+			sum += int(cleaned[i]-'0') * (pos + 1 - i)
+		}
+		digit := (sum * 10) % 11
+		if digit == 10 {
+			digit = 0
+		}
+		if int(cleaned[pos]-'0') != digit {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NormalizeCPF remove formatação de um CPF
+func NormalizeCPF(cpf string) string {
+	return onlyDigits(cpf)
+}
@@ -0,0 +1,360 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Tipos de pena privativa de liberdade
+const (
+	PenaReclusao      = "reclusao"
+	PenaDetencao      = "detencao"
+	PenaPrisaoSimples = "prisao_simples" // Contravenções (LCP)
+)
+
+// Multa prevista junto da pena privativa de liberdade
+const (
+	MultaCumulativa              = "cumulativa"                // "e multa"
+	MultaAlternativa             = "alternativa"               // "ou multa"
+	MultaAlternativaOuCumulativa = "alternativa_ou_cumulativa" // "ou multa, ou ambas"
+)
+
+// Convenção de contagem: ano = 12 meses, mês = 30 dias (frações exatas: 1/3 de 1 ano = 4 meses)
+const (
+	diasPorMes = 30
+	diasPorAno = 12 * diasPorMes
+)
+
+// PenaEstruturada pena cominada em formato estruturado (extraída de PenaMin/PenaMax)
+type PenaEstruturada struct {
+	Tipo        string `json:"tipo,omitempty" bson:"tipo,omitempty"` // reclusao, detencao, prisao_simples
+	MinDias     int    `json:"minDias" bson:"minDias"`
+	MaxDias     int    `json:"maxDias" bson:"maxDias"`
+	Multa       string `json:"multa,omitempty" bson:"multa,omitempty"`             // cumulativa, alternativa, alternativa_ou_cumulativa
+	Complemento string `json:"complemento,omitempty" bson:"complemento,omitempty"` // Texto adicional (ex: suspensão da habilitação)
+	Estruturada bool   `json:"estruturada" bson:"estruturada"`                     // false = texto não reconhecido
+}
+
+// Fracao fração de aumento/diminuição (ex: 1/3)
+type Fracao struct {
+	Num int `json:"num"`
+	Den int `json:"den"`
+}
+
+// Valor retorna a fração como número
+func (f Fracao) Valor() float64 {
+	if f.Den == 0 {
+		return 0
+	}
+	return float64(f.Num) / float64(f.Den)
+}
+
+func (f Fracao) String() string {
+	return fmt.Sprintf("%d/%d", f.Num, f.Den)
+}
+
+// CausaPena causa de aumento (majorante) ou diminuição (minorante) com faixa de frações
+type CausaPena struct {
+	Descricao string `json:"descricao,omitempty"`
+	Expressao string `json:"fracao"`    // Texto original: "de 1/3 até metade"
+	Min       Fracao `json:"fracaoMin"` // Menor fração da faixa
+	Max       Fracao `json:"fracaoMax"` // Maior fração da faixa
+}
+
+// Prescricao prazo prescricional (CP art. 109, com redução do art. 115)
+type Prescricao struct {
+	PenaBaseDias int    `json:"penaBaseDias"` // Pena considerada (máximo em abstrato)
+	PrazoAnos    int    `json:"prazoAnos"`    // Anos completos do prazo (com PrazoMeses: 1 ano e 6 meses)
+	PrazoMeses   int    `json:"prazoMeses"`   // Meses além dos anos completos (art. 115 pode gerar meio ano)
+	PrazoDias    int    `json:"prazoDias"`
+	PrazoTexto   string `json:"prazoTexto"` // "1 ano e 6 meses"
+	Inciso       string `json:"inciso"`     // Inciso do art. 109 aplicado
+	Reduzido     bool   `json:"reduzido"`   // Art. 115: menor de 21 ou maior de 70
+	Fundamento   string `json:"fundamento"`
+}
+
+// DosimetriaInput entrada do cálculo
+type DosimetriaInput struct {
+	Pena                 PenaEstruturada
+	Majorantes           []CausaPena
+	Minorantes           []CausaPena
+	ReducaoPrescricional bool // Art. 115 CP
+}
+
+// DosimetriaResultado faixa de pena resultante
+type DosimetriaResultado struct {
+	PenaBase           PenaEstruturada `json:"penaBase"`
+	Majorantes         []CausaPena     `json:"majorantes"`
+	Minorantes         []CausaPena     `json:"minorantes"`
+	MinDias            int             `json:"minDias"`
+	MaxDias            int             `json:"maxDias"`
+	MinFormatado       string          `json:"minFormatado"`
+	MaxFormatado       string          `json:"maxFormatado"`
+	Prescricao         Prescricao      `json:"prescricao"`         // Pela pena máxima resultante
+	PrescricaoPenaBase Prescricao      `json:"prescricaoPenaBase"` // Pela pena máxima cominada (sem causas)
+	Observacoes        []string        `json:"observacoes"`
+}
+
+var (
+	penaRegex = regexp.MustCompile(`(?i)^\s*(reclus[aã]o|deten[cç][aã]o|pris[aã]o simples)\s*,?\s*de\s+(\d+)\s*(dias?|m[eê]s|meses|anos?)?\s+a\s+(\d+)\s*(dias?|m[eê]s|meses|anos?)`)
+
+	fracaoNumericaRegex = regexp.MustCompile(`(\d+)\s*/\s*(\d+)`)
+
+	// Frações por extenso (sem acento, minúsculo), da mais longa para a mais curta
+	fracoesExtenso = []struct {
+		texto  string
+		fracao Fracao
+	}{
+		{"dois tercos", Fracao{2, 3}},
+		{"tres quartos", Fracao{3, 4}},
+		{"metade", Fracao{1, 2}},
+		{"um terco", Fracao{1, 3}},
+		{"um quarto", Fracao{1, 4}},
+		{"um quinto", Fracao{1, 5}},
+		{"um sexto", Fracao{1, 6}},
+		{"terco", Fracao{1, 3}},
+		{"sexto", Fracao{1, 6}},
+		{"triplo", Fracao{2, 1}}, // "aumenta até o triplo" = +200%
+		{"dobro", Fracao{1, 1}},  // "aplica-se em dobro" = +100%
+	}
+)
+
+// ParsePena extrai tipo, mínimo/máximo (em dias) e multa dos textos PenaMin/PenaMax
+func ParsePena(penaMin, penaMax string) PenaEstruturada {
+	pena := PenaEstruturada{}
+
+	if m := penaRegex.FindStringSubmatch(penaMin); m != nil {
+		tipo := NormalizeSearchText(m[1])
+		switch {
+		case strings.HasPrefix(tipo, "reclus"):
+			pena.Tipo = PenaReclusao
+		case strings.HasPrefix(tipo, "deten"):
+			pena.Tipo = PenaDetencao
+		default:
+			pena.Tipo = PenaPrisaoSimples
+		}
+
+		unidadeMax := m[5]
+		unidadeMin := m[3]
+		if unidadeMin == "" {
+			unidadeMin = unidadeMax // "de 1 a 6 meses"
+		}
+		min, _ := strconv.Atoi(m[2])
+		max, _ := strconv.Atoi(m[4])
+		pena.MinDias = min * diasPorUnidade(unidadeMin)
+		pena.MaxDias = max * diasPorUnidade(unidadeMax)
+		pena.Estruturada = pena.MaxDias >= pena.MinDias
+	}
+
+	complemento := strings.TrimSpace(penaMax)
+	normalized := NormalizeSearchText(complemento)
+	switch {
+	case strings.Contains(normalized, "ou multa") && strings.Contains(normalized, "ambas"):
+		pena.Multa = MultaAlternativaOuCumulativa
+	case strings.HasPrefix(normalized, "ou multa"):
+		pena.Multa = MultaAlternativa
+	case strings.Contains(normalized, "multa"):
+		pena.Multa = MultaCumulativa
+	}
+	if complemento != "" && normalized != "e multa" && normalized != "ou multa" && normalized != "ou multa, ou ambas" {
+		pena.Complemento = complemento
+	}
+
+	return pena
+}
+
+func diasPorUnidade(unidade string) int {
+	u := NormalizeSearchText(unidade)
+	switch {
+	case strings.HasPrefix(u, "ano"):
+		return diasPorAno
+	case strings.HasPrefix(u, "mes"):
+		return diasPorMes
+	default:
+		return 1
+	}
+}
+
+// ParseFracoes interpreta expressões como "aumenta de 1/3 até metade", "de um sexto a dois terços" ou "1/3"
+func ParseFracoes(expressao string) (Fracao, Fracao, error) {
+	text := NormalizeSearchText(expressao)
+
+	type ocorrencia struct {
+		pos    int
+		fracao Fracao
+	}
+	ocorrencias := []ocorrencia{}
+
+	for _, m := range fracaoNumericaRegex.FindAllStringSubmatchIndex(text, -1) {
+		num, _ := strconv.Atoi(text[m[2]:m[3]])
+		den, _ := strconv.Atoi(text[m[4]:m[5]])
+		if den == 0 {
+			return Fracao{}, Fracao{}, &ValidationError{Field: "fracao", Message: "denominador não pode ser zero"}
+		}
+		ocorrencias = append(ocorrencias, ocorrencia{m[0], Fracao{num, den}})
+	}
+
+	// Por extenso: marca as posições já usadas para "um terco" não contar também como "terco"
+	usado := make([]bool, len(text))
+	for _, f := range fracoesExtenso {
+		for start := 0; ; {
+			idx := strings.Index(text[start:], f.texto)
+			if idx == -1 {
+				break
+			}
+			pos := start + idx
+			if !usado[pos] {
+				ocorrencias = append(ocorrencias, ocorrencia{pos, f.fracao})
+				for i := pos; i < pos+len(f.texto); i++ {
+					usado[i] = true
+				}
+			}
+			start = pos + len(f.texto)
+		}
+	}
+
+	if len(ocorrencias) == 0 || len(ocorrencias) > 2 {
+		return Fracao{}, Fracao{}, &ValidationError{Field: "fracao", Message: fmt.Sprintf("não foi possível interpretar %q (use ex: \"1/3\", \"de 1/6 a 2/3\", \"de um terço até metade\")", expressao)}
+	}
+
+	// Ordem de aparição no texto
+	if len(ocorrencias) == 2 && ocorrencias[1].pos < ocorrencias[0].pos {
+		ocorrencias[0], ocorrencias[1] = ocorrencias[1], ocorrencias[0]
+	}
+	min, max := ocorrencias[0].fracao, ocorrencias[len(ocorrencias)-1].fracao
+	if min.Valor() > max.Valor() {
+		min, max = max, min
+	}
+	return min, max, nil
+}
+
+// NewCausaPena cria uma causa de aumento/diminuição a partir da expressão textual
+func NewCausaPena(descricao, expressao string) (CausaPena, error) {
+	min, max, err := ParseFracoes(expressao)
+	if err != nil {
+		return CausaPena{}, err
+	}
+	return CausaPena{Descricao: descricao, Expressao: expressao, Min: min, Max: max}, nil
+}
+
+// CalcularDosimetria aplica majorantes e minorantes (3ª fase, art. 68 CP) em cascata sobre a pena cominada
+//
+// Faixa resultante: mínimo = menor aumento e maior diminuição; máximo = maior aumento e menor diminuição.
+// Frações de dia são desprezadas (art. 11 CP).
+func CalcularDosimetria(in DosimetriaInput) (*DosimetriaResultado, error) {
+	if !in.Pena.Estruturada {
+		return nil, &ValidationError{Field: "pena", Message: "pena privativa de liberdade não reconhecida"}
+	}
+
+	min := float64(in.Pena.MinDias)
+	max := float64(in.Pena.MaxDias)
+	for _, m := range in.Majorantes {
+		min *= 1 + m.Min.Valor()
+		max *= 1 + m.Max.Valor()
+	}
+	for _, m := range in.Minorantes {
+		if m.Max.Valor() >= 1 || m.Min.Valor() >= 1 {
+			return nil, &ValidationError{Field: "minorantes", Message: "fração de diminuição deve ser menor que 1"}
+		}
+		min *= 1 - m.Max.Valor()
+		max *= 1 - m.Min.Valor()
+	}
+
+	result := &DosimetriaResultado{
+		PenaBase:    in.Pena,
+		Majorantes:  in.Majorantes,
+		Minorantes:  in.Minorantes,
+		MinDias:     int(math.Floor(min + 1e-9)),
+		MaxDias:     int(math.Floor(max + 1e-9)),
+		Observacoes: []string{},
+	}
+	if result.Majorantes == nil {
+		result.Majorantes = []CausaPena{}
+	}
+	if result.Minorantes == nil {
+		result.Minorantes = []CausaPena{}
+	}
+	result.MinFormatado = FormatPenaDias(result.MinDias)
+	result.MaxFormatado = FormatPenaDias(result.MaxDias)
+	result.Prescricao = CalcularPrescricao(result.MaxDias, in.ReducaoPrescricional)
+	result.PrescricaoPenaBase = CalcularPrescricao(in.Pena.MaxDias, in.ReducaoPrescricional)
+
+	if len(in.Majorantes) > 1 {
+		result.Observacoes = append(result.Observacoes, "Majorantes aplicadas em cascata; na Parte Especial o juiz pode limitar-se a um só aumento (art. 68, parágrafo único, CP)")
+	}
+	if in.Pena.Multa != "" {
+		result.Observacoes = append(result.Observacoes, "A pena de multa segue o critério de dias-multa (arts. 49 e 60 CP) e não entra neste cálculo")
+	}
+	result.Observacoes = append(result.Observacoes, "Contagem com ano de 12 meses e mês de 30 dias; frações de dia desprezadas (art. 11 CP)")
+
+	return result, nil
+}
+
+// CalcularPrescricao prazo da prescrição da pretensão punitiva pelo máximo da pena (art. 109 CP)
+func CalcularPrescricao(penaMaxDias int, reducao bool) Prescricao {
+	anos := float64(penaMaxDias) / diasPorAno
+
+	p := Prescricao{PenaBaseDias: penaMaxDias}
+	switch {
+	case anos > 12:
+		p.PrazoAnos, p.Inciso = 20, "I"
+	case anos > 8:
+		p.PrazoAnos, p.Inciso = 16, "II"
+	case anos > 4:
+		p.PrazoAnos, p.Inciso = 12, "III"
+	case anos > 2:
+		p.PrazoAnos, p.Inciso = 8, "IV"
+	case anos >= 1:
+		p.PrazoAnos, p.Inciso = 4, "V"
+	default:
+		p.PrazoAnos, p.Inciso = 3, "VI"
+	}
+	p.PrazoDias = p.PrazoAnos * diasPorAno
+	p.Fundamento = fmt.Sprintf("Art. 109, %s, do CP", p.Inciso)
+
+	if reducao {
+		p.Reduzido = true
+		p.PrazoDias /= 2
+		p.Fundamento += " c/c art. 115 do CP (prazo reduzido de metade)"
+	}
+
+	// Anos e meses a partir dos dias: 3 anos reduzidos de metade = 1 ano e 6 meses (não 1 ano)
+	p.PrazoAnos = p.PrazoDias / diasPorAno
+	p.PrazoMeses = (p.PrazoDias % diasPorAno) / diasPorMes
+	p.PrazoTexto = FormatPenaDias(p.PrazoDias)
+
+	return p
+}
+
+// FormatPenaDias formata dias como "X anos, Y meses e Z dias"
+func FormatPenaDias(dias int) string {
+	anos := dias / diasPorAno
+	meses := (dias % diasPorAno) / diasPorMes
+	resto := dias % diasPorMes
+
+	parts := []string{}
+	if anos > 0 {
+		parts = append(parts, plural(anos, "ano", "anos"))
+	}
+	if meses > 0 {
+		parts = append(parts, plural(meses, "mês", "meses"))
+	}
+	if resto > 0 || len(parts) == 0 {
+		parts = append(parts, plural(resto, "dia", "dias"))
+	}
+
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " e " + parts[len(parts)-1]
+}
+
+func plural(n int, singular, pluralForm string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, singular)
+	}
+	return fmt.Sprintf("%d %s", n, pluralForm)
+}
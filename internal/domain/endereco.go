@@ -0,0 +1,430 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Severidades das inconsistências de endereço
+const (
+	EnderecoSeveridadeErro  = "erro"  // Endereço não pode ser considerado válido
+	EnderecoSeveridadeAviso = "aviso" // Corrigido/complementado automaticamente ou divergência menor
+)
+
+// enderecoSimilaridadeMin similaridade mínima para considerar dois nomes a mesma coisa (grafia diferente)
+const enderecoSimilaridadeMin = 0.8
+
+// EnderecoEntrada texto livre e/ou campos parciais (campos informados prevalecem sobre o texto)
+type EnderecoEntrada struct {
+	Endereco       string `json:"endereco"` // "Av. Paulista, 1578 - Bela Vista, São Paulo/SP, 01310-200"
+	CEP            string `json:"cep"`
+	TipoLogradouro string `json:"tipoLogradouro"`
+	Logradouro     string `json:"logradouro"`
+	Numero         string `json:"numero"`
+	Complemento    string `json:"complemento"`
+	Bairro         string `json:"bairro"`
+	Cidade         string `json:"cidade"`
+	UF             string `json:"uf"`
+}
+
+// Endereco endereço estruturado
+type Endereco struct {
+	CEP            string `json:"cep,omitempty"`            // 8 dígitos
+	TipoLogradouro string `json:"tipoLogradouro,omitempty"` // "Rua", "Avenida", "Travessa"...
+	Logradouro     string `json:"logradouro,omitempty"`     // Nome sem o tipo ("Paulista")
+	Numero         string `json:"numero,omitempty"`         // "1578" ou "S/N"
+	Complemento    string `json:"complemento,omitempty"`
+	Bairro         string `json:"bairro,omitempty"`
+	Cidade         string `json:"cidade,omitempty"`
+	UF             string `json:"uf,omitempty"`
+	Estado         string `json:"estado,omitempty"`     // Nome da UF
+	CodigoIBGE     int    `json:"codigoIBGE,omitempty"` // Município
+	Formatado      string `json:"formatado,omitempty"`  // Linha única no padrão dos Correios
+}
+
+// EnderecoInconsistencia divergência encontrada no cruzamento com CEP/IBGE
+type EnderecoInconsistencia struct {
+	Campo      string `json:"campo"`
+	Codigo     string `json:"codigo"` // cep-invalido, cep-nao-encontrado, cep-cidade-divergente, municipio-nao-encontrado...
+	Severidade string `json:"severidade"`
+	Mensagem   string `json:"mensagem"`
+	Informado  string `json:"informado,omitempty"`
+	Esperado   string `json:"esperado,omitempty"`
+}
+
+// EnderecoCEP dados do CEP usados no cruzamento (preenchidos a partir da consulta de CEP)
+type EnderecoCEP struct {
+	CEP        string
+	Logradouro string // Com o tipo ("Avenida Paulista"); vazio em CEPs gerais de cidade
+	Bairro     string
+	Cidade     string
+	UF         string
+	CodigoIBGE string
+	Fonte      string // viacep, brasilapi, redis-cache, mongodb-cache
+}
+
+// EnderecoNormalizado resultado de /endereco/normalizar
+type EnderecoNormalizado struct {
+	Endereco        Endereco                 `json:"endereco"`     // Canônico (CEP/IBGE prevalecem)
+	Interpretado    Endereco                 `json:"interpretado"` // Como a entrada foi lida, antes do cruzamento
+	Valido          bool                     `json:"valido"`       // Sem inconsistências de severidade "erro"
+	Inconsistencias []EnderecoInconsistencia `json:"inconsistencias"`
+	Fontes          []string                 `json:"fontes"` // entrada, cep:<fonte>, ibge
+}
+
+// enderecoTipos abreviações/grafias → tipo de logradouro canônico
+var enderecoTipos = map[string]string{
+	"r": "Rua", "rua": "Rua",
+	"av": "Avenida", "ave": "Avenida", "avn": "Avenida", "avenida": "Avenida",
+	"al": "Alameda", "alameda": "Alameda",
+	"tv": "Travessa", "trav": "Travessa", "travessa": "Travessa",
+	"pc": "Praça", "pca": "Praça", "praca": "Praça",
+	"rod": "Rodovia", "rodovia": "Rodovia",
+	"est": "Estrada", "estr": "Estrada", "estrada": "Estrada",
+	"lgo": "Largo", "largo": "Largo",
+	"ld": "Ladeira", "ladeira": "Ladeira",
+	"bc": "Beco", "beco": "Beco",
+	"vl": "Vila", "vila": "Vila",
+	"vla": "Viela", "viela": "Viela",
+	"srv": "Servidão", "servidao": "Servidão",
+	"vd": "Viaduto", "viaduto": "Viaduto",
+	"pass": "Passagem", "passagem": "Passagem",
+	"via": "Via",
+}
+
+var (
+	enderecoCEPRegex         = regexp.MustCompile(`(?i)(?:cep[:\s]*)?\b(\d{2})\.?(\d{3})-?(\d{3})\b`)
+	enderecoSeparadorRegex   = regexp.MustCompile(`\s*(?:,|;|\||\n|\s-\s|\s–\s)\s*`)
+	enderecoNumeroRegex      = regexp.MustCompile(`(?i)^(?:(?:n[º°o]?\.?|numero|número)\s*)?(\d+[a-z]?|s\.?/?n[º°o]?\.?)$`)
+	enderecoNumeroMarcador   = regexp.MustCompile(`(?i)^(?:n[º°o]?\.?|numero|número)$`)
+	enderecoComplementoRegex = regexp.MustCompile(`(?i)^(?:apto?|apt|apartamento|sala|sl|bloco|bl|casa|cs|lote|lt|quadra|qd|conjunto|conj|cj|andar|loja|lj|fundos|frente|galpao|galpão|box|torre|cobertura|km)\b\.?`)
+	enderecoBairroPrefixo    = regexp.MustCompile(`(?i)^(?:bairro|b\.)\s*:?\s*`)
+)
+
+// ParseEndereco interpreta o texto livre e sobrepõe os campos informados explicitamente
+func ParseEndereco(entrada EnderecoEntrada) Endereco {
+	e := parseEnderecoTexto(entrada.Endereco)
+
+	if cep := strings.TrimSpace(entrada.CEP); cep != "" {
+		e.CEP = LimparCEP(cep)
+	}
+	if entrada.Logradouro != "" {
+		e.TipoLogradouro, e.Logradouro = SepararTipoLogradouro(entrada.Logradouro)
+	}
+	if tipo := strings.TrimSpace(entrada.TipoLogradouro); tipo != "" {
+		e.TipoLogradouro = normalizarTipoLogradouro(tipo)
+	}
+	for _, campo := range []struct {
+		valor   string
+		destino *string
+	}{
+		{entrada.Numero, &e.Numero},
+		{entrada.Complemento, &e.Complemento},
+		{entrada.Bairro, &e.Bairro},
+		{entrada.Cidade, &e.Cidade},
+	} {
+		if v := strings.Join(strings.Fields(campo.valor), " "); v != "" {
+			*campo.destino = v
+		}
+	}
+	if uf := strings.ToUpper(strings.TrimSpace(entrada.UF)); uf != "" {
+		e.UF = uf
+	}
+	e.Numero = normalizarNumero(e.Numero)
+	return e
+}
+
+// Vazio indica que nenhum campo foi informado/interpretado
+func (e Endereco) Vazio() bool {
+	return e.CEP == "" && e.Logradouro == "" && e.Numero == "" && e.Bairro == "" && e.Cidade == "" && e.UF == ""
+}
+
+func parseEnderecoTexto(texto string) Endereco {
+	var e Endereco
+	texto = strings.TrimSpace(texto)
+	if texto == "" {
+		return e
+	}
+
+	// CEP em qualquer posição
+	if m := enderecoCEPRegex.FindStringSubmatchIndex(texto); m != nil {
+		e.CEP = texto[m[2]:m[3]] + texto[m[4]:m[5]] + texto[m[6]:m[7]]
+		texto = texto[:m[0]] + " , " + texto[m[1]:]
+	}
+
+	segmentos := []string{}
+	for _, s := range enderecoSeparadorRegex.Split(texto, -1) {
+		if s = strings.Join(strings.Fields(strings.Trim(s, " .")), " "); s != "" {
+			segmentos = append(segmentos, s)
+		}
+	}
+	if len(segmentos) == 0 {
+		return e
+	}
+
+	// UF no último segmento ("SP", "São Paulo/SP", "São Paulo SP")
+	ultimo := segmentos[len(segmentos)-1]
+	if SiglasUF[strings.ToUpper(ultimo)] {
+		e.UF = strings.ToUpper(ultimo)
+		segmentos = segmentos[:len(segmentos)-1]
+	} else if nome, uf := SepararUFNome(ultimo); uf != "" {
+		e.UF = uf
+		segmentos[len(segmentos)-1] = nome
+	} else if campos := strings.Fields(ultimo); len(campos) > 1 && SiglasUF[strings.ToUpper(campos[len(campos)-1])] {
+		e.UF = strings.ToUpper(campos[len(campos)-1])
+		segmentos[len(segmentos)-1] = strings.Join(campos[:len(campos)-1], " ")
+	}
+	if len(segmentos) == 0 {
+		return e
+	}
+
+	// Primeiro segmento: tipo + logradouro (+ número e complemento, se vierem juntos)
+	complementos := []string{}
+	outros := []string{}
+	if enderecoBairroPrefixo.MatchString(segmentos[0]) {
+		outros = append(outros, segmentos[0]) // Sem logradouro ("Bairro Centro, Recife")
+	} else {
+		var resto string
+		e.TipoLogradouro, e.Logradouro, e.Numero, resto = separarLogradouroNumero(segmentos[0])
+		if resto != "" {
+			complementos = append(complementos, resto)
+		}
+	}
+
+	for _, s := range segmentos[1:] {
+		switch {
+		case e.Numero == "" && enderecoNumeroRegex.MatchString(s):
+			e.Numero = s
+		case e.Numero == "" && numeroComComplemento(s) != "":
+			e.Numero = numeroComComplemento(s)
+			complementos = append(complementos, strings.TrimSpace(s[len(strings.Fields(s)[0]):]))
+		case enderecoComplementoRegex.MatchString(RemoveAccents(s)):
+			complementos = append(complementos, s)
+		default:
+			outros = append(outros, s)
+		}
+	}
+	e.Complemento = strings.Join(complementos, " - ")
+
+	// Restantes: [bairro,] cidade
+	switch len(outros) {
+	case 0:
+	case 1:
+		e.Cidade = outros[0]
+		if prefixo := enderecoBairroPrefixo.FindString(e.Cidade); prefixo != "" {
+			e.Bairro, e.Cidade = e.Cidade[len(prefixo):], ""
+		}
+	default:
+		e.Bairro = enderecoBairroPrefixo.ReplaceAllString(outros[len(outros)-2], "")
+		e.Cidade = outros[len(outros)-1]
+	}
+	return e
+}
+
+// separarLogradouroNumero "Rua 25 de Março 100 apto 3" → Rua, "25 de Março", "100", "apto 3"
+func separarLogradouroNumero(s string) (tipo, nome, numero, complemento string) {
+	tipo, nome = SepararTipoLogradouro(s)
+	tokens := strings.Fields(nome)
+	for i := 1; i < len(tokens); i++ {
+		if !enderecoNumeroRegex.MatchString(tokens[i]) || strings.EqualFold(tokens[i-1], "km") {
+			continue // "km 30" faz parte do endereço em rodovias
+		}
+		restante := strings.Join(tokens[i+1:], " ")
+		if restante != "" && !enderecoComplementoRegex.MatchString(RemoveAccents(restante)) {
+			continue
+		}
+		fim := i
+		if enderecoNumeroMarcador.MatchString(tokens[i-1]) {
+			fim = i - 1
+		}
+		if fim == 0 {
+			continue // O número é o próprio nome ("Rua 7")
+		}
+		return tipo, strings.Join(tokens[:fim], " "), tokens[i], restante
+	}
+	return tipo, nome, "", ""
+}
+
+// numeroComComplemento "1578 apto 12" → "1578" (vazio quando o segmento não começa com número + complemento)
+func numeroComComplemento(s string) string {
+	campos := strings.Fields(s)
+	if len(campos) < 2 || !enderecoNumeroRegex.MatchString(campos[0]) {
+		return ""
+	}
+	if !enderecoComplementoRegex.MatchString(RemoveAccents(strings.Join(campos[1:], " "))) {
+		return ""
+	}
+	return campos[0]
+}
+
+// SepararTipoLogradouro "Av. Paulista" → "Avenida", "Paulista" (tipo vazio quando não reconhecido)
+func SepararTipoLogradouro(s string) (tipo, nome string) {
+	s = strings.Join(strings.Fields(s), " ")
+	primeiro, resto, ok := strings.Cut(s, " ")
+	if !ok {
+		return "", s
+	}
+	if tipo := normalizarTipoLogradouro(primeiro); tipo != "" {
+		return tipo, strings.TrimSpace(resto)
+	}
+	return "", s
+}
+
+func normalizarTipoLogradouro(s string) string {
+	chave := strings.TrimSuffix(strings.ToLower(RemoveAccents(strings.TrimSpace(s))), ".")
+	if tipo, ok := enderecoTipos[chave]; ok {
+		return tipo
+	}
+	return ""
+}
+
+func normalizarNumero(s string) string {
+	s = strings.TrimSpace(s)
+	m := enderecoNumeroRegex.FindStringSubmatch(s)
+	if m == nil {
+		return s
+	}
+	if _, err := strconv.Atoi(strings.TrimRight(m[1], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")); err != nil {
+		return "S/N"
+	}
+	return strings.ToUpper(m[1])
+}
+
+// LimparCEP mantém apenas os dígitos
+func LimparCEP(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// FormatarCEP "01310200" → "01310-200"
+func FormatarCEP(cep string) string {
+	if len(cep) != 8 {
+		return cep
+	}
+	return cep[:5] + "-" + cep[5:]
+}
+
+// NovoEnderecoNormalizado parte da interpretação da entrada (o cruzamento com CEP/IBGE completa o resultado)
+func NovoEnderecoNormalizado(interpretado Endereco) *EnderecoNormalizado {
+	return &EnderecoNormalizado{
+		Endereco:        interpretado,
+		Interpretado:    interpretado,
+		Inconsistencias: []EnderecoInconsistencia{},
+		Fontes:          []string{"entrada"},
+	}
+}
+
+// Adicionar registra uma inconsistência
+func (r *EnderecoNormalizado) Adicionar(campo, codigo, severidade, mensagem, informado, esperado string) {
+	r.Inconsistencias = append(r.Inconsistencias, EnderecoInconsistencia{
+		Campo:      campo,
+		Codigo:     codigo,
+		Severidade: severidade,
+		Mensagem:   mensagem,
+		Informado:  informado,
+		Esperado:   esperado,
+	})
+}
+
+// ConferirCEP cruza o endereço com os dados do CEP; os dados do CEP passam a ser os canônicos
+func (r *EnderecoNormalizado) ConferirCEP(ref EnderecoCEP) {
+	e := &r.Endereco
+	r.Fontes = append(r.Fontes, "cep:"+ref.Fonte)
+
+	if ref.UF != "" && e.UF != "" && !strings.EqualFold(ref.UF, e.UF) {
+		r.Adicionar("uf", "cep-uf-divergente", EnderecoSeveridadeErro,
+			fmt.Sprintf("CEP %s pertence a %s", FormatarCEP(ref.CEP), ref.UF), e.UF, ref.UF)
+	}
+	if ref.Cidade != "" && e.Cidade != "" {
+		switch sim := similaridadeTexto(e.Cidade, ref.Cidade); {
+		case sim == 1:
+		case sim >= enderecoSimilaridadeMin:
+			r.Adicionar("cidade", "cidade-grafia", EnderecoSeveridadeAviso,
+				"Nome da cidade corrigido conforme o CEP", e.Cidade, ref.Cidade)
+		default:
+			r.Adicionar("cidade", "cep-cidade-divergente", EnderecoSeveridadeErro,
+				fmt.Sprintf("CEP %s pertence a %s/%s", FormatarCEP(ref.CEP), ref.Cidade, ref.UF), e.Cidade, ref.Cidade)
+		}
+	}
+	if ref.Bairro != "" && e.Bairro != "" && similaridadeTexto(e.Bairro, ref.Bairro) < enderecoSimilaridadeMin {
+		r.Adicionar("bairro", "cep-bairro-divergente", EnderecoSeveridadeAviso,
+			"Bairro diferente do cadastrado para o CEP", e.Bairro, ref.Bairro)
+	}
+
+	tipoRef, nomeRef := SepararTipoLogradouro(ref.Logradouro)
+	if nomeRef != "" {
+		if e.Logradouro != "" && similaridadeTexto(e.Logradouro, nomeRef) < enderecoSimilaridadeMin {
+			r.Adicionar("logradouro", "cep-logradouro-divergente", EnderecoSeveridadeAviso,
+				"Logradouro diferente do cadastrado para o CEP", strings.TrimSpace(e.TipoLogradouro+" "+e.Logradouro), ref.Logradouro)
+		}
+		e.TipoLogradouro, e.Logradouro = tipoRef, nomeRef
+	}
+	if ref.Bairro != "" {
+		e.Bairro = ref.Bairro
+	}
+	if ref.Cidade != "" {
+		e.Cidade = ref.Cidade
+	}
+	if ref.UF != "" {
+		e.UF = strings.ToUpper(ref.UF)
+	}
+	if codigo, err := strconv.Atoi(ref.CodigoIBGE); err == nil {
+		e.CodigoIBGE = codigo
+	}
+}
+
+// Finalizar monta a linha formatada e calcula a validade
+func (r *EnderecoNormalizado) Finalizar() {
+	e := &r.Endereco
+	if e.Logradouro != "" && e.Numero == "" {
+		r.Adicionar("numero", "numero-ausente", EnderecoSeveridadeAviso, "Número não informado (use S/N quando não houver)", "", "")
+	}
+
+	linha := strings.TrimSpace(e.TipoLogradouro + " " + e.Logradouro)
+	if linha != "" && e.Numero != "" {
+		linha += ", " + e.Numero
+	}
+	if linha != "" && e.Complemento != "" {
+		linha += " - " + e.Complemento
+	}
+	partes := []string{}
+	for _, p := range []string{linha, e.Bairro} {
+		if p != "" {
+			partes = append(partes, p)
+		}
+	}
+	switch {
+	case e.Cidade != "" && e.UF != "":
+		partes = append(partes, e.Cidade+" - "+e.UF)
+	case e.Cidade != "":
+		partes = append(partes, e.Cidade)
+	case e.UF != "":
+		partes = append(partes, e.UF)
+	}
+	if e.CEP != "" {
+		partes = append(partes, FormatarCEP(e.CEP))
+	}
+	e.Formatado = strings.Join(partes, ", ")
+
+	r.Valido = true
+	for _, i := range r.Inconsistencias {
+		if i.Severidade == EnderecoSeveridadeErro {
+			r.Valido = false
+		}
+	}
+}
+
+// similaridadeTexto compara nomes de logradouro/bairro/cidade ignorando acentos, caixa e abreviações
+func similaridadeTexto(a, b string) float64 {
+	na, nb := NormalizarNomeMunicipio(a), NormalizarNomeMunicipio(b)
+	if na == nb {
+		return 1
+	}
+	return similaridadeNome(na, trigramas(na), nb, trigramas(nb))
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // ValidationError representa um erro de validação
 type ValidationError struct {
@@ -12,3 +15,13 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
+// APIKeyExpiredError API key expirada (já fora do período de carência)
+type APIKeyExpiredError struct {
+	KeyID      string
+	ExpiresAt  time.Time
+	GraceUntil time.Time
+}
+
+func (e *APIKeyExpiredError) Error() string {
+	return fmt.Sprintf("api key %s expirada em %s", e.KeyID, e.ExpiresAt.Format(time.RFC3339))
+}
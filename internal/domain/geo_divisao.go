@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// Níveis da divisão territorial do IBGE
+const (
+	GeoNivelRegiao              = "regiao"
+	GeoNivelMesorregiao         = "mesorregiao"
+	GeoNivelMicrorregiao        = "microrregiao"
+	GeoNivelRegiaoIntermediaria = "regiao-intermediaria"
+	GeoNivelRegiaoImediata      = "regiao-imediata"
+)
+
+// GeoNiveis todos os níveis pré-agregados (ordem de exibição)
+var GeoNiveis = []string{
+	GeoNivelRegiao,
+	GeoNivelMesorregiao,
+	GeoNivelMicrorregiao,
+	GeoNivelRegiaoIntermediaria,
+	GeoNivelRegiaoImediata,
+}
+
+// GeoDivisaoRef referência simplificada à divisão superior
+type GeoDivisaoRef struct {
+	ID   int    `bson:"id" json:"id"`
+	Nome string `bson:"nome" json:"nome"`
+}
+
+// GeoDivisao divisão regional pré-agregada a partir dos municípios (seed)
+type GeoDivisao struct {
+	ID              int            `bson:"id" json:"id"`
+	Nome            string         `bson:"nome" json:"nome"`
+	Sigla           string         `bson:"sigla,omitempty" json:"sigla,omitempty"` // Apenas regiões ("NE")
+	Nivel           string         `bson:"nivel" json:"nivel"`
+	UF              *UFReference   `bson:"UF,omitempty" json:"UF,omitempty"` // nil para regiões
+	Regiao          Regiao         `bson:"regiao" json:"regiao"`
+	Superior        *GeoDivisaoRef `bson:"superior,omitempty" json:"superior,omitempty"` // Mesorregião da microrregião, intermediária da imediata
+	TotalMunicipios int            `bson:"totalMunicipios" json:"totalMunicipios"`
+	UFs             []string       `bson:"ufs,omitempty" json:"ufs,omitempty"` // Siglas das UFs (regiões)
+	UpdatedAt       time.Time      `bson:"updatedAt" json:"updatedAt"`
+}
+
+// GeoCampoMunicipio caminho do ID de cada nível no documento do município
+var GeoCampoMunicipio = map[string]string{
+	GeoNivelRegiao:              "microrregiao.mesorregiao.UF.regiao.id",
+	GeoNivelMesorregiao:         "microrregiao.mesorregiao.id",
+	GeoNivelMicrorregiao:        "microrregiao.id",
+	GeoNivelRegiaoIntermediaria: "regiao-imediata.regiao-intermediaria.id",
+	GeoNivelRegiaoImediata:      "regiao-imediata.id",
+}
+
+// BuildGeoDivisoes agrega os municípios em cada nível da divisão regional (nível → divisões ordenadas por nome)
+func BuildGeoDivisoes(municipios []Municipio) map[string][]GeoDivisao {
+	porNivel := map[string]map[int]*GeoDivisao{}
+	ufsPorRegiao := map[int]map[string]bool{}
+	for _, nivel := range GeoNiveis {
+		porNivel[nivel] = map[int]*GeoDivisao{}
+	}
+
+	add := func(nivel string, d GeoDivisao) {
+		if d.ID == 0 {
+			return // Município sem a divisão (dados incompletos do IBGE)
+		}
+		atual, ok := porNivel[nivel][d.ID]
+		if !ok {
+			d.Nivel = nivel
+			atual = &d
+			porNivel[nivel][d.ID] = atual
+		}
+		atual.TotalMunicipios++
+	}
+
+	for _, m := range municipios {
+		meso := m.Microrregiao.Mesorregiao
+		uf := meso.UF
+		regiao := uf.Regiao
+
+		add(GeoNivelRegiao, GeoDivisao{ID: regiao.ID, Nome: regiao.Nome, Sigla: regiao.Sigla, Regiao: regiao})
+		if regiao.ID != 0 && uf.Sigla != "" {
+			if ufsPorRegiao[regiao.ID] == nil {
+				ufsPorRegiao[regiao.ID] = map[string]bool{}
+			}
+			ufsPorRegiao[regiao.ID][uf.Sigla] = true
+		}
+
+		ufRef := uf
+		add(GeoNivelMesorregiao, GeoDivisao{ID: meso.ID, Nome: meso.Nome, UF: &ufRef, Regiao: regiao})
+		add(GeoNivelMicrorregiao, GeoDivisao{
+			ID: m.Microrregiao.ID, Nome: m.Microrregiao.Nome, UF: &ufRef, Regiao: regiao,
+			Superior: &GeoDivisaoRef{ID: meso.ID, Nome: meso.Nome},
+		})
+
+		inter := m.RegiaoImediata.RegiaoIntermediaria
+		interUF := inter.UF
+		if interUF.Sigla == "" {
+			interUF = uf
+		}
+		add(GeoNivelRegiaoIntermediaria, GeoDivisao{ID: inter.ID, Nome: inter.Nome, UF: &interUF, Regiao: interUF.Regiao})
+		add(GeoNivelRegiaoImediata, GeoDivisao{
+			ID: m.RegiaoImediata.ID, Nome: m.RegiaoImediata.Nome, UF: &interUF, Regiao: interUF.Regiao,
+			Superior: &GeoDivisaoRef{ID: inter.ID, Nome: inter.Nome},
+		})
+	}
+
+	result := map[string][]GeoDivisao{}
+	for nivel, divisoes := range porNivel {
+		lista := make([]GeoDivisao, 0, len(divisoes))
+		for _, d := range divisoes {
+			if nivel == GeoNivelRegiao {
+				for sigla := range ufsPorRegiao[d.ID] {
+					d.UFs = append(d.UFs, sigla)
+				}
+				sort.Strings(d.UFs)
+			}
+			lista = append(lista, *d)
+		}
+		sort.Slice(lista, func(i, j int) bool {
+			if lista[i].Nome != lista[j].Nome {
+				return lista[i].Nome < lista[j].Nome
+			}
+			return lista[i].ID < lista[j].ID
+		})
+		result[nivel] = lista
+	}
+	return result
+}
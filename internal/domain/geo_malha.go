@@ -0,0 +1,215 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Tipos de malha
+const (
+	GeoMalhaMunicipio = "municipio"
+	GeoMalhaUF        = "uf"
+)
+
+// Qualidades da malha (mesma nomenclatura da API de malhas do IBGE)
+const (
+	GeoQualidadeMaxima        = "maxima"        // Geometria original do seed (usada no /geo/reverse)
+	GeoQualidadeIntermediaria = "intermediaria" // Simplificada (~100 m)
+	GeoQualidadeMinima        = "minima"        // Simplificada (~1 km), para mapas em escala de UF/país
+)
+
+// GeoQualidadeTolerancia tolerância do Douglas-Peucker (em graus) de cada qualidade simplificada
+var GeoQualidadeTolerancia = map[string]float64{
+	GeoQualidadeIntermediaria: 0.001,
+	GeoQualidadeMinima:        0.01,
+}
+
+// GeoGeometria geometria GeoJSON normalizada para MultiPolygon ([polígono][anel][ponto][lng, lat])
+type GeoGeometria struct {
+	Type        string          `json:"type" bson:"type"`
+	Coordinates [][][][]float64 `json:"coordinates" bson:"coordinates"`
+}
+
+// GeoMalha contorno de um município ou UF
+type GeoMalha struct {
+	Tipo          string                  `json:"tipo" bson:"tipo"`
+	Codigo        int                     `json:"codigo" bson:"codigo"` // Código IBGE (município: 7 dígitos; UF: 2)
+	Geometria     GeoGeometria            `json:"geometria" bson:"geometria"`
+	Simplificadas map[string]GeoGeometria `json:"simplificadas" bson:"simplificadas"` // qualidade → geometria
+	Pontos        int                     `json:"pontos" bson:"pontos"`               // Vértices da geometria original
+	UpdatedAt     time.Time               `json:"updatedAt" bson:"updatedAt"`
+}
+
+// GeoFeature resposta GeoJSON (Feature)
+type GeoFeature struct {
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   GeoGeometria           `json:"geometry"`
+}
+
+// GeometriaQualidade retorna a geometria na qualidade pedida (máxima quando a simplificada não existe)
+func (m GeoMalha) GeometriaQualidade(qualidade string) GeoGeometria {
+	if g, ok := m.Simplificadas[qualidade]; ok && qualidade != GeoQualidadeMaxima {
+		return g
+	}
+	return m.Geometria
+}
+
+// ParseMalhasGeoJSON lê uma FeatureCollection da API de malhas do IBGE (properties.codarea)
+// e monta as malhas com as versões simplificadas
+func ParseMalhasGeoJSON(data []byte, tipo string) ([]GeoMalha, error) {
+	var collection struct {
+		Features []struct {
+			Properties map[string]interface{} `json:"properties"`
+			Geometry   struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, err
+	}
+
+	malhas := make([]GeoMalha, 0, len(collection.Features))
+	for i, feature := range collection.Features {
+		codigo := codigoMalha(feature.Properties)
+		if codigo == 0 {
+			return nil, fmt.Errorf("feature %d sem codarea", i)
+		}
+
+		var multi [][][][]float64
+		switch feature.Geometry.Type {
+		case "Polygon":
+			var polygon [][][]float64
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &polygon); err != nil {
+				return nil, fmt.Errorf("geometria de %d: %w", codigo, err)
+			}
+			multi = [][][][]float64{polygon}
+		case "MultiPolygon":
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &multi); err != nil {
+				return nil, fmt.Errorf("geometria de %d: %w", codigo, err)
+			}
+		default:
+			return nil, fmt.Errorf("geometria de %d: tipo %s não suportado", codigo, feature.Geometry.Type)
+		}
+
+		geometria := GeoGeometria{Type: "MultiPolygon", Coordinates: multi}
+		malha := GeoMalha{
+			Tipo:          tipo,
+			Codigo:        codigo,
+			Geometria:     geometria,
+			Simplificadas: map[string]GeoGeometria{},
+			Pontos:        contarPontos(geometria),
+		}
+		for qualidade, tolerancia := range GeoQualidadeTolerancia {
+			malha.Simplificadas[qualidade] = SimplificarGeometria(geometria, tolerancia)
+		}
+		malhas = append(malhas, malha)
+	}
+	return malhas, nil
+}
+
+// codigoMalha lê o código IBGE (codarea vem como string na API de malhas)
+func codigoMalha(props map[string]interface{}) int {
+	for _, key := range []string{"codarea", "codigo", "id", "CD_MUN", "CD_UF"} {
+		switch v := props[key].(type) {
+		case string:
+			if n, err := strconv.Atoi(v); err == nil {
+				return n
+			}
+		case float64:
+			return int(v)
+		}
+	}
+	return 0
+}
+
+func contarPontos(g GeoGeometria) int {
+	total := 0
+	for _, polygon := range g.Coordinates {
+		for _, ring := range polygon {
+			total += len(ring)
+		}
+	}
+	return total
+}
+
+// SimplificarGeometria aplica Douglas-Peucker em cada anel (tolerância em graus) e arredonda para 6 casas.
+// Anéis que colapsariam (< 4 pontos) são mantidos como estão; buracos que colapsam são descartados.
+func SimplificarGeometria(g GeoGeometria, tolerancia float64) GeoGeometria {
+	out := GeoGeometria{Type: g.Type, Coordinates: make([][][][]float64, 0, len(g.Coordinates))}
+	for _, polygon := range g.Coordinates {
+		simplificado := make([][][]float64, 0, len(polygon))
+		for i, ring := range polygon {
+			ring2 := douglasPeucker(ring, tolerancia)
+			if len(ring2) < 4 {
+				if i > 0 {
+					continue // Buraco menor que a tolerância
+				}
+				ring2 = ring
+			}
+			simplificado = append(simplificado, arredondarRing(ring2))
+		}
+		out.Coordinates = append(out.Coordinates, simplificado)
+	}
+	return out
+}
+
+func douglasPeucker(points [][]float64, tolerancia float64) [][]float64 {
+	if len(points) < 3 {
+		return points
+	}
+
+	manter := make([]bool, len(points))
+	manter[0], manter[len(points)-1] = true, true
+
+	// Pilha em vez de recursão (anéis com dezenas de milhares de pontos)
+	type trecho struct{ inicio, fim int }
+	pilha := []trecho{{0, len(points) - 1}}
+	for len(pilha) > 0 {
+		t := pilha[len(pilha)-1]
+		pilha = pilha[:len(pilha)-1]
+
+		maxDist, maxIdx := 0.0, -1
+		for i := t.inicio + 1; i < t.fim; i++ {
+			if d := distanciaSegmento(points[i], points[t.inicio], points[t.fim]); d > maxDist {
+				maxDist, maxIdx = d, i
+			}
+		}
+		if maxIdx != -1 && maxDist > tolerancia {
+			manter[maxIdx] = true
+			pilha = append(pilha, trecho{t.inicio, maxIdx}, trecho{maxIdx, t.fim})
+		}
+	}
+
+	out := make([][]float64, 0, len(points)/4)
+	for i, p := range points {
+		if manter[i] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// distanciaSegmento distância (planar, em graus) do ponto p ao segmento ab
+func distanciaSegmento(p, a, b []float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	if dx == 0 && dy == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / (dx*dx + dy*dy)
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
+
+func arredondarRing(ring [][]float64) [][]float64 {
+	out := make([][]float64, len(ring))
+	for i, p := range ring {
+		out[i] = []float64{math.Round(p[0]*1e6) / 1e6, math.Round(p[1]*1e6) / 1e6}
+	}
+	return out
+}
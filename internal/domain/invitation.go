@@ -0,0 +1,62 @@
+package domain
+
+import "time"
+
+// Status de um convite (calculado)
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// Invitation convite para um usuário entrar no tenant (o token só é mostrado na criação; no banco fica o hash)
+type Invitation struct {
+	ID         string     `bson:"_id,omitempty" json:"id"`
+	TenantID   string     `bson:"tenantId" json:"tenantId"`
+	Email      string     `bson:"email" json:"email"`
+	Role       TenantRole `bson:"role" json:"role"`
+	TokenHash  string     `bson:"tokenHash" json:"-"`
+	InvitedBy  Actor      `bson:"invitedBy" json:"invitedBy"`
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	ExpiresAt  time.Time  `bson:"expiresAt" json:"expiresAt"`
+	AcceptedAt *time.Time `bson:"acceptedAt,omitempty" json:"acceptedAt,omitempty"`
+	UserID     string     `bson:"userId,omitempty" json:"userId,omitempty"` // Usuário criado no aceite
+	RevokedAt  *time.Time `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+}
+
+// Status situação do convite no instante informado
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case now.After(i.ExpiresAt):
+		return InvitationStatusExpired
+	}
+	return InvitationStatusPending
+}
+
+// InviteUserRequest payload de POST /me/users/invitations
+type InviteUserRequest struct {
+	Email string     `json:"email" binding:"required,email"`
+	Role  TenantRole `json:"role" binding:"required"`
+}
+
+// AcceptInvitationRequest payload de POST /auth/invitations/accept
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// UpdateTenantUserRequest payload de PATCH /me/users/:id
+type UpdateTenantUserRequest struct {
+	Role TenantRole `json:"role" binding:"required"`
+}
+
+// TransferOwnershipRequest payload de POST /me/users/transfer-ownership
+type TransferOwnershipRequest struct {
+	UserID string `json:"userId" binding:"required"`
+}
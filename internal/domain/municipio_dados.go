@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MunicipioIncludes grupos de dados aceitos em ?include= ("all" = todos)
+var MunicipioIncludes = []string{"populacao", "area", "codigos", "ddd", "fuso", "capital", "coordenadas"}
+
+// GeoPonto coordenada em graus decimais
+type GeoPonto struct {
+	Lat float64 `bson:"lat" json:"lat"`
+	Lng float64 `bson:"lng" json:"lng"`
+}
+
+// MunicipioDados dados complementares do município (datasets opcionais carregados no seed)
+type MunicipioDados struct {
+	ID          int            `bson:"id" json:"-"`                                        // Código IBGE
+	Populacao   map[string]int `bson:"populacao,omitempty" json:"populacao,omitempty"`     // Ano → estimativa ("2024": 1587707)
+	AreaKm2     float64        `bson:"areaKm2,omitempty" json:"areaKm2,omitempty"`         // Área territorial (km²)
+	Siafi       string         `bson:"siafi,omitempty" json:"siafi,omitempty"`             // Código SIAFI (Tesouro, 4 dígitos)
+	TSE         string         `bson:"tse,omitempty" json:"tse,omitempty"`                 // Código do município no TSE (5 dígitos)
+	DDD         int            `bson:"ddd,omitempty" json:"ddd,omitempty"`                 // DDD principal
+	FusoHorario string         `bson:"fusoHorario,omitempty" json:"fusoHorario,omitempty"` // IANA ("America/Recife")
+	Capital     *bool          `bson:"capital,omitempty" json:"capital,omitempty"`         // Capital da UF
+	Sede        *GeoPonto      `bson:"sede,omitempty" json:"sede,omitempty"`               // Coordenadas da sede municipal
+	UpdatedAt   time.Time      `bson:"updatedAt,omitempty" json:"updatedAt,omitempty"`
+}
+
+// MunicipioEnriquecido município com os dados complementares pedidos em ?include=
+type MunicipioEnriquecido struct {
+	Municipio `bson:",inline"`
+	Dados     *MunicipioDados `bson:"dados,omitempty" json:"dados,omitempty"`
+}
+
+// MunicipioDadosSeed registro de municipios_dados.json (códigos podem vir como número ou texto)
+type MunicipioDadosSeed struct {
+	ID          int            `json:"id"`
+	Populacao   map[string]int `json:"populacao"`
+	AreaKm2     float64        `json:"areaKm2"`
+	Siafi       interface{}    `json:"siafi"`
+	TSE         interface{}    `json:"tse"`
+	DDD         int            `json:"ddd"`
+	FusoHorario string         `json:"fusoHorario"`
+	Capital     *bool          `json:"capital"`
+	Latitude    *float64       `json:"latitude"`
+	Longitude   *float64       `json:"longitude"`
+}
+
+// ToDados converte o registro do seed, normalizando os códigos SIAFI/TSE
+func (s MunicipioDadosSeed) ToDados() MunicipioDados {
+	d := MunicipioDados{
+		ID:          s.ID,
+		Populacao:   s.Populacao,
+		AreaKm2:     s.AreaKm2,
+		DDD:         s.DDD,
+		FusoHorario: s.FusoHorario,
+		Capital:     s.Capital,
+	}
+	if codigo := codigoSeed(s.Siafi); codigo != "" {
+		d.Siafi = NormalizarCodigoSiafi(codigo)
+	}
+	if codigo := codigoSeed(s.TSE); codigo != "" {
+		d.TSE = NormalizarCodigoTSE(codigo)
+	}
+	if s.Latitude != nil && s.Longitude != nil {
+		d.Sede = &GeoPonto{Lat: *s.Latitude, Lng: *s.Longitude}
+	}
+	return d
+}
+
+func codigoSeed(v interface{}) string {
+	switch c := v.(type) {
+	case string:
+		return strings.TrimSpace(c)
+	case float64:
+		return fmt.Sprintf("%.0f", c)
+	}
+	return ""
+}
+
+// ParseMunicipioIncludes valida ?include=populacao,codigos ("all" = todos)
+func ParseMunicipioIncludes(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	validos := map[string]bool{}
+	for _, inc := range MunicipioIncludes {
+		validos[inc] = true
+	}
+
+	includes := []string{}
+	vistos := map[string]bool{}
+	for _, inc := range strings.Split(s, ",") {
+		inc = strings.ToLower(strings.TrimSpace(inc))
+		if inc == "all" {
+			return MunicipioIncludes, nil
+		}
+		if inc == "" || vistos[inc] {
+			continue
+		}
+		if !validos[inc] {
+			return nil, fmt.Errorf("include '%s' inválido. Valores aceitos: %s ou all", inc, strings.Join(MunicipioIncludes, ", "))
+		}
+		vistos[inc] = true
+		includes = append(includes, inc)
+	}
+	return includes, nil
+}
+
+// Filtrar mantém apenas os grupos pedidos
+func (d MunicipioDados) Filtrar(includes []string) *MunicipioDados {
+	out := &MunicipioDados{ID: d.ID, UpdatedAt: d.UpdatedAt}
+	for _, inc := range includes {
+		switch inc {
+		case "populacao":
+			out.Populacao = d.Populacao
+		case "area":
+			out.AreaKm2 = d.AreaKm2
+		case "codigos":
+			out.Siafi, out.TSE = d.Siafi, d.TSE
+		case "ddd":
+			out.DDD = d.DDD
+		case "fuso":
+			out.FusoHorario = d.FusoHorario
+		case "capital":
+			out.Capital = d.Capital
+		case "coordenadas":
+			out.Sede = d.Sede
+		}
+	}
+	return out
+}
+
+// NormalizarCodigoSiafi completa com zeros à esquerda (SIAFI tem 4 dígitos: "0001" não é "1")
+func NormalizarCodigoSiafi(codigo string) string {
+	return padCodigo(codigo, 4)
+}
+
+// NormalizarCodigoTSE completa com zeros à esquerda (TSE tem 5 dígitos)
+func NormalizarCodigoTSE(codigo string) string {
+	return padCodigo(codigo, 5)
+}
+
+func padCodigo(codigo string, tamanho int) string {
+	codigo = strings.TrimSpace(codigo)
+	if len(codigo) < tamanho {
+		codigo = strings.Repeat("0", tamanho-len(codigo)) + codigo
+	}
+	return codigo
+}
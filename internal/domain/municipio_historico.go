@@ -0,0 +1,149 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tipos de alteração na divisão municipal
+const (
+	AlteracaoCriacao       = "criacao"        // Emancipação/desmembramento: novo código
+	AlteracaoRenomeacao    = "renomeacao"     // Mesmo código, novo nome (inclui grafia)
+	AlteracaoIncorporacao  = "incorporacao"   // Município extinto, território incorporado a outro
+	AlteracaoMudancaCodigo = "mudanca-codigo" // Código IBGE substituído (ex: mudança de UF)
+)
+
+// MunicipioAlteracao alteração da divisão municipal com data de vigência
+type MunicipioAlteracao struct {
+	Tipo           string    `bson:"tipo" json:"tipo"`
+	Municipio      int       `bson:"municipio" json:"municipio"`                               // Código IBGE vigente após a alteração
+	CodigoAnterior int       `bson:"codigoAnterior,omitempty" json:"codigoAnterior,omitempty"` // Código extinto (incorporação/mudança de código)
+	NomeAnterior   string    `bson:"nomeAnterior,omitempty" json:"nomeAnterior,omitempty"`
+	Nome           string    `bson:"nome" json:"nome"` // Nome após a alteração
+	UF             string    `bson:"uf" json:"uf"`
+	Origem         []int     `bson:"origem,omitempty" json:"origem,omitempty"` // Municípios de origem (criação por desmembramento)
+	Data           string    `bson:"data" json:"data"`                         // Vigência (YYYY-MM-DD)
+	Lei            string    `bson:"lei,omitempty" json:"lei,omitempty"`
+	Observacao     string    `bson:"observacao,omitempty" json:"observacao,omitempty"`
+	UpdatedAt      time.Time `bson:"updatedAt,omitempty" json:"-"`
+}
+
+// Validar confere os campos obrigatórios de cada tipo (registros do seed)
+func (a MunicipioAlteracao) Validar() error {
+	if a.Municipio < 1000000 || a.Municipio > 9999999 {
+		return fmt.Errorf("municipio deve ter 7 dígitos")
+	}
+	if _, err := time.Parse("2006-01-02", a.Data); err != nil {
+		return fmt.Errorf("data deve estar no formato YYYY-MM-DD")
+	}
+	switch a.Tipo {
+	case AlteracaoCriacao:
+	case AlteracaoRenomeacao:
+		if a.NomeAnterior == "" {
+			return fmt.Errorf("renomeação sem nomeAnterior")
+		}
+	case AlteracaoIncorporacao, AlteracaoMudancaCodigo:
+		if a.CodigoAnterior == 0 {
+			return fmt.Errorf("%s sem codigoAnterior", a.Tipo)
+		}
+	default:
+		return fmt.Errorf("tipo '%s' inválido", a.Tipo)
+	}
+	return nil
+}
+
+// MunicipioLegadoResolucao resultado da resolução de um código/nome antigo
+type MunicipioLegadoResolucao struct {
+	Codigo     int                  `json:"codigo"` // Código IBGE vigente
+	Motivo     string               `json:"motivo"` // atual, codigo-6-digitos, renomeado, incorporado, codigo-alterado
+	Municipio  *Municipio           `json:"municipio,omitempty"`
+	Alteracoes []MunicipioAlteracao `json:"alteracoes"`
+}
+
+// HistoricoMunicipio alterações que envolvem o código (como resultado, código extinto ou origem), por data
+func HistoricoMunicipio(alteracoes []MunicipioAlteracao, codigo int) []MunicipioAlteracao {
+	historico := []MunicipioAlteracao{}
+	for _, a := range alteracoes {
+		envolve := a.Municipio == codigo || a.CodigoAnterior == codigo
+		for _, origem := range a.Origem {
+			envolve = envolve || origem == codigo
+		}
+		if envolve {
+			historico = append(historico, a)
+		}
+	}
+	sort.SliceStable(historico, func(i, j int) bool { return historico[i].Data < historico[j].Data })
+	return historico
+}
+
+// ResolverCodigoLegado segue as incorporações/mudanças de código a partir de um código extinto.
+// Retorna ok = false quando nenhuma alteração cita o código.
+func ResolverCodigoLegado(alteracoes []MunicipioAlteracao, codigo int) (MunicipioLegadoResolucao, bool) {
+	porAnterior := map[int]MunicipioAlteracao{}
+	for _, a := range alteracoes {
+		if a.CodigoAnterior != 0 && (a.Tipo == AlteracaoIncorporacao || a.Tipo == AlteracaoMudancaCodigo) {
+			porAnterior[a.CodigoAnterior] = a
+		}
+	}
+
+	resolucao := MunicipioLegadoResolucao{Codigo: codigo, Alteracoes: []MunicipioAlteracao{}}
+	visitados := map[int]bool{}
+	for !visitados[resolucao.Codigo] {
+		visitados[resolucao.Codigo] = true
+		a, ok := porAnterior[resolucao.Codigo]
+		if !ok {
+			break
+		}
+		resolucao.Alteracoes = append(resolucao.Alteracoes, a)
+		resolucao.Codigo = a.Municipio
+		if resolucao.Motivo == "" {
+			resolucao.Motivo = "codigo-alterado"
+			if a.Tipo == AlteracaoIncorporacao {
+				resolucao.Motivo = "incorporado"
+			}
+		}
+	}
+	return resolucao, len(resolucao.Alteracoes) > 0
+}
+
+// ResolverCodigoSemDV resolve um código extinto informado com 6 dígitos (sem dígito verificador)
+func ResolverCodigoSemDV(alteracoes []MunicipioAlteracao, codigo int) (MunicipioLegadoResolucao, bool) {
+	for _, a := range alteracoes {
+		if a.CodigoAnterior != 0 && a.CodigoAnterior/10 == codigo {
+			return ResolverCodigoLegado(alteracoes, a.CodigoAnterior)
+		}
+	}
+	return MunicipioLegadoResolucao{}, false
+}
+
+// ResolverNomeLegado encontra o município atual a partir de um nome anterior (sem acento/caixa; uf opcional).
+// Mais de um resultado possível (mesmo nome antigo em UFs diferentes) → todos, para o cliente desambiguar.
+func ResolverNomeLegado(alteracoes []MunicipioAlteracao, nome, uf string) []MunicipioLegadoResolucao {
+	nome = NormalizeSearchText(nome)
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+
+	resultados := []MunicipioLegadoResolucao{}
+	vistos := map[int]bool{}
+	for _, a := range alteracoes {
+		if a.NomeAnterior == "" || NormalizeSearchText(a.NomeAnterior) != nome || (uf != "" && a.UF != uf) {
+			continue
+		}
+
+		resolucao := MunicipioLegadoResolucao{Codigo: a.Municipio, Motivo: "renomeado", Alteracoes: []MunicipioAlteracao{a}}
+		if a.Tipo == AlteracaoIncorporacao {
+			resolucao.Motivo = "incorporado"
+		}
+		// O município resultante pode ter sido alterado de novo depois
+		if seguinte, ok := ResolverCodigoLegado(alteracoes, a.Municipio); ok {
+			resolucao.Codigo = seguinte.Codigo
+			resolucao.Alteracoes = append(resolucao.Alteracoes, seguinte.Alteracoes...)
+		}
+		if !vistos[resolucao.Codigo] {
+			vistos[resolucao.Codigo] = true
+			resultados = append(resultados, resolucao)
+		}
+	}
+	return resultados
+}
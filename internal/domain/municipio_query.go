@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Limites da paginação de municípios
+const (
+	MunicipiosLimitPadrao = 100
+	MunicipiosLimitMax    = 1000
+)
+
+// MunicipioSorts ordenações aceitas em ?sort= ("-" = decrescente)
+var MunicipioSorts = map[string]bool{"nome": true, "-nome": true, "id": true, "-id": true}
+
+// MunicipioCampos campos aceitos em ?fields= (uf, mesorregiao, regiao e regiao-intermediaria são atalhos para os níveis aninhados)
+var MunicipioCampos = []string{
+	"id", "nome", "uf", "microrregiao", "mesorregiao", "regiao", "regiao-imediata", "regiao-intermediaria",
+}
+
+// MunicipioQuery filtros, ordenação, projeção e paginação de /geo/municipios
+type MunicipioQuery struct {
+	UF     string
+	Q      string
+	Sort   string           // "nome" (padrão), "-nome", "id", "-id"
+	Limit  int              // 0 = sem limite
+	Cursor *MunicipioCursor // Posição após o último item da página anterior
+	Fields []string         // vazio = documento completo
+}
+
+// MunicipioCursor posição na ordenação (valor da chave + id para desempate)
+type MunicipioCursor struct {
+	Sort string `json:"s"`
+	Nome string `json:"n,omitempty"`
+	ID   int    `json:"i"`
+}
+
+// Encode serializa o cursor (base64 url-safe, opaco para o cliente)
+func (c MunicipioCursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeMunicipioCursor lê o cursor recebido em ?cursor=
+func DecodeMunicipioCursor(s string) (*MunicipioCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("cursor inválido")
+	}
+	var cursor MunicipioCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || !MunicipioSorts[cursor.Sort] {
+		return nil, fmt.Errorf("cursor inválido")
+	}
+	return &cursor, nil
+}
+
+// NextCursor cursor para a página seguinte ao município informado
+func (q MunicipioQuery) NextCursor(ultimo Municipio) string {
+	cursor := MunicipioCursor{Sort: q.Sort, ID: ultimo.ID}
+	if strings.TrimPrefix(q.Sort, "-") == "nome" {
+		cursor.Nome = ultimo.Nome
+	}
+	return cursor.Encode()
+}
+
+// ParseMunicipioFields valida ?fields=id,nome,uf
+func ParseMunicipioFields(s string) ([]string, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+
+	validos := map[string]bool{}
+	for _, campo := range MunicipioCampos {
+		validos[campo] = true
+	}
+
+	fields := []string{}
+	vistos := map[string]bool{}
+	for _, campo := range strings.Split(s, ",") {
+		campo = strings.ToLower(strings.TrimSpace(campo))
+		if campo == "" || vistos[campo] {
+			continue
+		}
+		if !validos[campo] {
+			return nil, fmt.Errorf("campo '%s' inválido. Campos aceitos: %s", campo, strings.Join(MunicipioCampos, ", "))
+		}
+		vistos[campo] = true
+		fields = append(fields, campo)
+	}
+	return fields, nil
+}
+
+// ProjectMunicipio mantém apenas os campos pedidos
+func ProjectMunicipio(m Municipio, fields []string) map[string]interface{} {
+	out := make(map[string]interface{}, len(fields))
+	for _, campo := range fields {
+		switch campo {
+		case "id":
+			out[campo] = m.ID
+		case "nome":
+			out[campo] = m.Nome
+		case "uf":
+			out[campo] = m.Microrregiao.Mesorregiao.UF.Sigla
+		case "microrregiao":
+			out[campo] = GeoDivisaoRef{ID: m.Microrregiao.ID, Nome: m.Microrregiao.Nome}
+		case "mesorregiao":
+			out[campo] = GeoDivisaoRef{ID: m.Microrregiao.Mesorregiao.ID, Nome: m.Microrregiao.Mesorregiao.Nome}
+		case "regiao":
+			out[campo] = m.Microrregiao.Mesorregiao.UF.Regiao
+		case "regiao-imediata":
+			out[campo] = GeoDivisaoRef{ID: m.RegiaoImediata.ID, Nome: m.RegiaoImediata.Nome}
+		case "regiao-intermediaria":
+			out[campo] = GeoDivisaoRef{ID: m.RegiaoImediata.RegiaoIntermediaria.ID, Nome: m.RegiaoImediata.RegiaoIntermediaria.Nome}
+		}
+	}
+	return out
+}
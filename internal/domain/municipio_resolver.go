@@ -0,0 +1,255 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Limites do resolver de nomes de municípios
+const (
+	MunicipioResolverLimitPadrao = 5
+	MunicipioResolverLimitMax    = 20
+	MunicipioResolverScoreMin    = 0.5 // Abaixo disso o candidato não é retornado
+
+	municipioResolverPesoAnterior = 0.95 // Nome anterior (histórico) pesa um pouco menos que o vigente
+)
+
+// SiglasUF siglas das 27 UFs
+var SiglasUF = map[string]bool{
+	"AC": true, "AL": true, "AP": true, "AM": true, "BA": true, "CE": true, "DF": true, "ES": true, "GO": true,
+	"MA": true, "MT": true, "MS": true, "MG": true, "PA": true, "PB": true, "PR": true, "PE": true, "PI": true,
+	"RJ": true, "RN": true, "RS": true, "RO": true, "RR": true, "SC": true, "SP": true, "SE": true, "TO": true,
+}
+
+// municipioAbreviacoes abreviações comuns em cadastros ("S. José", "Sta. Rita", "Pres. Prudente")
+var municipioAbreviacoes = map[string]string{
+	"s": "sao", "sta": "santa", "sto": "santo", "sr": "senhor", "sra": "senhora", "n": "nossa",
+	"pres": "presidente", "gov": "governador", "mal": "marechal", "cel": "coronel", "dr": "doutor",
+	"fco": "francisco",
+}
+
+// Tipos de correspondência do candidato
+const (
+	MunicipioCorrespondenciaExata      = "exata"         // Nome normalizado idêntico
+	MunicipioCorrespondenciaAproximada = "aproximada"    // Similaridade (erros de digitação, nome incompleto)
+	MunicipioCorrespondenciaAnterior   = "nome-anterior" // Nome antigo do histórico de alterações
+)
+
+// MunicipioCandidato candidato do resolver, com confiança entre 0 e 1
+type MunicipioCandidato struct {
+	Municipio          Municipio `json:"municipio"`
+	Score              float64   `json:"score"`
+	Correspondencia    string    `json:"correspondencia"`
+	NomeCorrespondente string    `json:"nomeCorrespondente"` // Nome comparado (vigente ou anterior)
+}
+
+type municipioResolverEntrada struct {
+	municipio   int // Posição em MunicipioResolverIndex.municipios
+	nome        string
+	normalizado string
+	trigramas   map[string]bool
+	uf          string
+	anterior    bool
+}
+
+// MunicipioResolverIndex nomes normalizados (vigentes e anteriores) com trigramas pré-calculados
+type MunicipioResolverIndex struct {
+	municipios []Municipio
+	entradas   []municipioResolverEntrada
+	BuiltAt    time.Time
+}
+
+// NewMunicipioResolverIndex indexa os nomes vigentes e os nomes anteriores do histórico (apontando para o código atual)
+func NewMunicipioResolverIndex(municipios []Municipio, alteracoes []MunicipioAlteracao) *MunicipioResolverIndex {
+	idx := &MunicipioResolverIndex{municipios: municipios, BuiltAt: time.Now()}
+
+	porID := make(map[int]int, len(municipios))
+	for i, m := range municipios {
+		porID[m.ID] = i
+		idx.add(i, m.Nome, m.Microrregiao.Mesorregiao.UF.Sigla, false)
+	}
+
+	for _, a := range alteracoes {
+		if a.NomeAnterior == "" {
+			continue
+		}
+		codigo := a.Municipio
+		if seguinte, ok := ResolverCodigoLegado(alteracoes, codigo); ok {
+			codigo = seguinte.Codigo
+		}
+		if i, ok := porID[codigo]; ok {
+			idx.add(i, a.NomeAnterior, municipios[i].Microrregiao.Mesorregiao.UF.Sigla, true)
+		}
+	}
+	return idx
+}
+
+func (idx *MunicipioResolverIndex) add(municipio int, nome, uf string, anterior bool) {
+	normalizado := NormalizarNomeMunicipio(nome)
+	idx.entradas = append(idx.entradas, municipioResolverEntrada{
+		municipio:   municipio,
+		nome:        nome,
+		normalizado: normalizado,
+		trigramas:   trigramas(normalizado),
+		uf:          uf,
+		anterior:    anterior,
+	})
+}
+
+// Resolver retorna os candidatos mais prováveis para o nome (uf opcional), do mais ao menos provável
+func (idx *MunicipioResolverIndex) Resolver(nome, uf string, limit int) []MunicipioCandidato {
+	normalizado := NormalizarNomeMunicipio(nome)
+	candidatos := []MunicipioCandidato{}
+	if normalizado == "" {
+		return candidatos
+	}
+	tri := trigramas(normalizado)
+
+	// Melhor correspondência de cada município (vigente ou anterior)
+	melhores := map[int]MunicipioCandidato{}
+	for _, e := range idx.entradas {
+		if uf != "" && e.uf != uf {
+			continue
+		}
+
+		candidato := MunicipioCandidato{Correspondencia: MunicipioCorrespondenciaExata, NomeCorrespondente: e.nome, Score: 1}
+		if e.normalizado != normalizado {
+			candidato.Correspondencia = MunicipioCorrespondenciaAproximada
+			candidato.Score = similaridadeNome(normalizado, tri, e.normalizado, e.trigramas)
+		}
+		if e.anterior {
+			candidato.Correspondencia = MunicipioCorrespondenciaAnterior
+			candidato.Score *= municipioResolverPesoAnterior
+		}
+		candidato.Score = math.Round(candidato.Score*1000) / 1000
+		if candidato.Score < MunicipioResolverScoreMin {
+			continue
+		}
+
+		if atual, ok := melhores[e.municipio]; !ok || candidato.Score > atual.Score {
+			candidato.Municipio = idx.municipios[e.municipio]
+			melhores[e.municipio] = candidato
+		}
+	}
+
+	for _, c := range melhores {
+		candidatos = append(candidatos, c)
+	}
+	sort.Slice(candidatos, func(i, j int) bool {
+		if candidatos[i].Score != candidatos[j].Score {
+			return candidatos[i].Score > candidatos[j].Score
+		}
+		if candidatos[i].Municipio.Nome != candidatos[j].Municipio.Nome {
+			return candidatos[i].Municipio.Nome < candidatos[j].Municipio.Nome
+		}
+		return candidatos[i].Municipio.ID < candidatos[j].Municipio.ID
+	})
+	if limit > 0 && len(candidatos) > limit {
+		candidatos = candidatos[:limit]
+	}
+	return candidatos
+}
+
+// NormalizarNomeMunicipio minúsculas, sem acento/pontuação, abreviações expandidas e "d'Oeste" unificado
+// ("S. José do Rio Preto" → "sao jose do rio preto"; "Sta Bárbara D'Oeste" → "santa barbara doeste")
+func NormalizarNomeMunicipio(s string) string {
+	s = strings.ToLower(RemoveAccents(s))
+	for _, apostrofo := range []string{"'", "’", "‘", "´", "`"} {
+		s = strings.ReplaceAll(s, apostrofo, "")
+	}
+	s = strings.ReplaceAll(s, "n.s.", "n sra ")
+
+	tokens := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if expandido, ok := municipioAbreviacoes[t]; ok {
+			t = expandido
+		}
+		// "do oeste" / "d oeste" → "doeste" (IBGE grafa "D'Oeste")
+		if (t == "do" || t == "d") && i+1 < len(tokens) && tokens[i+1] == "oeste" {
+			t, i = "doeste", i+1
+		}
+		out = append(out, t)
+	}
+	return strings.Join(out, " ")
+}
+
+// SepararUFNome separa a UF informada junto do nome ("Recife/PE", "Recife - PE", "Recife (PE)", "Recife, PE")
+func SepararUFNome(s string) (nome, uf string) {
+	s = strings.TrimSpace(s)
+	semParentese := strings.TrimSpace(strings.TrimSuffix(s, ")"))
+	for _, sep := range []string{"/", "-", "(", ","} {
+		i := strings.LastIndex(semParentese, sep)
+		if i < 0 {
+			continue
+		}
+		if sigla := strings.ToUpper(strings.TrimSpace(semParentese[i+1:])); SiglasUF[sigla] {
+			return strings.TrimSpace(semParentese[:i]), sigla
+		}
+	}
+	return s, ""
+}
+
+// similaridadeNome maior entre o coeficiente de Dice dos trigramas (bom para nomes incompletos)
+// e a similaridade de Levenshtein (bom para erros de digitação)
+func similaridadeNome(a string, triA map[string]bool, b string, triB map[string]bool) float64 {
+	comuns := 0
+	for t := range triA {
+		if triB[t] {
+			comuns++
+		}
+	}
+	dice := 0.0
+	if len(triA)+len(triB) > 0 {
+		dice = 2 * float64(comuns) / float64(len(triA)+len(triB))
+	}
+	if dice < 0.2 {
+		return dice // Nada em comum: evita o Levenshtein (O(n·m)) na maioria das entradas
+	}
+
+	ra, rb := []rune(a), []rune(b)
+	maior := len(ra)
+	if len(rb) > maior {
+		maior = len(rb)
+	}
+	lev := 1 - float64(levenshtein(ra, rb))/float64(maior)
+	return math.Max(dice, lev)
+}
+
+// trigramas de cada palavra, com borda ("  sa", " sao", "sao ")
+func trigramas(s string) map[string]bool {
+	out := map[string]bool{}
+	for _, palavra := range strings.Fields(s) {
+		r := []rune("  " + palavra + " ")
+		for i := 0; i+3 <= len(r); i++ {
+			out[string(r[i:i+3])] = true
+		}
+	}
+	return out
+}
+
+func levenshtein(a, b []rune) int {
+	anterior := make([]int, len(b)+1)
+	atual := make([]int, len(b)+1)
+	for j := range anterior {
+		anterior[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		atual[0] = i
+		for j := 1; j <= len(b); j++ {
+			custo := 1
+			if a[i-1] == b[j-1] {
+				custo = 0
+			}
+			atual[j] = min(anterior[j]+1, atual[j-1]+1, anterior[j-1]+custo)
+		}
+		anterior, atual = atual, anterior
+	}
+	return anterior[len(b)]
+}
@@ -0,0 +1,196 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Níveis da Nomenclatura Comum do Mercosul (pelo número de dígitos)
+const (
+	NCMNivelCapitulo   = "capitulo"   // 2 dígitos
+	NCMNivelPosicao    = "posicao"    // 4 dígitos
+	NCMNivelSubposicao = "subposicao" // 5-6 dígitos
+	NCMNivelItem       = "item"       // 7 dígitos
+	NCMNivelSubitem    = "subitem"    // 8 dígitos (código usado na NF-e)
+)
+
+// NCM representa um código da Nomenclatura Comum do Mercosul
+type NCM struct {
+	ID              string     `json:"-" bson:"_id,omitempty"`
+	Codigo          string     `json:"codigo" bson:"codigo"`                   // Apenas dígitos: "84713012"
+	CodigoFormatado string     `json:"codigoFormatado" bson:"codigoFormatado"` // "8471.30.12"
+	Descricao       string     `json:"descricao" bson:"descricao"`
+	Nivel           string     `json:"nivel" bson:"nivel"`
+	CodigoPai       string     `json:"codigoPai,omitempty" bson:"codigoPai,omitempty"`
+	Hierarquia      []NCMNivel `json:"hierarquia,omitempty" bson:"hierarquia,omitempty"` // Do capítulo até o pai
+	DataInicio      *time.Time `json:"dataInicio,omitempty" bson:"dataInicio,omitempty"`
+	DataFim         *time.Time `json:"dataFim,omitempty" bson:"dataFim,omitempty"` // nil = vigente por prazo indeterminado
+	AtoLegal        string     `json:"atoLegal,omitempty" bson:"atoLegal,omitempty"`
+	Busca           string     `json:"-" bson:"busca"` // Texto normalizado para busca (lowercase, sem acentos)
+	CreatedAt       time.Time  `json:"createdAt" bson:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt" bson:"updatedAt"`
+}
+
+// NCMNivel resumo de um nível da hierarquia
+type NCMNivel struct {
+	Codigo          string `json:"codigo" bson:"codigo"`
+	CodigoFormatado string `json:"codigoFormatado" bson:"codigoFormatado"`
+	Descricao       string `json:"descricao" bson:"descricao"`
+	Nivel           string `json:"nivel" bson:"nivel"`
+}
+
+// CEST representa um Código Especificador da Substituição Tributária (Convênio ICMS 142/18)
+type CEST struct {
+	Codigo    string `json:"cest" bson:"cest"` // "03.001.00"
+	NCM       string `json:"ncm" bson:"ncm"`   // NCM/SH associado (dígitos, pode ser capítulo/posição)
+	Descricao string `json:"descricao" bson:"descricao"`
+	Segmento  string `json:"segmento,omitempty" bson:"segmento,omitempty"`
+}
+
+// NCMSiscomexArquivo formato do JSON publicado no Portal Único Siscomex (tabela NCM vigente)
+type NCMSiscomexArquivo struct {
+	DataUltimaAtualizacao string                   `json:"Data_Ultima_Atualizacao_NCM"`
+	Ato                   string                   `json:"Ato"`
+	Nomenclaturas         []NCMSiscomexArquivoItem `json:"Nomenclaturas"`
+}
+
+// NCMSiscomexArquivoItem linha da tabela NCM do Siscomex
+type NCMSiscomexArquivoItem struct {
+	Codigo       string `json:"Codigo"`
+	Descricao    string `json:"Descricao"`
+	DataInicio   string `json:"Data_Inicio"`
+	DataFim      string `json:"Data_Fim"`
+	TipoAtoIni   string `json:"Tipo_Ato_Ini"`
+	NumeroAtoIni string `json:"Numero_Ato_Ini"`
+	AnoAtoIni    string `json:"Ano_Ato_Ini"`
+}
+
+var htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+
+// NormalizeNCM remove a formatação de um código NCM
+func NormalizeNCM(codigo string) string {
+	return onlyDigits(codigo)
+}
+
+// FormatNCM formata um código NCM conforme o nível (ex: "84713012" → "8471.30.12")
+func FormatNCM(codigo string) string {
+	switch {
+	case len(codigo) <= 4:
+		return codigo
+	case len(codigo) <= 6:
+		return codigo[:4] + "." + codigo[4:]
+	default:
+		return codigo[:4] + "." + codigo[4:6] + "." + codigo[6:]
+	}
+}
+
+// NCMNivelPorTamanho retorna o nível do código pelo número de dígitos
+func NCMNivelPorTamanho(codigo string) string {
+	switch len(codigo) {
+	case 2:
+		return NCMNivelCapitulo
+	case 4:
+		return NCMNivelPosicao
+	case 5, 6:
+		return NCMNivelSubposicao
+	case 7:
+		return NCMNivelItem
+	case 8:
+		return NCMNivelSubitem
+	}
+	return ""
+}
+
+// ParseNCMSiscomex converte o arquivo do Siscomex em NCMs com hierarquia e campo de busca
+func ParseNCMSiscomex(data []byte) ([]NCM, error) {
+	var arquivo NCMSiscomexArquivo
+	if err := json.Unmarshal(data, &arquivo); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+	if len(arquivo.Nomenclaturas) == 0 {
+		return nil, &ValidationError{Field: "Nomenclaturas", Message: "arquivo não contém nomenclaturas"}
+	}
+
+	byCodigo := make(map[string]*NCM, len(arquivo.Nomenclaturas))
+	for _, item := range arquivo.Nomenclaturas {
+		codigo := NormalizeNCM(item.Codigo)
+		nivel := NCMNivelPorTamanho(codigo)
+		if nivel == "" {
+			return nil, &ValidationError{Field: "Codigo", Message: fmt.Sprintf("código NCM inválido: %q", item.Codigo)}
+		}
+
+		ncm := &NCM{
+			Codigo:          codigo,
+			CodigoFormatado: FormatNCM(codigo),
+			Descricao:       cleanNCMDescricao(item.Descricao),
+			Nivel:           nivel,
+			DataInicio:      parseDataBR(item.DataInicio),
+			DataFim:         parseDataBR(item.DataFim),
+		}
+		// 31/12/9999 = sem data de término
+		if ncm.DataFim != nil && ncm.DataFim.Year() >= 9999 {
+			ncm.DataFim = nil
+		}
+		if item.TipoAtoIni != "" {
+			ncm.AtoLegal = strings.TrimSpace(fmt.Sprintf("%s %s/%s", item.TipoAtoIni, item.NumeroAtoIni, item.AnoAtoIni))
+		}
+		byCodigo[codigo] = ncm
+	}
+
+	codigos := make([]string, 0, len(byCodigo))
+	for codigo := range byCodigo {
+		codigos = append(codigos, codigo)
+	}
+	sort.Strings(codigos)
+
+	// Pai = maior prefixo existente na tabela (processado em ordem, o pai já tem hierarquia)
+	result := make([]NCM, 0, len(codigos))
+	for _, codigo := range codigos {
+		ncm := byCodigo[codigo]
+		for size := len(codigo) - 1; size >= 2; size-- {
+			pai, ok := byCodigo[codigo[:size]]
+			if !ok {
+				continue
+			}
+			ncm.CodigoPai = pai.Codigo
+			ncm.Hierarquia = append(append([]NCMNivel{}, pai.Hierarquia...), NCMNivel{
+				Codigo:          pai.Codigo,
+				CodigoFormatado: pai.CodigoFormatado,
+				Descricao:       pai.Descricao,
+				Nivel:           pai.Nivel,
+			})
+			break
+		}
+
+		// Busca inclui a hierarquia (descrições como "Outros" só fazem sentido com o pai)
+		busca := []string{ncm.Codigo, ncm.CodigoFormatado, ncm.Descricao}
+		for _, nivel := range ncm.Hierarquia {
+			busca = append(busca, nivel.Descricao)
+		}
+		ncm.Busca = NormalizeSearchText(strings.Join(busca, " "))
+
+		result = append(result, *ncm)
+	}
+
+	return result, nil
+}
+
+// cleanNCMDescricao remove marcadores de nível ("- ", "-- ") e tags HTML da descrição do Siscomex
+func cleanNCMDescricao(descricao string) string {
+	descricao = htmlTagRegex.ReplaceAllString(descricao, "")
+	descricao = strings.TrimLeft(strings.TrimSpace(descricao), "- ")
+	return strings.TrimSpace(descricao)
+}
+
+// parseDataBR converte "dd/mm/aaaa" (nil se vazia ou inválida)
+func parseDataBR(data string) *time.Time {
+	t, err := time.Parse("02/01/2006", strings.TrimSpace(data))
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,138 @@
+package domain
+
+import "fmt"
+
+// ChaveAcesso representa uma chave de acesso de documento fiscal eletrônico (NF-e, NFC-e, CT-e, MDF-e...)
+// Layout (44 dígitos): cUF(2) AAMM(4) CNPJ/CPF(14) mod(2) serie(3) nNF(9) tpEmis(1) cNF(8) cDV(1)
+type ChaveAcesso struct {
+	Chave             string `json:"chave"`
+	ChaveFormatada    string `json:"chaveFormatada"` // Grupos de 4 dígitos (formato do DANFE)
+	CodigoUF          int    `json:"codigoUF"`       // Código IBGE da UF do emitente
+	AnoMes            string `json:"anoMes"`         // AAMM
+	Ano               int    `json:"ano"`            // 2024
+	Mes               int    `json:"mes"`            // 1-12
+	CNPJEmitente      string `json:"cnpjEmitente"`
+	CNPJValido        bool   `json:"cnpjValido"` // false pode indicar emitente pessoa física (CPF)
+	Modelo            string `json:"modelo"`     // "55", "65", "57"...
+	ModeloDescricao   string `json:"modeloDescricao"`
+	Serie             int    `json:"serie"`
+	Numero            int    `json:"numero"`
+	TipoEmissao       int    `json:"tipoEmissao"`
+	TipoEmissaoDesc   string `json:"tipoEmissaoDescricao"`
+	CodigoNumerico    string `json:"codigoNumerico"`
+	DigitoVerificador int    `json:"digitoVerificador"`
+}
+
+// ModelosDocumentoFiscal mapeia o código do modelo para a descrição
+var ModelosDocumentoFiscal = map[string]string{
+	"55": "NF-e - Nota Fiscal Eletrônica",
+	"57": "CT-e - Conhecimento de Transporte Eletrônico",
+	"58": "MDF-e - Manifesto Eletrônico de Documentos Fiscais",
+	"59": "CF-e SAT - Cupom Fiscal Eletrônico",
+	"63": "BP-e - Bilhete de Passagem Eletrônico",
+	"65": "NFC-e - Nota Fiscal de Consumidor Eletrônica",
+	"66": "NF3e - Nota Fiscal de Energia Elétrica Eletrônica",
+	"67": "CT-e OS - Conhecimento de Transporte Eletrônico para Outros Serviços",
+}
+
+// TiposEmissao mapeia o tpEmis para a descrição
+var TiposEmissao = map[int]string{
+	1: "Normal",
+	2: "Contingência FS-IA",
+	3: "Contingência SCAN",
+	4: "Contingência EPEC",
+	5: "Contingência FS-DA",
+	6: "Contingência SVC-AN",
+	7: "Contingência SVC-RS",
+	8: "Contingência SVC-SP",
+	9: "Contingência off-line (NFC-e)",
+}
+
+// NormalizeChaveAcesso remove espaços, pontos e qualquer caractere não numérico da chave
+func NormalizeChaveAcesso(chave string) string {
+	return onlyDigits(chave)
+}
+
+// ChaveAcessoDV calcula o dígito verificador (módulo 11, pesos 2 a 9 da direita para a esquerda)
+// a partir dos 43 primeiros dígitos da chave
+func ChaveAcessoDV(chave43 string) int {
+	remainder := modulo11Soma(chave43) % 11
+	if remainder < 2 {
+		return 0
+	}
+	return 11 - remainder
+}
+
+// ValidateChaveAcesso valida tamanho e dígito verificador de uma chave de acesso
+func ValidateChaveAcesso(chave string) bool {
+	_, err := ParseChaveAcesso(chave)
+	return err == nil
+}
+
+// ParseChaveAcesso valida e decodifica uma chave de acesso de 44 dígitos
+func ParseChaveAcesso(chave string) (*ChaveAcesso, error) {
+	cleaned := NormalizeChaveAcesso(chave)
+
+	if len(cleaned) != 44 {
+		return nil, &ValidationError{Field: "chave", Message: fmt.Sprintf("chave de acesso deve ter 44 dígitos (recebido: %d)", len(cleaned))}
+	}
+
+	dv := int(cleaned[43] - '0')
+	expected := ChaveAcessoDV(cleaned[:43])
+	if dv != expected {
+		return nil, &ValidationError{Field: "chave", Message: fmt.Sprintf("dígito verificador inválido (esperado %d, recebido %d)", expected, dv)}
+	}
+
+	result := &ChaveAcesso{
+		Chave:             cleaned,
+		ChaveFormatada:    formatChaveAcesso(cleaned),
+		CodigoUF:          atoiDigits(cleaned[0:2]),
+		AnoMes:            cleaned[2:6],
+		Ano:               2000 + atoiDigits(cleaned[2:4]),
+		Mes:               atoiDigits(cleaned[4:6]),
+		CNPJEmitente:      cleaned[6:20],
+		Modelo:            cleaned[20:22],
+		Serie:             atoiDigits(cleaned[22:25]),
+		Numero:            atoiDigits(cleaned[25:34]),
+		TipoEmissao:       atoiDigits(cleaned[34:35]),
+		CodigoNumerico:    cleaned[35:43],
+		DigitoVerificador: dv,
+	}
+
+	if result.Mes < 1 || result.Mes > 12 {
+		return nil, &ValidationError{Field: "chave", Message: fmt.Sprintf("mês de emissão inválido: %02d", result.Mes)}
+	}
+
+	result.CNPJValido = ValidateCNPJ(result.CNPJEmitente)
+	result.ModeloDescricao = ModelosDocumentoFiscal[result.Modelo]
+	if result.ModeloDescricao == "" {
+		result.ModeloDescricao = "Modelo desconhecido"
+	}
+	result.TipoEmissaoDesc = TiposEmissao[result.TipoEmissao]
+	if result.TipoEmissaoDesc == "" {
+		result.TipoEmissaoDesc = "Tipo de emissão desconhecido"
+	}
+
+	return result, nil
+}
+
+// formatChaveAcesso separa a chave em grupos de 4 dígitos
+func formatChaveAcesso(chave string) string {
+	out := make([]byte, 0, len(chave)+len(chave)/4)
+	for i := 0; i < len(chave); i++ {
+		if i > 0 && i%4 == 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, chave[i])
+	}
+	return string(out)
+}
+
+// atoiDigits converte uma string composta apenas por dígitos em int
+func atoiDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
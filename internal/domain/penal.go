@@ -25,6 +25,16 @@ type ArtigoPenal struct {
 	DataAtualizacao string  `json:"dataAtualizacao" bson:"dataAtualizacao"` // Data da última atualização da fonte oficial
 	HashConteudo  string    `json:"hashConteudo,omitempty" bson:"hashConteudo,omitempty"` // SHA256 para detectar alterações
 	IdUnico       string    `json:"idUnico" bson:"idUnico"` // Identificador único: "LEGISLACAO:CODIGO" (ex: "CP:121", "Lei 11.343/2006:33")
+	// Vigência da redação atual (histórico completo em penal_artigos_versoes)
+	VigenteDesde  string    `json:"vigenteDesde,omitempty" bson:"vigenteDesde,omitempty"`   // "2009-08-10"
+	LeiAlteradora string    `json:"leiAlteradora,omitempty" bson:"leiAlteradora,omitempty"` // "Lei 12.015/2009"
+	Versao        int       `json:"versao,omitempty" bson:"versao,omitempty"`               // Número da versão atual
+	// Hierarquia (calculada no seed)
+	Nivel         string    `json:"nivel,omitempty" bson:"nivel,omitempty"`         // "artigo", "paragrafo", "inciso", "alinea"
+	CodigoPai     string    `json:"codigoPai,omitempty" bson:"codigoPai,omitempty"` // idUnico do dispositivo superior (vazio no caput)
+	Ordem         string    `json:"-" bson:"ordem,omitempty"`                       // Chave de ordenação ("00121", "00121.p001.i002")
+	Localizacao   []PenalDivisaoRef `json:"localizacao,omitempty" bson:"localizacao,omitempty"` // Parte → Título → Capítulo
+	Pena          *PenaEstruturada  `json:"pena,omitempty" bson:"pena,omitempty"`               // PenaMin/PenaMax estruturados (calculado no seed)
 	CreatedAt     time.Time `json:"createdAt" bson:"createdAt"`
 	UpdatedAt     time.Time `json:"updatedAt" bson:"updatedAt"`
 }
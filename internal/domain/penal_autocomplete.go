@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Pesos do autocomplete: código do dispositivo > início da descrição > palavra da descrição
+const (
+	autocompletePesoCodigo    = 3
+	autocompletePesoDescricao = 2
+	autocompletePesoPalavra   = 1
+
+	autocompleteMaxScan = 5000 // Limite de chaves percorridas por consulta (prefixos muito curtos)
+)
+
+// palavras ignoradas na normalização ("art. 121, § 2º, inciso IV" → "121 2 iv")
+var autocompleteIgnorar = map[string]bool{
+	"art": true, "arts": true, "artigo": true, "paragrafo": true, "inc": true, "inciso": true, "alinea": true,
+	"do": true, "da": true, "de": true,
+}
+
+// PenalAutocompleteItem sugestão do autocomplete
+type PenalAutocompleteItem struct {
+	PenalResponse
+	Match string `json:"match"` // "codigo" ou "descricao"
+}
+
+type autocompleteChave struct {
+	chave string
+	doc   int
+	peso  int
+}
+
+// PenalAutocompleteIndex índice de prefixos em memória (chaves normalizadas ordenadas + busca binária)
+type PenalAutocompleteIndex struct {
+	artigos []ArtigoPenal
+	ordem   []string // PenalOrdem de cada artigo (desempate: caput antes dos parágrafos)
+	chaves  []autocompleteChave
+	BuiltAt time.Time
+}
+
+// NewPenalAutocompleteIndex indexa código ("121 2", "cp 121 2") e descrição (início e cada palavra)
+func NewPenalAutocompleteIndex(artigos []ArtigoPenal) *PenalAutocompleteIndex {
+	idx := &PenalAutocompleteIndex{
+		artigos: artigos,
+		ordem:   make([]string, len(artigos)),
+		BuiltAt: time.Now(),
+	}
+
+	for i, artigo := range artigos {
+		idx.ordem[i] = PenalOrdem(artigo.Codigo)
+
+		codigo := NormalizeAutocomplete(artigo.Codigo)
+		idx.add(codigo, i, autocompletePesoCodigo)
+		if parts := strings.SplitN(artigo.IdUnico, ":", 2); len(parts) == 2 {
+			idx.add(NormalizeAutocomplete(parts[0])+" "+codigo, i, autocompletePesoCodigo)
+		}
+
+		descricao := NormalizeAutocomplete(artigo.Descricao)
+		idx.add(descricao, i, autocompletePesoDescricao)
+		for pos := strings.Index(descricao, " "); pos != -1; {
+			idx.add(descricao[pos+1:], i, autocompletePesoPalavra)
+			next := strings.Index(descricao[pos+1:], " ")
+			if next == -1 {
+				break
+			}
+			pos += next + 1
+		}
+	}
+
+	sort.Slice(idx.chaves, func(i, j int) bool { return idx.chaves[i].chave < idx.chaves[j].chave })
+	return idx
+}
+
+func (idx *PenalAutocompleteIndex) add(chave string, doc, peso int) {
+	if chave != "" {
+		idx.chaves = append(idx.chaves, autocompleteChave{chave: chave, doc: doc, peso: peso})
+	}
+}
+
+// Suggest retorna até limit sugestões para o prefixo (opcionalmente filtradas por legislação)
+func (idx *PenalAutocompleteIndex) Suggest(prefix, legislacao string, limit int) []PenalAutocompleteItem {
+	prefix = NormalizeAutocomplete(prefix)
+	if prefix == "" {
+		return []PenalAutocompleteItem{}
+	}
+
+	type candidato struct {
+		peso  int
+		exato bool
+	}
+	melhores := map[int]candidato{}
+
+	start := sort.Search(len(idx.chaves), func(i int) bool { return idx.chaves[i].chave >= prefix })
+	for i := start; i < len(idx.chaves) && i-start < autocompleteMaxScan; i++ {
+		k := idx.chaves[i]
+		if !strings.HasPrefix(k.chave, prefix) {
+			break
+		}
+		if legislacao != "" && !matchesLegislacao(idx.artigos[k.doc], legislacao) {
+			continue
+		}
+		atual := candidato{peso: k.peso, exato: k.chave == prefix}
+		if prev, ok := melhores[k.doc]; !ok || atual.peso > prev.peso || (atual.peso == prev.peso && atual.exato) {
+			melhores[k.doc] = atual
+		}
+	}
+
+	docs := make([]int, 0, len(melhores))
+	for doc := range melhores {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		a, b := melhores[docs[i]], melhores[docs[j]]
+		if a.peso != b.peso {
+			return a.peso > b.peso
+		}
+		if a.exato != b.exato {
+			return a.exato
+		}
+		if idx.ordem[docs[i]] != idx.ordem[docs[j]] {
+			return idx.ordem[docs[i]] < idx.ordem[docs[j]]
+		}
+		return idx.artigos[docs[i]].IdUnico < idx.artigos[docs[j]].IdUnico
+	})
+	if limit > 0 && len(docs) > limit {
+		docs = docs[:limit]
+	}
+
+	items := make([]PenalAutocompleteItem, 0, len(docs))
+	for _, doc := range docs {
+		artigo := idx.artigos[doc]
+		match := "descricao"
+		if melhores[doc].peso == autocompletePesoCodigo {
+			match = "codigo"
+		}
+		items = append(items, PenalAutocompleteItem{
+			PenalResponse: PenalResponse{
+				Codigo:          artigo.Codigo,
+				CodigoFormatado: artigo.CodigoFormatado,
+				Descricao:       artigo.Descricao,
+				Tipo:            artigo.Tipo,
+				Legislacao:      artigo.Legislacao,
+				LegislacaoNome:  artigo.LegislacaoNome,
+				IdUnico:         artigo.IdUnico,
+			},
+			Match: match,
+		})
+	}
+	return items
+}
+
+// NormalizeAutocomplete minúsculo, sem acentos e sem pontuação, descartando "art", "§", "inciso"...
+// "Art. 121, § 2º" → "121 2"; "121.2.IV" → "121 2 iv"; "149-A" → "149 a"; "Lesão Corporal" → "lesao corporal"
+func NormalizeAutocomplete(s string) string {
+	s = strings.ToLower(RemoveAccents(s))
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		// "º" é letra para o unicode; "-" separa o sufixo ("149-A" → "149 a")
+		return r == 'º' || r == 'ª' || !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if strings.HasSuffix(f, "o") && isNumeric(f[:len(f)-1]) {
+			f = f[:len(f)-1] // "2o" (ordinal) → "2"
+		}
+		if autocompleteIgnorar[f] {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// matchesLegislacao filtro por legislação ("CP", "Lei 11.343/2006") ou código curto do idUnico ("DRG")
+func matchesLegislacao(artigo ArtigoPenal, legislacao string) bool {
+	return strings.EqualFold(artigo.Legislacao, legislacao) ||
+		strings.HasPrefix(strings.ToUpper(artigo.IdUnico), strings.ToUpper(legislacao)+":")
+}
@@ -0,0 +1,438 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PenalImportLegislacao metadados da lei importada
+type PenalImportLegislacao struct {
+	Codigo          string // Código curto do idUnico: "CP", "MDP"
+	Legislacao      string // "CP", "Lei 11.340/2006"
+	LegislacaoNome  string // "Código Penal", "Lei Maria da Penha"
+	Tipo            string // "crime" ou "contravencao"
+	Fonte           string
+	DataAtualizacao string
+}
+
+// PenalImportOptions opções do parser
+type PenalImportOptions struct {
+	Todos bool // true = todos os dispositivos; false = apenas os que cominam pena
+}
+
+// PenalImportDiff diferenças entre a base atual e a importação
+type PenalImportDiff struct {
+	Novos     []ArtigoPenal     `json:"novos"`
+	Alterados []PenalVersaoDiff `json:"alterados"`
+	Ausentes  []string          `json:"ausentes"` // idUnico na base mas não na importação
+	Iguais    int               `json:"iguais"`
+}
+
+var (
+	importStrikeRegex = regexp.MustCompile(`(?is)<(strike|s|del)\b[^>]*>.*?</(strike|s|del)>`)
+	importBlockRegex  = regexp.MustCompile(`(?i)</?(p|br|div|tr|li|h[1-6])\b[^>]*>`)
+	importTagRegex    = regexp.MustCompile(`(?s)<[^>]*>`)
+	importScriptRegex = regexp.MustCompile(`(?is)<(script|style|head)\b[^>]*>.*?</(script|style|head)>`)
+
+	// Anotações do Planalto: "(Redação dada pela Lei nº 12.015, de 2009)", "(Incluído pela Lei nº 13.104, de 2015)"
+	importAnotacaoRegex = regexp.MustCompile(`(?i)\(\s*(reda[çc][ãa]o dada|inclu[íi]d[oa]|acrescentad[oa]|renumerad[oa]|revogad[oa]|vide|vig[êe]ncia|produ[çc][ãa]o de efeito)[^)]*\)`)
+	importLeiRegex      = regexp.MustCompile(`(?i)(lei(?:\s+complementar)?)\s+n[º°o]?\.?\s*([\d.]+)\s*,?\s*de\s+(?:\d{1,2}[./]\d{1,2}[./])?(\d{2,4})`)
+
+	// Sufixo "-A" sem espaços ("Art. 121-A."); com espaços é separador ("Art. 1º - Não há crime")
+	importArtigoRegex    = regexp.MustCompile(`^Art\.?\s*(\d+)[º°o]?(?:-([A-Z]))?\s*[.\-–—]?\s*(.*)$`)
+	importParagrafoRegex = regexp.MustCompile(`^§\s*(\d+)[º°o]?(?:-([A-Z]))?\s*[.\-–—]?\s*(.*)$`)
+	importUnicoRegex     = regexp.MustCompile(`(?i)^par[áa]grafo\s+[úu]nico\s*[.\-–—:]?\s*(.*)$`)
+	importIncisoRegex    = regexp.MustCompile(`^([IVXLC]+)(?:-([A-Z]))?\s*[-–—]\s*(.*)$`)
+	importAlineaRegex    = regexp.MustCompile(`^([a-z])\)\s*(.*)$`)
+	importPenaRegex      = regexp.MustCompile(`(?i)^Penas?\s*[-–—:]+\s*(.*)$`)
+	importDivisaoRegex   = regexp.MustCompile(`(?i)^(parte|livro|t[íi]tulo|cap[íi]tulo|se[çc][ãa]o|subse[çc][ãa]o)\s+([IVXLC]+|[úu]nic[oa]|geral|especial)\b`)
+
+	// "de 6 (seis) a 10 (dez) anos" → "de 6 a 10 anos"
+	importExtensoParenRegex = regexp.MustCompile(`(\d+)\s*\([^)]*\)`)
+
+	numerosExtenso = map[string]string{
+		"um": "1", "uma": "1", "dois": "2", "duas": "2", "tres": "3", "quatro": "4", "cinco": "5",
+		"seis": "6", "sete": "7", "oito": "8", "nove": "9", "dez": "10", "onze": "11", "doze": "12",
+		"treze": "13", "quatorze": "14", "catorze": "14", "quinze": "15", "dezesseis": "16",
+		"dezessete": "17", "dezoito": "18", "dezenove": "19", "vinte": "20", "trinta": "30", "quarenta": "40",
+	}
+)
+
+// importDispositivo dispositivo em construção durante o parse
+type importDispositivo struct {
+	codigo   string
+	texto    string
+	rubrica  string
+	pena     string
+	lei      string
+	revogado bool
+	filhos   []string // códigos dos incisos/alíneas (compõem o texto do dispositivo que os enumera)
+}
+
+// ParsePlanalto converte o texto de uma lei no formato do Planalto (HTML ou texto puro)
+// em dispositivos penais: artigos, parágrafos, incisos e alíneas, com rubrica e pena.
+func ParsePlanalto(data []byte, leg PenalImportLegislacao, opts PenalImportOptions) ([]ArtigoPenal, error) {
+	linhas := planaltoLinhas(data)
+
+	dispositivos := map[string]*importDispositivo{}
+	ordem := []string{}
+	var atual *importDispositivo
+	artigo, paragrafo, inciso := "", "", ""
+	rubrica := ""
+
+	novo := func(codigo, texto string) {
+		d := &importDispositivo{codigo: codigo, rubrica: rubrica}
+		d.texto, d.lei, d.revogado = limparAnotacoes(texto)
+		if _, exists := dispositivos[codigo]; !exists {
+			ordem = append(ordem, codigo)
+		}
+		dispositivos[codigo] = d
+		atual = d
+		rubrica = ""
+	}
+
+	for i, linha := range linhas {
+		switch {
+		case importArtigoRegex.MatchString(linha):
+			m := importArtigoRegex.FindStringSubmatch(linha)
+			artigo, paragrafo, inciso = m[1], "", ""
+			if m[2] != "" {
+				artigo += "-" + m[2]
+			}
+			novo(artigo, m[3])
+
+		case artigo != "" && importParagrafoRegex.MatchString(linha):
+			m := importParagrafoRegex.FindStringSubmatch(linha)
+			paragrafo, inciso = m[1], ""
+			if m[2] != "" {
+				paragrafo += "-" + m[2]
+			}
+			novo(artigo+"."+paragrafo, m[3])
+
+		case artigo != "" && importUnicoRegex.MatchString(linha):
+			paragrafo, inciso = "unico", ""
+			novo(artigo+".unico", importUnicoRegex.FindStringSubmatch(linha)[1])
+
+		case artigo != "" && importIncisoRegex.MatchString(linha):
+			m := importIncisoRegex.FindStringSubmatch(linha)
+			inciso = m[1]
+			if m[2] != "" {
+				inciso += "-" + m[2]
+			}
+			pai := planaltoCodigo(artigo, paragrafo, "")
+			novo(pai+"."+inciso, m[3])
+			if d := dispositivos[pai]; d != nil {
+				d.filhos = append(d.filhos, atual.codigo)
+			}
+
+		case artigo != "" && importAlineaRegex.MatchString(linha):
+			m := importAlineaRegex.FindStringSubmatch(linha)
+			pai := planaltoCodigo(artigo, paragrafo, inciso)
+			novo(pai+"."+m[1], m[2])
+			if d := dispositivos[pai]; d != nil {
+				d.filhos = append(d.filhos, atual.codigo)
+			}
+
+		case importPenaRegex.MatchString(linha):
+			// A pena pertence ao artigo/parágrafo que enumera os incisos, não ao último inciso
+			pena, lei, _ := limparAnotacoes(importPenaRegex.FindStringSubmatch(linha)[1])
+			if d := dispositivos[planaltoCodigo(artigo, paragrafo, "")]; d != nil {
+				d.pena = pena
+				if lei != "" {
+					d.lei = lei
+				}
+			}
+
+		case importDivisaoRegex.MatchString(linha) || isCaixaAlta(linha):
+			// Títulos e capítulos: o sumário vem de penal_estrutura.json
+			rubrica = ""
+			atual = nil
+
+		case isRubrica(linha, linhas, i):
+			rubrica, _, _ = limparAnotacoes(linha)
+
+		case atual != nil:
+			// Continuação (texto quebrado em várias linhas)
+			texto, lei, _ := limparAnotacoes(linha)
+			atual.texto = strings.TrimSpace(atual.texto + " " + texto)
+			if lei != "" {
+				atual.lei = lei
+			}
+		}
+	}
+
+	if len(ordem) == 0 {
+		return nil, fmt.Errorf("nenhum artigo encontrado (esperado formato do Planalto: \"Art. 1º ...\")")
+	}
+
+	artigos := []ArtigoPenal{}
+	for _, codigo := range ordem {
+		d := dispositivos[codigo]
+		if d.revogado || (!opts.Todos && d.pena == "") {
+			continue
+		}
+		artigos = append(artigos, d.toArtigoPenal(dispositivos, leg))
+	}
+	return artigos, nil
+}
+
+func (d *importDispositivo) toArtigoPenal(dispositivos map[string]*importDispositivo, leg PenalImportLegislacao) ArtigoPenal {
+	// Texto: o dispositivo + os incisos/alíneas que ele enumera ("Se o crime é cometido: I - ...; II - ...")
+	texto := strings.TrimRight(d.textoComFilhos(dispositivos), " .:;,")
+
+	// Descrição: rubrica do dispositivo ou do superior mais próximo
+	descricao := d.rubrica
+	for pai := PenalCodigoPai(d.codigo); descricao == "" && pai != ""; pai = PenalCodigoPai(pai) {
+		if p := dispositivos[pai]; p != nil {
+			descricao = p.rubrica
+		}
+	}
+
+	penaMin, penaMax := NormalizarPenaPlanalto(d.pena)
+	artigo := ArtigoPenal{
+		Codigo:          d.codigo,
+		Descricao:       descricao,
+		TextoCompleto:   texto,
+		Tipo:            leg.Tipo,
+		Legislacao:      leg.Legislacao,
+		LegislacaoNome:  leg.LegislacaoNome,
+		PenaMin:         penaMin,
+		PenaMax:         penaMax,
+		CodigoFormatado: FormatCodigoPenal(d.codigo, leg.Legislacao),
+		Fonte:           leg.Fonte,
+		DataAtualizacao: leg.DataAtualizacao,
+		HashConteudo:    HashConteudoPenal(leg.Legislacao, d.codigo, texto),
+		IdUnico:         leg.Codigo + ":" + d.codigo,
+		LeiAlteradora:   d.lei,
+	}
+	fmt.Sscanf(PenalArtigoBase(d.codigo), "%d", &artigo.Artigo)
+
+	segments := strings.Split(d.codigo, ".")
+	for _, seg := range segments[1:] {
+		seg := seg
+		switch nivelSegmento(seg) {
+		case PenalNivelParagrafo:
+			n := 0
+			fmt.Sscanf(seg, "%d", &n) // "unico" → 0
+			artigo.Paragrafo = &n
+		case PenalNivelInciso:
+			artigo.Inciso = &seg
+		default:
+			artigo.Alinea = &seg
+		}
+	}
+
+	return artigo
+}
+
+// textoComFilhos texto do dispositivo seguido dos incisos/alíneas que ele enumera
+func (d *importDispositivo) textoComFilhos(dispositivos map[string]*importDispositivo) string {
+	if !strings.HasSuffix(d.texto, ":") || len(d.filhos) == 0 {
+		return d.texto
+	}
+	partes := []string{d.texto}
+	for _, codigo := range d.filhos {
+		filho := dispositivos[codigo]
+		if filho == nil || filho.revogado {
+			continue
+		}
+		rotulo := PenalRotulo(codigo)
+		if nivelSegmento(codigo[strings.LastIndex(codigo, ".")+1:]) == PenalNivelInciso {
+			rotulo += " -"
+		}
+		partes = append(partes, rotulo+" "+filho.textoComFilhos(dispositivos))
+	}
+	return strings.Join(partes, " ")
+}
+
+// HashConteudoPenal hash do conteúdo do dispositivo (mesmo critério do seed)
+func HashConteudoPenal(legislacao, codigo, texto string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s", legislacao, codigo, texto)))
+	return hex.EncodeToString(hash[:])
+}
+
+// FormatCodigoPenal "121.2.IV" + "CP" → "Art. 121, § 2º, IV do CP"; leis → "Art. 24-A da Lei 11.340/2006"
+func FormatCodigoPenal(codigo, legislacao string) string {
+	segments := strings.Split(codigo, ".")
+	rotulos := make([]string, 0, len(segments))
+	for i := range segments {
+		rotulos = append(rotulos, PenalRotulo(strings.Join(segments[:i+1], ".")))
+	}
+	artigo := strings.Join(rotulos, ", ")
+	if strings.HasPrefix(legislacao, "Lei") {
+		return artigo + " da " + legislacao
+	}
+	return artigo + " do " + legislacao
+}
+
+// NormalizarPenaPlanalto "reclusão, de seis a vinte anos, e multa." → ("Reclusão, de 6 a 20 anos", "e multa")
+func NormalizarPenaPlanalto(pena string) (string, string) {
+	pena = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(pena), "."))
+	if pena == "" {
+		return "", ""
+	}
+	pena = importExtensoParenRegex.ReplaceAllString(pena, "$1")
+
+	// Números por extenso → dígitos (mantém a grafia original das demais palavras)
+	palavras := strings.Fields(pena)
+	for i, palavra := range palavras {
+		core := strings.TrimFunc(palavra, func(r rune) bool { return !unicode.IsLetter(r) })
+		if n, ok := numerosExtenso[strings.ToLower(RemoveAccents(core))]; ok && core != "" {
+			palavras[i] = strings.Replace(palavra, core, n, 1)
+		}
+	}
+	pena = strings.Join(palavras, " ")
+
+	r, size := utf8.DecodeRuneInString(pena)
+	pena = string(unicode.ToUpper(r)) + pena[size:]
+
+	loc := penaRegex.FindStringIndex(pena)
+	if loc == nil {
+		return pena, ""
+	}
+	return strings.TrimSpace(pena[:loc[1]]), strings.TrimSpace(strings.TrimLeft(pena[loc[1]:], " ,;"))
+}
+
+// DiffArtigosPenais compara a base atual com a importação (apenas a legislação importada)
+func DiffArtigosPenais(atuais, importados []ArtigoPenal) PenalImportDiff {
+	diff := PenalImportDiff{Novos: []ArtigoPenal{}, Alterados: []PenalVersaoDiff{}, Ausentes: []string{}}
+
+	porId := make(map[string]ArtigoPenal, len(atuais))
+	for _, artigo := range atuais {
+		porId[artigo.IdUnico] = artigo
+	}
+
+	vistos := map[string]bool{}
+	for _, novo := range importados {
+		vistos[novo.IdUnico] = true
+		atual, ok := porId[novo.IdUnico]
+		if !ok {
+			diff.Novos = append(diff.Novos, novo)
+			continue
+		}
+		de, para := NewVersaoFromArtigo(atual), NewVersaoFromArtigo(novo)
+		if de.HashConteudo == para.HashConteudo {
+			diff.Iguais++
+			continue
+		}
+		diff.Alterados = append(diff.Alterados, DiffVersoes(de, para))
+	}
+
+	for _, atual := range atuais {
+		if !vistos[atual.IdUnico] {
+			diff.Ausentes = append(diff.Ausentes, atual.IdUnico)
+		}
+	}
+	sort.Strings(diff.Ausentes)
+
+	return diff
+}
+
+// planaltoLinhas remove marcação HTML e texto revogado (riscado) e retorna as linhas não vazias.
+// Páginas antigas do Planalto vêm em Windows-1252/Latin-1: convertidas para UTF-8.
+func planaltoLinhas(data []byte) []string {
+	text := string(data)
+	if !utf8.Valid(data) {
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		text = string(runes)
+	}
+
+	if strings.Contains(text, "<") {
+		text = importScriptRegex.ReplaceAllString(text, "")
+		text = importStrikeRegex.ReplaceAllString(text, "")
+		text = importBlockRegex.ReplaceAllString(text, "\n")
+		text = importTagRegex.ReplaceAllString(text, "")
+		text = html.UnescapeString(text)
+	}
+
+	linhas := []string{}
+	for _, linha := range strings.Split(text, "\n") {
+		linha = strings.Join(strings.Fields(strings.ReplaceAll(linha, " ", " ")), " ")
+		if linha != "" {
+			linhas = append(linhas, linha)
+		}
+	}
+	return linhas
+}
+
+// limparAnotacoes remove as anotações do Planalto, retornando o texto, a lei alteradora e se foi revogado/vetado
+func limparAnotacoes(texto string) (string, string, bool) {
+	lei, revogadoAnotado := "", false
+	for _, anotacao := range importAnotacaoRegex.FindAllString(texto, -1) {
+		lower := strings.ToLower(RemoveAccents(anotacao))
+		if strings.Contains(lower, "revogad") {
+			revogadoAnotado = true
+		}
+		if strings.Contains(lower, "vide") || strings.Contains(lower, "vigencia") {
+			continue
+		}
+		if m := importLeiRegex.FindStringSubmatch(anotacao); m != nil {
+			ano := m[3]
+			if len(ano) == 2 {
+				ano = "19" + ano
+			}
+			prefixo := "Lei"
+			if strings.Contains(strings.ToLower(m[1]), "complementar") {
+				prefixo = "Lei Complementar"
+			}
+			lei = fmt.Sprintf("%s %s/%s", prefixo, m[2], ano)
+		}
+	}
+	texto = strings.Join(strings.Fields(importAnotacaoRegex.ReplaceAllString(texto, "")), " ")
+
+	// Revogado/vetado: só a anotação sobrou ("I - (Revogado pela Lei nº ...)", "II - (VETADO)", "§ 3º Revogado.")
+	resto := strings.ToLower(RemoveAccents(strings.Trim(texto, " .;:-()")))
+	revogado := (revogadoAnotado && resto == "") || resto == "revogado" || resto == "revogada" || resto == "vetado"
+	return texto, lei, revogado
+}
+
+func planaltoCodigo(artigo, paragrafo, inciso string) string {
+	codigo := artigo
+	if paragrafo != "" {
+		codigo += "." + paragrafo
+	}
+	if inciso != "" {
+		codigo += "." + inciso
+	}
+	return codigo
+}
+
+// isRubrica rubrica (nome do crime) antes de um artigo/parágrafo: linha curta, sem pontuação final
+func isRubrica(linha string, linhas []string, i int) bool {
+	if len(linha) > 120 || strings.ContainsAny(linha[len(linha)-1:], ".:;,") {
+		return false
+	}
+	r, _ := utf8.DecodeRuneInString(linha)
+	if !unicode.IsUpper(r) {
+		return false
+	}
+	if i+1 >= len(linhas) {
+		return false
+	}
+	next := linhas[i+1]
+	return importArtigoRegex.MatchString(next) || importParagrafoRegex.MatchString(next) || importUnicoRegex.MatchString(next)
+}
+
+// isCaixaAlta linha toda em maiúsculas (nome de título/capítulo: "DOS CRIMES CONTRA A PESSOA")
+func isCaixaAlta(linha string) bool {
+	letras := 0
+	for _, r := range linha {
+		if unicode.IsLetter(r) {
+			if unicode.IsLower(r) {
+				return false
+			}
+			letras++
+		}
+	}
+	return letras >= 4
+}
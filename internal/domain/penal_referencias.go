@@ -0,0 +1,244 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// PenalReferencia aresta do grafo de citações entre dispositivos
+type PenalReferencia struct {
+	Origem    string    `json:"origem" bson:"origem"`   // idUnico do dispositivo que cita
+	Destino   string    `json:"destino" bson:"destino"` // idUnico citado (ou o superior mais próximo cadastrado)
+	Citado    string    `json:"citado" bson:"citado"`   // idUnico exatamente como citado ("CP:121.2.IV")
+	Existe    bool      `json:"existe" bson:"existe"`   // false = dispositivo citado não está na base
+	Trecho    string    `json:"trecho" bson:"trecho"`   // Trecho da citação: "arts. 33, 34 e 35 desta Lei"
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// PenalReferenciaItem aresta com o resumo do dispositivo da outra ponta (destino nas saídas, origem nas entradas)
+type PenalReferenciaItem struct {
+	PenalReferencia
+	Artigo *PenalResponse `json:"artigo,omitempty"` // nil quando o dispositivo citado não está na base
+}
+
+// PenalLegislacoesPorNumero número da lei/decreto-lei → código curto do idUnico
+var PenalLegislacoesPorNumero = map[string]string{
+	"2.848":  "CP",  // Decreto-Lei 2.848/1940
+	"3.688":  "LCP", // Decreto-Lei 3.688/1941
+	"11.343": "DRG",
+	"8.069":  "ECA",
+	"9.503":  "CTB",
+	"9.605":  "AMB",
+	"8.078":  "CDC",
+	"9.613":  "LVD",
+	"11.340": "MDP",
+	"10.826": "DES",
+}
+
+// penalLegislacoesPorNome nomes usuais (sem acento, minúsculo) → código curto
+var penalLegislacoesPorNome = []struct {
+	nome   string
+	codigo string
+}{
+	{"codigo penal", "CP"},
+	{"lei das contravencoes penais", "LCP"},
+	{"lei de contravencoes penais", "LCP"},
+	{"lei de drogas", "DRG"},
+	{"estatuto da crianca e do adolescente", "ECA"},
+	{"codigo de transito brasileiro", "CTB"},
+	{"lei de crimes ambientais", "AMB"},
+	{"codigo de defesa do consumidor", "CDC"},
+	{"lei de lavagem", "LVD"},
+	{"lei maria da penha", "MDP"},
+	{"estatuto do desarmamento", "DES"},
+}
+
+var (
+	refInicioRegex      = regexp.MustCompile(`(?i)\b(arts?\.|artigos?)\s*`)
+	refNumeroRegex      = regexp.MustCompile(`^(\d+)[º°o]?(?:-([A-Z]))?`)
+	refParagrafoRegex   = regexp.MustCompile(`^\s*,?\s*(?:§|par[áa]grafo)\s*(\d+)[º°o]?(?:-([A-Z]))?`)
+	refUnicoRegex       = regexp.MustCompile(`(?i)^\s*,?\s*par[áa]grafo\s+[úu]nico`)
+	refIncisoRegex      = regexp.MustCompile(`^\s*,?\s*(?:inciso\s+)?([IVXLC]+)\b`)
+	refSeparadorRegex   = regexp.MustCompile(`(?i)^\s*(,|e|ou|a|at[ée])\s+`)
+	refLeiNumeroRegex   = regexp.MustCompile(`(?i)^(?:lei|decreto-lei)(?:\s+complementar)?\s+n[º°o]?\.?\s*([\d.]+)`)
+	refDesteArtigoRegex = regexp.MustCompile(`(?i)§\s*(\d+)[º°o]?(?:-([A-Z]))?\s+deste\s+artigo`)
+)
+
+// refCitacao dispositivo citado (antes de resolver a legislação)
+type refCitacao struct {
+	codigo string
+}
+
+// ExtrairReferencias encontra citações a outros dispositivos no texto de um artigo.
+// legCode é o código curto da legislação do artigo (para "desta Lei", "deste Código" e citações sem lei).
+func ExtrairReferencias(artigo ArtigoPenal, legCode string) []PenalReferencia {
+	texto := artigo.TextoCompleto
+	if artigo.PenaMax != "" {
+		texto += " " + artigo.PenaMax // "sem prejuízo das penas do art. 129"
+	}
+
+	vistos := map[string]bool{artigo.IdUnico: true} // Sem auto-referência
+	refs := []PenalReferencia{}
+	add := func(citado, trecho string) {
+		if !vistos[citado] {
+			vistos[citado] = true
+			refs = append(refs, PenalReferencia{Origem: artigo.IdUnico, Citado: citado, Trecho: trecho})
+		}
+	}
+
+	// "arts. 33, 34 e 35 desta Lei", "art. 121, § 2º, IV, do Código Penal"
+	for _, loc := range refInicioRegex.FindAllStringIndex(texto, -1) {
+		citacoes, fim := parseListaArtigos(texto[loc[1]:])
+		if len(citacoes) == 0 {
+			continue
+		}
+		fim += loc[1]
+		leg, fimLei := legislacaoCitada(texto[fim:], legCode)
+		if leg == "" {
+			continue // Lei não mapeada
+		}
+		trecho := strings.TrimSpace(strings.TrimRight(texto[loc[0]:fim+fimLei], " ,"))
+		for _, c := range citacoes {
+			add(leg+":"+c.codigo, trecho)
+		}
+	}
+
+	// "§ 2º deste artigo"
+	base := PenalArtigoBase(artigo.Codigo)
+	for _, m := range refDesteArtigoRegex.FindAllStringSubmatch(texto, -1) {
+		paragrafo := m[1]
+		if m[2] != "" {
+			paragrafo += "-" + m[2]
+		}
+		add(legCode+":"+base+"."+paragrafo, m[0])
+	}
+
+	return refs
+}
+
+// parseListaArtigos lê "33, 34 e 35", "121, § 2º, IV", "33 a 37" e retorna os códigos e onde a lista termina
+func parseListaArtigos(s string) ([]refCitacao, int) {
+	citacoes := []refCitacao{}
+	pos := 0
+	anterior := -1 // Número do artigo anterior (para faixas "33 a 37")
+	faixa := false
+
+	for {
+		m := refNumeroRegex.FindStringSubmatch(s[pos:])
+		if m == nil {
+			break
+		}
+		pos += len(m[0])
+		codigo := m[1]
+		if m[2] != "" {
+			codigo += "-" + m[2]
+		}
+
+		// Faixa: expande os artigos intermediários (limitado para evitar textos malformados)
+		if faixa && anterior >= 0 && m[2] == "" {
+			if n, _ := strconv.Atoi(m[1]); n > anterior && n-anterior <= 50 {
+				for i := anterior + 1; i < n; i++ {
+					citacoes = append(citacoes, refCitacao{codigo: strconv.Itoa(i)})
+				}
+			}
+		}
+
+		if p := refParagrafoRegex.FindStringSubmatch(s[pos:]); p != nil {
+			pos += len(p[0])
+			codigo += "." + p[1]
+			if p[2] != "" {
+				codigo += "-" + p[2]
+			}
+		} else if p := refUnicoRegex.FindString(s[pos:]); p != "" {
+			pos += len(p)
+			codigo += ".unico"
+		}
+		if inc := refIncisoRegex.FindStringSubmatch(s[pos:]); inc != nil && romanToInt(inc[1]) > 0 {
+			pos += len(inc[0])
+			codigo += "." + inc[1]
+		}
+		citacoes = append(citacoes, refCitacao{codigo: codigo})
+
+		anterior = -1
+		if !strings.Contains(codigo, ".") && m[2] == "" {
+			anterior, _ = strconv.Atoi(m[1])
+		}
+
+		sep := refSeparadorRegex.FindStringSubmatch(s[pos:])
+		if sep == nil || !refNumeroRegex.MatchString(s[pos+len(sep[0]):]) {
+			break
+		}
+		word := strings.ToLower(RemoveAccents(sep[1]))
+		faixa = word == "a" || word == "ate"
+		pos += len(sep[0])
+	}
+
+	return citacoes, pos
+}
+
+// legislacaoCitada identifica a lei após a lista de artigos ("desta Lei", "do Código Penal",
+// "da Lei nº 11.343, de 2006"). Sem qualificador, a citação é da própria legislação.
+func legislacaoCitada(s, legCode string) (string, int) {
+	trimmed := strings.TrimLeft(s, " ,")
+	offset := len(s) - len(trimmed)
+	lower := strings.ToLower(RemoveAccents(trimmed))
+
+	// lower perde bytes ao remover acentos ("ó" → "o"): converte o tamanho casado de volta para o texto original
+	fim := func(casado string) int {
+		return offset + len(string([]rune(trimmed)[:utf8.RuneCountInString(casado)]))
+	}
+
+	for _, prefixo := range []string{"desta lei", "deste codigo", "deste decreto-lei", "nesta lei", "neste codigo"} {
+		if strings.HasPrefix(lower, prefixo) {
+			return legCode, fim(prefixo)
+		}
+	}
+
+	for _, artigo := range []string{"do ", "da ", "dos ", "das ", "no ", "na "} {
+		if !strings.HasPrefix(lower, artigo) {
+			continue
+		}
+		resto := lower[len(artigo):]
+		restoOriginal := trimmed[len(artigo):]
+		if m := refLeiNumeroRegex.FindStringSubmatch(restoOriginal); m != nil {
+			if codigo, ok := PenalLegislacoesPorNumero[strings.TrimRight(m[1], ".")]; ok {
+				return codigo, fim(artigo + m[0])
+			}
+			return "", 0
+		}
+		for _, lei := range penalLegislacoesPorNome {
+			if strings.HasPrefix(resto, lei.nome) {
+				return lei.codigo, fim(artigo + lei.nome)
+			}
+		}
+		if strings.HasPrefix(resto, "lei") || strings.HasPrefix(resto, "decreto") || strings.HasPrefix(resto, "codigo") || strings.HasPrefix(resto, "estatuto") {
+			return "", 0 // Outra lei não mapeada
+		}
+	}
+
+	return legCode, 0
+}
+
+// ResolverReferencias aponta cada citação para o dispositivo cadastrado mais próximo
+// ("CP:121.2.IV" → "CP:121.2" se o inciso não estiver na base)
+func ResolverReferencias(refs []PenalReferencia, existentes map[string]bool) []PenalReferencia {
+	for i, ref := range refs {
+		refs[i].Destino = ref.Citado
+		refs[i].Existe = existentes[ref.Citado]
+		if refs[i].Existe {
+			continue
+		}
+		parts := strings.SplitN(ref.Citado, ":", 2)
+		for pai := PenalCodigoPai(parts[1]); pai != ""; pai = PenalCodigoPai(pai) {
+			if id := parts[0] + ":" + pai; existentes[id] {
+				refs[i].Destino = id
+				refs[i].Existe = true
+				break
+			}
+		}
+	}
+	return refs
+}
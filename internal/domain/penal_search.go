@@ -0,0 +1,370 @@
+package domain
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Parâmetros do BM25
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Pesos por campo (BM25F simplificado): a descrição vale mais que o texto da lei
+const (
+	pesoDescricao       = 3.0
+	pesoCodigoFormatado = 2.0
+	pesoTextoCompleto   = 1.0
+)
+
+// stopwordsPT palavras sem valor para a busca (já sem acento)
+var stopwordsPT = map[string]bool{
+	"a": true, "o": true, "as": true, "os": true, "um": true, "uma": true, "uns": true, "umas": true,
+	"de": true, "do": true, "da": true, "dos": true, "das": true, "no": true, "na": true, "nos": true, "nas": true,
+	"em": true, "por": true, "para": true, "pelo": true, "pela": true, "pelos": true, "pelas": true,
+	"ao": true, "aos": true, "e": true, "ou": true, "que": true, "se": true, "com": true, "sem": true,
+	"ate": true, "sua": true, "seu": true, "suas": true, "seus": true, "ser": true, "ha": true,
+	"art": true, "artigo": true, "lei": true,
+}
+
+// PenalSearchResult resultado de busca com relevância e trechos destacados
+type PenalSearchResult struct {
+	PenalResponse
+	Score             float64 `json:"score"`
+	DescricaoDestaque string  `json:"descricaoDestaque"` // Descrição com <mark> nos termos encontrados
+	Trecho            string  `json:"trecho,omitempty"`  // Trecho do texto completo com <mark>
+}
+
+// PenalSearchOptions filtros e paginação da busca
+type PenalSearchOptions struct {
+	Legislacao string // "CP", "Lei 11.343/2006" ou código curto do idUnico ("DRG")
+	Tipo       string // "crime" ou "contravencao"
+	Limit      int
+	Offset     int
+}
+
+// PenalSearchIndex índice invertido em memória dos artigos penais (BM25)
+type PenalSearchIndex struct {
+	artigos    []ArtigoPenal
+	postings   map[string]map[int]float64 // termo → doc → frequência ponderada
+	docLen     []float64                  // tamanho ponderado de cada documento
+	avgDocLen  float64
+	vocabulary []string // termos ordenados (expansão por prefixo)
+	BuiltAt    time.Time
+}
+
+// NewPenalSearchIndex constrói o índice a partir dos artigos
+func NewPenalSearchIndex(artigos []ArtigoPenal) *PenalSearchIndex {
+	idx := &PenalSearchIndex{
+		artigos:  artigos,
+		postings: make(map[string]map[int]float64),
+		docLen:   make([]float64, len(artigos)),
+		BuiltAt:  time.Now(),
+	}
+
+	total := 0.0
+	for i, artigo := range artigos {
+		fields := []struct {
+			text string
+			peso float64
+		}{
+			{artigo.Descricao, pesoDescricao},
+			{artigo.CodigoFormatado, pesoCodigoFormatado},
+			{artigo.TextoCompleto, pesoTextoCompleto},
+		}
+		for _, field := range fields {
+			for _, term := range AnalyzePT(field.text) {
+				if idx.postings[term] == nil {
+					idx.postings[term] = make(map[int]float64)
+				}
+				idx.postings[term][i] += field.peso
+				idx.docLen[i] += field.peso
+			}
+		}
+		total += idx.docLen[i]
+	}
+	if len(artigos) > 0 {
+		idx.avgDocLen = total / float64(len(artigos))
+	}
+
+	idx.vocabulary = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.vocabulary = append(idx.vocabulary, term)
+	}
+	sort.Strings(idx.vocabulary)
+
+	return idx
+}
+
+// Size retorna a quantidade de artigos indexados
+func (idx *PenalSearchIndex) Size() int {
+	return len(idx.artigos)
+}
+
+// Search busca artigos ordenados por relevância (BM25); retorna a página e o total de resultados
+func (idx *PenalSearchIndex) Search(query string, opts PenalSearchOptions) ([]PenalSearchResult, int) {
+	queryTerms := idx.expandTerms(AnalyzePT(query))
+	if len(queryTerms) == 0 {
+		return []PenalSearchResult{}, 0
+	}
+
+	n := float64(len(idx.artigos))
+	scores := make(map[int]float64)
+	for term, boost := range queryTerms {
+		postings := idx.postings[term]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for doc, tf := range postings {
+			if !idx.matchesFilters(doc, opts) {
+				continue
+			}
+			norm := tf + bm25K1*(1-bm25B+bm25B*idx.docLen[doc]/idx.avgDocLen)
+			scores[doc] += boost * idf * tf * (bm25K1 + 1) / norm
+		}
+	}
+
+	docs := make([]int, 0, len(scores))
+	for doc := range scores {
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool {
+		if scores[docs[i]] != scores[docs[j]] {
+			return scores[docs[i]] > scores[docs[j]]
+		}
+		return idx.artigos[docs[i]].IdUnico < idx.artigos[docs[j]].IdUnico
+	})
+
+	total := len(docs)
+	if opts.Offset >= total {
+		return []PenalSearchResult{}, total
+	}
+	end := total
+	if opts.Limit > 0 && opts.Offset+opts.Limit < total {
+		end = opts.Offset + opts.Limit
+	}
+
+	results := make([]PenalSearchResult, 0, end-opts.Offset)
+	for _, doc := range docs[opts.Offset:end] {
+		artigo := idx.artigos[doc]
+		results = append(results, PenalSearchResult{
+			PenalResponse: PenalResponse{
+				Codigo:          artigo.Codigo,
+				CodigoFormatado: artigo.CodigoFormatado,
+				Descricao:       artigo.Descricao,
+				Tipo:            artigo.Tipo,
+				Legislacao:      artigo.Legislacao,
+				LegislacaoNome:  artigo.LegislacaoNome,
+				IdUnico:         artigo.IdUnico,
+			},
+			Score:             math.Round(scores[doc]*1000) / 1000,
+			DescricaoDestaque: highlightTerms(artigo.Descricao, queryTerms, 0),
+			Trecho:            highlightTerms(artigo.TextoCompleto, queryTerms, 30),
+		})
+	}
+
+	return results, total
+}
+
+// expandTerms mantém os termos existentes no vocabulário; termos desconhecidos (ex: palavra
+// incompleta) são expandidos para os termos com o mesmo prefixo, com peso reduzido
+func (idx *PenalSearchIndex) expandTerms(terms []string) map[string]float64 {
+	expanded := make(map[string]float64)
+	for _, term := range terms {
+		if _, ok := idx.postings[term]; ok {
+			expanded[term] = 1
+			continue
+		}
+		if len(term) < 3 {
+			continue
+		}
+		start := sort.SearchStrings(idx.vocabulary, term)
+		for i := start; i < len(idx.vocabulary) && strings.HasPrefix(idx.vocabulary[i], term); i++ {
+			if _, ok := expanded[idx.vocabulary[i]]; !ok {
+				expanded[idx.vocabulary[i]] = 0.5
+			}
+		}
+	}
+	return expanded
+}
+
+// matchesFilters aplica filtros de legislação e tipo
+func (idx *PenalSearchIndex) matchesFilters(doc int, opts PenalSearchOptions) bool {
+	artigo := idx.artigos[doc]
+	if opts.Tipo != "" && artigo.Tipo != opts.Tipo {
+		return false
+	}
+	if opts.Legislacao != "" && !matchesLegislacao(artigo, opts.Legislacao) {
+		return false
+	}
+	return true
+}
+
+// ========================================
+// Análise de texto (português)
+// ========================================
+
+// AnalyzePT tokeniza, remove acentos e stopwords e aplica stemming leve em português
+func AnalyzePT(text string) []string {
+	words := tokenizePT(text)
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if stopwordsPT[w.norm] {
+			continue
+		}
+		terms = append(terms, StemPT(w.norm))
+	}
+	return terms
+}
+
+type ptToken struct {
+	start, end int    // posição (bytes) no texto original
+	norm       string // minúsculo e sem acento
+}
+
+// tokenizePT separa palavras (letras/dígitos) preservando as posições no texto original
+func tokenizePT(text string) []ptToken {
+	tokens := []ptToken{}
+	start := -1
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, ptToken{
+				start: start,
+				end:   end,
+				norm:  strings.ToLower(RemoveAccents(text[start:end])),
+			})
+			start = -1
+		}
+	}
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+		} else {
+			flush(i)
+		}
+	}
+	flush(len(text))
+	return tokens
+}
+
+// StemPT stemmer leve para português (sufixos de plural, gênero e advérbios em -mente)
+// Ex: "homicídios" → "homicidi", "corrupção" → "corrupca", "roubos" → "roub"
+func StemPT(word string) string {
+	if len(word) <= 3 || isNumeric(word) {
+		return word
+	}
+
+	if strings.HasSuffix(word, "mente") && len(word) > 7 {
+		word = strings.TrimSuffix(word, "mente")
+	}
+
+	// Plurais
+	switch {
+	case strings.HasSuffix(word, "oes"), strings.HasSuffix(word, "aes"):
+		word = word[:len(word)-3] + "ao"
+	case strings.HasSuffix(word, "ais"):
+		word = word[:len(word)-3] + "al"
+	case strings.HasSuffix(word, "eis"):
+		word = word[:len(word)-3] + "el"
+	case strings.HasSuffix(word, "ois"):
+		word = word[:len(word)-3] + "ol"
+	case strings.HasSuffix(word, "ns"):
+		word = word[:len(word)-2] + "m"
+	case strings.HasSuffix(word, "res"), strings.HasSuffix(word, "zes"), strings.HasSuffix(word, "les"):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "is") && !strings.HasSuffix(word, "us"):
+		word = word[:len(word)-1]
+	}
+
+	// Gênero / vogal temática
+	if len(word) > 3 {
+		switch word[len(word)-1] {
+		case 'a', 'e', 'o':
+			word = word[:len(word)-1]
+		}
+	}
+
+	return word
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// highlightTerms envolve em <mark> as palavras cujo stem está na consulta.
+// Com window > 0, retorna apenas um trecho de ~window palavras ao redor da primeira ocorrência.
+// O texto é escapado (HTML): apenas as tags <mark> são marcação.
+func highlightTerms(text string, terms map[string]float64, window int) string {
+	tokens := tokenizePT(text)
+
+	matches := make([]bool, len(tokens))
+	first := -1
+	for i, tok := range tokens {
+		if _, ok := terms[StemPT(tok.norm)]; ok && !stopwordsPT[tok.norm] {
+			matches[i] = true
+			if first < 0 {
+				first = i
+			}
+		}
+	}
+
+	if len(tokens) == 0 {
+		return html.EscapeString(text)
+	}
+	if first < 0 {
+		first = 0 // Sem ocorrência no texto (ex: só na descrição): trecho inicial
+	}
+
+	from, to := 0, len(tokens)
+	if window > 0 {
+		from = first - window/3
+		if from < 0 {
+			from = 0
+		}
+		to = from + window
+		if to > len(tokens) {
+			to = len(tokens)
+		}
+	}
+	if from >= to {
+		return html.EscapeString(text)
+	}
+
+	var sb strings.Builder
+	startByte := tokens[from].start
+	endByte := len(text)
+	if to < len(tokens) {
+		endByte = tokens[to-1].end
+	}
+	if from > 0 {
+		sb.WriteString("…")
+	}
+	pos := startByte
+	for i := from; i < to; i++ {
+		if !matches[i] {
+			continue
+		}
+		sb.WriteString(html.EscapeString(text[pos:tokens[i].start]))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(text[tokens[i].start:tokens[i].end]))
+		sb.WriteString("</mark>")
+		pos = tokens[i].end
+	}
+	sb.WriteString(html.EscapeString(text[pos:endByte]))
+	if endByte < len(text) {
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
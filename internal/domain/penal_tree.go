@@ -0,0 +1,335 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Níveis de um dispositivo legal
+const (
+	PenalNivelArtigo    = "artigo"
+	PenalNivelParagrafo = "paragrafo"
+	PenalNivelInciso    = "inciso"
+	PenalNivelAlinea    = "alinea"
+)
+
+// PenalDivisao divisão de uma legislação (parte, título, capítulo, seção) por faixa de artigos
+type PenalDivisao struct {
+	Tipo         string         `json:"tipo" bson:"tipo"` // "parte", "titulo", "capitulo", "secao"
+	Numero       string         `json:"numero,omitempty" bson:"numero,omitempty"`
+	Nome         string         `json:"nome" bson:"nome"`
+	ArtigoInicio string         `json:"artigoInicio" bson:"artigoInicio"` // "121"
+	ArtigoFim    string         `json:"artigoFim" bson:"artigoFim"`       // "154-B"
+	Divisoes     []PenalDivisao `json:"divisoes,omitempty" bson:"divisoes,omitempty"`
+	Artigos      []PenalSumario `json:"artigos,omitempty" bson:"artigos,omitempty"`
+}
+
+// PenalDivisaoRef referência a uma divisão (breadcrumb do artigo)
+type PenalDivisaoRef struct {
+	Tipo   string `json:"tipo" bson:"tipo"`
+	Numero string `json:"numero,omitempty" bson:"numero,omitempty"`
+	Nome   string `json:"nome" bson:"nome"`
+}
+
+// PenalSumario entrada de artigo no sumário
+type PenalSumario struct {
+	IdUnico   string `json:"idUnico" bson:"idUnico"`
+	Codigo    string `json:"codigo" bson:"codigo"`
+	Rotulo    string `json:"rotulo" bson:"rotulo"` // "Art. 121"
+	Descricao string `json:"descricao" bson:"descricao"`
+	Tipo      string `json:"tipo" bson:"tipo"`
+}
+
+// PenalEstrutura sumário (índice) de uma legislação, gerado no seed
+type PenalEstrutura struct {
+	Legislacao     string         `json:"legislacao" bson:"legislacao"`         // "CP", "Lei 11.343/2006"
+	Codigo         string         `json:"codigo" bson:"codigo"`                 // Código curto do idUnico: "CP", "DRG"
+	LegislacaoNome string         `json:"legislacaoNome" bson:"legislacaoNome"` // "Código Penal"
+	Divisoes       []PenalDivisao `json:"divisoes" bson:"divisoes"`
+	Artigos        []PenalSumario `json:"artigos" bson:"artigos"` // Artigos fora de qualquer divisão cadastrada
+	TotalArtigos   int            `json:"totalArtigos" bson:"totalArtigos"`
+	UpdatedAt      time.Time      `json:"updatedAt" bson:"updatedAt"`
+}
+
+// PenalEstruturaSeed divisões cadastradas no arquivo penal_estrutura.json
+type PenalEstruturaSeed struct {
+	Legislacao string         `json:"legislacao"`
+	Divisoes   []PenalDivisao `json:"divisoes"`
+}
+
+// PenalNo nó da árvore de um artigo (caput → parágrafos → incisos → alíneas)
+type PenalNo struct {
+	IdUnico         string     `json:"idUnico,omitempty" bson:"idUnico,omitempty"`
+	Codigo          string     `json:"codigo" bson:"codigo"`
+	CodigoFormatado string     `json:"codigoFormatado,omitempty" bson:"codigoFormatado,omitempty"`
+	Nivel           string     `json:"nivel" bson:"nivel"`
+	Rotulo          string     `json:"rotulo" bson:"rotulo"` // "Art. 121", "§ 1º", "II", "a)"
+	Descricao       string     `json:"descricao,omitempty" bson:"descricao,omitempty"`
+	TextoCompleto   string     `json:"textoCompleto,omitempty" bson:"textoCompleto,omitempty"`
+	Tipo            string     `json:"tipo,omitempty" bson:"tipo,omitempty"`
+	PenaMin         string     `json:"penaMin,omitempty" bson:"penaMin,omitempty"`
+	PenaMax         string     `json:"penaMax,omitempty" bson:"penaMax,omitempty"`
+	Cadastrado      bool       `json:"cadastrado" bson:"cadastrado"` // false = nó intermediário sem texto na base
+	Filhos          []*PenalNo `json:"filhos" bson:"filhos"`
+}
+
+// PenalArvore árvore de um artigo com a legislação e a localização, gerada no seed
+type PenalArvore struct {
+	IdUnico        string            `json:"-" bson:"idUnico"` // Caput: "CP:121"
+	Legislacao     string            `json:"legislacao" bson:"legislacao"`
+	LegislacaoNome string            `json:"legislacaoNome" bson:"legislacaoNome"`
+	Localizacao    []PenalDivisaoRef `json:"localizacao" bson:"localizacao"`
+	Arvore         *PenalNo          `json:"arvore" bson:"arvore"`
+	UpdatedAt      time.Time         `json:"-" bson:"updatedAt"`
+}
+
+// PenalArtigoBase retorna o número do artigo de um código ("121.1.I.a" → "121")
+func PenalArtigoBase(codigo string) string {
+	if idx := strings.Index(codigo, "."); idx != -1 {
+		return codigo[:idx]
+	}
+	return codigo
+}
+
+// PenalCodigoPai retorna o código do dispositivo superior ("121.1.I" → "121.1"; "121" → "")
+func PenalCodigoPai(codigo string) string {
+	if idx := strings.LastIndex(codigo, "."); idx != -1 {
+		return codigo[:idx]
+	}
+	return ""
+}
+
+// PenalNivel identifica o nível do dispositivo pelo último segmento do código
+func PenalNivel(codigo string) string {
+	segments := strings.Split(codigo, ".")
+	if len(segments) == 1 {
+		return PenalNivelArtigo
+	}
+	return nivelSegmento(segments[len(segments)-1])
+}
+
+// nivelSegmento classifica um segmento abaixo do artigo: número = parágrafo,
+// romano maiúsculo = inciso, minúsculo = alínea (evita confundir a alínea "c" com C romano)
+func nivelSegmento(seg string) string {
+	switch {
+	case isNumeric(strings.SplitN(seg, "-", 2)[0]) || strings.EqualFold(seg, "unico"): // "2", "2-A", "unico"
+		return PenalNivelParagrafo
+	case seg == strings.ToUpper(seg) && romanToInt(seg) > 0:
+		return PenalNivelInciso
+	default:
+		return PenalNivelAlinea
+	}
+}
+
+// PenalOrdem gera uma chave de ordenação natural ("149-A" depois de "149", "§ 10" depois de "§ 2º")
+// Incisos do caput vêm antes dos parágrafos, como no texto da lei.
+func PenalOrdem(codigo string) string {
+	segments := strings.Split(codigo, ".")
+	parts := make([]string, 0, len(segments))
+	parts = append(parts, artigoOrdem(segments[0]))
+	for _, seg := range segments[1:] {
+		switch nivelSegmento(seg) {
+		case PenalNivelParagrafo:
+			ordem := "000" // Parágrafo único
+			if !strings.EqualFold(seg, "unico") {
+				ordem = artigoOrdem(seg)[2:] // "2-A" → "002-A"
+			}
+			parts = append(parts, "p"+ordem)
+		case PenalNivelInciso:
+			parts = append(parts, fmt.Sprintf("i%03d", romanToInt(seg)))
+		default:
+			parts = append(parts, "a"+strings.ToLower(seg))
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// artigoOrdem "149-A" → "00149-A"
+func artigoOrdem(artigo string) string {
+	num, suffix := artigo, ""
+	if idx := strings.Index(artigo, "-"); idx != -1 {
+		num, suffix = artigo[:idx], artigo[idx:]
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil {
+		return artigo
+	}
+	return fmt.Sprintf("%05d%s", n, strings.ToUpper(suffix))
+}
+
+// PenalRotulo rótulo de exibição do último segmento ("Art. 121", "§ 1º", "Parágrafo único", "II", "a)")
+func PenalRotulo(codigo string) string {
+	segments := strings.Split(codigo, ".")
+	last := segments[len(segments)-1]
+	switch PenalNivel(codigo) {
+	case PenalNivelArtigo:
+		return "Art. " + last
+	case PenalNivelParagrafo:
+		if strings.EqualFold(last, "unico") {
+			return "Parágrafo único"
+		}
+		num, suffix := last, "" // "2-A" → "§ 2º-A"
+		if idx := strings.Index(last, "-"); idx != -1 {
+			num, suffix = last[:idx], last[idx:]
+		}
+		if n, _ := strconv.Atoi(num); n < 10 {
+			return fmt.Sprintf("§ %dº%s", n, suffix)
+		}
+		return "§ " + last
+	case PenalNivelInciso:
+		return strings.ToUpper(last)
+	default:
+		return strings.ToLower(last) + ")"
+	}
+}
+
+// romanToInt converte numeral romano (0 se inválido)
+func romanToInt(s string) int {
+	values := map[rune]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100}
+	total, prev := 0, 0
+	runes := []rune(strings.ToUpper(s))
+	for i := len(runes) - 1; i >= 0; i-- {
+		v, ok := values[runes[i]]
+		if !ok {
+			return 0
+		}
+		if v < prev {
+			total -= v
+		} else {
+			total += v
+			prev = v
+		}
+	}
+	return total
+}
+
+// LocalizarArtigo retorna o caminho de divisões (mais externa → mais interna) que contém o artigo
+func LocalizarArtigo(divisoes []PenalDivisao, artigo string) []PenalDivisaoRef {
+	ordem := artigoOrdem(artigo)
+	for _, div := range divisoes {
+		if ordem < artigoOrdem(div.ArtigoInicio) || ordem > artigoOrdem(div.ArtigoFim) {
+			continue
+		}
+		ref := PenalDivisaoRef{Tipo: div.Tipo, Numero: div.Numero, Nome: div.Nome}
+		return append([]PenalDivisaoRef{ref}, LocalizarArtigo(div.Divisoes, artigo)...)
+	}
+	return nil
+}
+
+// BuildPenalEstrutura monta o sumário de uma legislação distribuindo os artigos (caput) nas divisões
+func BuildPenalEstrutura(codigo string, artigos []ArtigoPenal, divisoes []PenalDivisao) PenalEstrutura {
+	estrutura := PenalEstrutura{
+		Codigo:   codigo,
+		Divisoes: clonePenalDivisoes(divisoes),
+		Artigos:  []PenalSumario{},
+	}
+
+	caputs := make([]ArtigoPenal, 0, len(artigos))
+	for _, a := range artigos {
+		if PenalNivel(a.Codigo) == PenalNivelArtigo {
+			caputs = append(caputs, a)
+		}
+		if estrutura.Legislacao == "" {
+			estrutura.Legislacao = a.Legislacao
+			estrutura.LegislacaoNome = a.LegislacaoNome
+		}
+	}
+	sort.Slice(caputs, func(i, j int) bool {
+		return PenalOrdem(caputs[i].Codigo) < PenalOrdem(caputs[j].Codigo)
+	})
+
+	for _, a := range caputs {
+		sumario := PenalSumario{
+			IdUnico:   a.IdUnico,
+			Codigo:    a.Codigo,
+			Rotulo:    PenalRotulo(a.Codigo),
+			Descricao: a.Descricao,
+			Tipo:      a.Tipo,
+		}
+		if !addToDivisao(estrutura.Divisoes, a.Codigo, sumario) {
+			estrutura.Artigos = append(estrutura.Artigos, sumario)
+		}
+	}
+	estrutura.TotalArtigos = len(caputs)
+
+	return estrutura
+}
+
+// addToDivisao adiciona o artigo à divisão mais interna que o contém
+func addToDivisao(divisoes []PenalDivisao, artigo string, sumario PenalSumario) bool {
+	ordem := artigoOrdem(artigo)
+	for i := range divisoes {
+		div := &divisoes[i]
+		if ordem < artigoOrdem(div.ArtigoInicio) || ordem > artigoOrdem(div.ArtigoFim) {
+			continue
+		}
+		if !addToDivisao(div.Divisoes, artigo, sumario) {
+			div.Artigos = append(div.Artigos, sumario)
+		}
+		return true
+	}
+	return false
+}
+
+func clonePenalDivisoes(divisoes []PenalDivisao) []PenalDivisao {
+	if divisoes == nil {
+		return []PenalDivisao{}
+	}
+	clone := make([]PenalDivisao, len(divisoes))
+	for i, d := range divisoes {
+		clone[i] = d
+		clone[i].Artigos = nil
+		clone[i].Divisoes = clonePenalDivisoes(d.Divisoes)
+	}
+	return clone
+}
+
+// BuildPenalArvore monta a árvore de um artigo a partir de todos os seus dispositivos cadastrados.
+// Dispositivos intermediários ausentes na base (ex: § 2º sem texto próprio) viram nós não cadastrados.
+func BuildPenalArvore(artigos []ArtigoPenal) *PenalNo {
+	if len(artigos) == 0 {
+		return nil
+	}
+
+	sorted := append([]ArtigoPenal{}, artigos...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return PenalOrdem(sorted[i].Codigo) < PenalOrdem(sorted[j].Codigo)
+	})
+
+	nodes := make(map[string]*PenalNo)
+	var getNode func(codigo string) *PenalNo
+	getNode = func(codigo string) *PenalNo {
+		if node, ok := nodes[codigo]; ok {
+			return node
+		}
+		node := &PenalNo{
+			Codigo: codigo,
+			Nivel:  PenalNivel(codigo),
+			Rotulo: PenalRotulo(codigo),
+			Filhos: []*PenalNo{},
+		}
+		nodes[codigo] = node
+		if pai := PenalCodigoPai(codigo); pai != "" {
+			parent := getNode(pai)
+			parent.Filhos = append(parent.Filhos, node)
+		}
+		return node
+	}
+
+	for _, a := range sorted {
+		node := getNode(a.Codigo)
+		node.IdUnico = a.IdUnico
+		node.CodigoFormatado = a.CodigoFormatado
+		node.Descricao = a.Descricao
+		node.TextoCompleto = a.TextoCompleto
+		node.Tipo = a.Tipo
+		node.PenaMin = a.PenaMin
+		node.PenaMax = a.PenaMax
+		node.Cadastrado = true
+	}
+
+	return nodes[PenalArtigoBase(sorted[0].Codigo)]
+}
@@ -0,0 +1,190 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// DataLayout formato das datas de vigência (YYYY-MM-DD, ordenável como string)
+const DataLayout = "2006-01-02"
+
+// ArtigoPenalVersao redação de um dispositivo penal em um período de vigência
+type ArtigoPenalVersao struct {
+	ID            string    `json:"-" bson:"_id,omitempty"`
+	IdUnico       string    `json:"idUnico" bson:"idUnico"`
+	Versao        int       `json:"versao" bson:"versao"` // 1 = redação mais antiga conhecida
+	Descricao     string    `json:"descricao" bson:"descricao"`
+	TextoCompleto string    `json:"textoCompleto" bson:"textoCompleto"`
+	PenaMin       string    `json:"penaMin,omitempty" bson:"penaMin,omitempty"`
+	PenaMax       string    `json:"penaMax,omitempty" bson:"penaMax,omitempty"`
+	HashConteudo  string    `json:"hashConteudo" bson:"hashConteudo"`                       // SHA256 de descrição + texto + pena
+	VigenteDesde  string    `json:"vigenteDesde,omitempty" bson:"vigenteDesde,omitempty"`   // "" = desde a redação original (data desconhecida)
+	VigenteAte    string    `json:"vigenteAte,omitempty" bson:"vigenteAte,omitempty"`       // "" = em vigor
+	LeiAlteradora string    `json:"leiAlteradora,omitempty" bson:"leiAlteradora,omitempty"` // "Lei 12.015/2009" (vazio na redação original)
+	Fonte         string    `json:"fonte,omitempty" bson:"fonte,omitempty"`
+	CreatedAt     time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// PenalVersoesSeed redações anteriores e vigência da redação atual (seeds/penal_versoes.json)
+type PenalVersoesSeed struct {
+	IdUnico       string              `json:"idUnico"`
+	VigenteDesde  string              `json:"vigenteDesde"`  // Vigência da redação atual (penal.json)
+	LeiAlteradora string              `json:"leiAlteradora"` // Lei que deu a redação atual
+	Anteriores    []ArtigoPenalVersao `json:"anteriores"`    // Da mais antiga para a mais recente
+}
+
+// DiffTrecho trecho do diff palavra a palavra
+type DiffTrecho struct {
+	Op    string `json:"op"` // "igual", "inserido", "removido"
+	Texto string `json:"texto"`
+}
+
+// PenalVersaoDiff diferenças entre duas redações
+type PenalVersaoDiff struct {
+	IdUnico       string       `json:"idUnico"`
+	De            int          `json:"de"`
+	Para          int          `json:"para"`
+	Alterados     []string     `json:"alterados"` // Campos alterados: descricao, textoCompleto, penaMin, penaMax
+	Descricao     []DiffTrecho `json:"descricao"`
+	Texto         []DiffTrecho `json:"texto"`
+	PenaMin       []DiffTrecho `json:"penaMin"`
+	PenaMax       []DiffTrecho `json:"penaMax"`
+	LeiAlteradora string       `json:"leiAlteradora,omitempty"` // Lei que introduziu a redação "para"
+}
+
+// HashVersaoPenal hash do conteúdo normativo (texto + pena); muda quando a redação muda
+func HashVersaoPenal(descricao, texto, penaMin, penaMax string) string {
+	hash := sha256.Sum256([]byte(descricao + "\x00" + texto + "\x00" + penaMin + "\x00" + penaMax))
+	return hex.EncodeToString(hash[:])
+}
+
+// NewVersaoFromArtigo cria a versão correspondente à redação atual do artigo
+func NewVersaoFromArtigo(artigo ArtigoPenal) ArtigoPenalVersao {
+	return ArtigoPenalVersao{
+		IdUnico:       artigo.IdUnico,
+		Descricao:     artigo.Descricao,
+		TextoCompleto: artigo.TextoCompleto,
+		PenaMin:       artigo.PenaMin,
+		PenaMax:       artigo.PenaMax,
+		HashConteudo:  HashVersaoPenal(artigo.Descricao, artigo.TextoCompleto, artigo.PenaMin, artigo.PenaMax),
+		VigenteDesde:  artigo.VigenteDesde,
+		LeiAlteradora: artigo.LeiAlteradora,
+		Fonte:         artigo.Fonte,
+	}
+}
+
+// VigenteEm indica se a versão estava em vigor na data (YYYY-MM-DD)
+func (v ArtigoPenalVersao) VigenteEm(data string) bool {
+	if v.VigenteDesde != "" && data < v.VigenteDesde {
+		return false
+	}
+	return v.VigenteAte == "" || data <= v.VigenteAte
+}
+
+// VersaoVigenteEm retorna a versão em vigor na data, ou nil se o dispositivo não existia
+func VersaoVigenteEm(versoes []ArtigoPenalVersao, data string) *ArtigoPenalVersao {
+	for i := len(versoes) - 1; i >= 0; i-- {
+		if versoes[i].VigenteEm(data) {
+			return &versoes[i]
+		}
+	}
+	return nil
+}
+
+// DiaAnterior retorna a data (YYYY-MM-DD) do dia anterior; vazio se a data for inválida
+func DiaAnterior(data string) string {
+	t, err := time.Parse(DataLayout, data)
+	if err != nil {
+		return ""
+	}
+	return t.AddDate(0, 0, -1).Format(DataLayout)
+}
+
+// DiffVersoes compara duas redações campo a campo (texto com diff palavra a palavra)
+func DiffVersoes(de, para ArtigoPenalVersao) PenalVersaoDiff {
+	diff := PenalVersaoDiff{
+		IdUnico:       para.IdUnico,
+		De:            de.Versao,
+		Para:          para.Versao,
+		Alterados:     []string{},
+		Descricao:     DiffPalavras(de.Descricao, para.Descricao),
+		Texto:         DiffPalavras(de.TextoCompleto, para.TextoCompleto),
+		PenaMin:       DiffPalavras(de.PenaMin, para.PenaMin),
+		PenaMax:       DiffPalavras(de.PenaMax, para.PenaMax),
+		LeiAlteradora: para.LeiAlteradora,
+	}
+
+	campos := []struct {
+		nome     string
+		de, para string
+	}{
+		{"descricao", de.Descricao, para.Descricao},
+		{"textoCompleto", de.TextoCompleto, para.TextoCompleto},
+		{"penaMin", de.PenaMin, para.PenaMin},
+		{"penaMax", de.PenaMax, para.PenaMax},
+	}
+	for _, campo := range campos {
+		if campo.de != campo.para {
+			diff.Alterados = append(diff.Alterados, campo.nome)
+		}
+	}
+
+	return diff
+}
+
+// DiffPalavras diff palavra a palavra (LCS); trechos consecutivos com a mesma operação são agrupados
+func DiffPalavras(antes, depois string) []DiffTrecho {
+	a, b := strings.Fields(antes), strings.Fields(depois)
+
+	// lcs[i][j] = tamanho da maior subsequência comum de a[i:] e b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	trechos := []DiffTrecho{}
+	add := func(op, palavra string) {
+		if n := len(trechos); n > 0 && trechos[n-1].Op == op {
+			trechos[n-1].Texto += " " + palavra
+			return
+		}
+		trechos = append(trechos, DiffTrecho{Op: op, Texto: palavra})
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add("igual", a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			add("removido", a[i])
+			i++
+		default:
+			add("inserido", b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add("removido", a[i])
+	}
+	for ; j < len(b); j++ {
+		add("inserido", b[j])
+	}
+
+	return trechos
+}
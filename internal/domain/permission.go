@@ -0,0 +1,199 @@
+package domain
+
+import (
+	"path"
+	"strings"
+)
+
+// Níveis de acesso das permissões
+const (
+	PermissionAccessRead  = "read"  // Consultas (GET)
+	PermissionAccessWrite = "write" // Operações que recebem payload (POST)
+)
+
+// PermissionRoute rota HTTP coberta pela permissão (path no formato do gin, ex: /cnpj/:numero)
+type PermissionRoute struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// Permission ação de uma API que pode ser concedida a uma API key ("<api>:<ação>", ex: cep:search)
+type Permission struct {
+	Scope       string            `json:"scope"`
+	API         string            `json:"api"`
+	Action      string            `json:"action"`
+	Access      string            `json:"access"` // read | write
+	Description string            `json:"description"`
+	Routes      []PermissionRoute `json:"routes"`
+	Available   bool              `json:"available"` // false = reservada (endpoint ainda não implementado)
+}
+
+func permissionGET(paths ...string) []PermissionRoute {
+	out := make([]PermissionRoute, len(paths))
+	for i, p := range paths {
+		out[i] = PermissionRoute{Method: "GET", Path: p}
+	}
+	return out
+}
+
+func permissionPOST(paths ...string) []PermissionRoute {
+	out := make([]PermissionRoute, len(paths))
+	for i, p := range paths {
+		out[i] = PermissionRoute{Method: "POST", Path: p}
+	}
+	return out
+}
+
+// geoDivisoesRoutes rotas da divisão regional (lista, detalhe e municípios de cada nível)
+func geoDivisoesRoutes() []PermissionRoute {
+	var out []PermissionRoute
+	for _, nivel := range []string{"regioes", "mesorregioes", "microrregioes", "regioes-intermediarias", "regioes-imediatas"} {
+		out = append(out, permissionGET("/geo/"+nivel, "/geo/"+nivel+"/:id", "/geo/"+nivel+"/:id/municipios")...)
+	}
+	return out
+}
+
+// Permissions catálogo de permissões (registro rota → permissão usado pelo RequireScope)
+var Permissions = []Permission{
+	// GEO
+	{Scope: "geo:ufs", Description: "Estados (lista e detalhe)",
+		Routes: permissionGET("/geo/ufs", "/geo/ufs/:sigla", "/public/geo/ufs", "/public/geo/ufs/:sigla")},
+	{Scope: "geo:municipios", Description: "Municípios (lista, detalhe, códigos SIAFI/TSE, histórico e códigos legados)",
+		Routes: permissionGET("/geo/municipios", "/geo/municipios/:uf", "/geo/municipios/id/:id", "/geo/municipios/siafi/:codigo",
+			"/geo/municipios/tse/:codigo", "/geo/municipios/id/:id/historico", "/geo/municipios/legado")},
+	{Scope: "geo:resolver", Description: "Resolução de nomes de municípios por similaridade",
+		Routes: permissionGET("/geo/municipios/resolver")},
+	{Scope: "geo:geojson", Description: "Malhas (GeoJSON) de municípios e UFs",
+		Routes: permissionGET("/geo/municipios/id/:id/geojson", "/geo/ufs/:sigla/geojson")},
+	{Scope: "geo:reverse", Description: "Geocodificação reversa (ponto → município)",
+		Routes: permissionGET("/geo/reverse")},
+	{Scope: "geo:divisoes", Description: "Divisão regional do IBGE (regiões, meso/microrregiões, regiões intermediárias/imediatas)",
+		Routes: geoDivisoesRoutes()},
+
+	// CEP
+	{Scope: "cep:lookup", Description: "Consulta de CEP",
+		Routes: permissionGET("/cep/:codigo", "/public/cep/:codigo")},
+	{Scope: "cep:search", Description: "Busca reversa de CEP (UF, cidade e logradouro)",
+		Routes: permissionGET("/cep/buscar", "/public/cep/buscar")},
+
+	// ENDEREÇO
+	{Scope: "endereco:normalize", Description: "Normalização/validação de endereços",
+		Routes: permissionPOST("/endereco/normalizar")},
+
+	// CNPJ
+	{Scope: "cnpj:lookup", Description: "Consulta unitária de CNPJ",
+		Routes: permissionGET("/cnpj/:numero", "/public/cnpj/:numero")},
+	{Scope: "cnpj:batch", Access: PermissionAccessWrite, Description: "Enriquecimento de CNPJs em lote (em breve)"},
+
+	// PENAL
+	{Scope: "penal:artigos", Description: "Artigos penais (lista, detalhe, árvore, versões, diff, referências e estrutura)",
+		Routes: permissionGET("/penal/artigos", "/penal/artigos/:codigo", "/penal/artigos/:codigo/arvore", "/penal/artigos/:codigo/versoes",
+			"/penal/artigos/:codigo/diff", "/penal/artigos/:codigo/referencias", "/penal/legislacoes/:leg/estrutura",
+			"/public/penal/artigos", "/public/penal/artigos/*codigo")},
+	{Scope: "penal:search", Description: "Busca e autocomplete de artigos penais",
+		Routes: permissionGET("/penal/search", "/penal/autocomplete", "/public/penal/search")},
+	{Scope: "penal:dosimetria", Description: "Cálculo de dosimetria da pena",
+		Routes: permissionPOST("/penal/dosimetria")},
+
+	// NF-e
+	{Scope: "nfe:chave", Description: "Validação da chave de acesso NF-e/CT-e",
+		Routes: permissionGET("/nfe/chave/:chave")},
+
+	// BOLETO
+	{Scope: "boleto:decode", Description: "Decodificação de linha digitável/código de barras",
+		Routes: permissionPOST("/boleto/decodificar")},
+
+	// PIX
+	{Scope: "pix:decode", Description: "Decodificação de BR Code",
+		Routes: permissionPOST("/pix/brcode/decode")},
+	{Scope: "pix:encode", Description: "Geração de BR Code",
+		Routes: permissionPOST("/pix/brcode/encode")},
+	{Scope: "pix:chave", Description: "Classificação do tipo de chave PIX",
+		Routes: permissionGET("/pix/chave/:chave/tipo")},
+
+	// PLACA
+	{Scope: "placa:lookup", Description: "Validação/conversão de placas",
+		Routes: permissionGET("/placa/:placa")},
+
+	// NCM
+	{Scope: "ncm:lookup", Description: "Consulta de NCM por código",
+		Routes: permissionGET("/ncm/:codigo")},
+	{Scope: "ncm:search", Description: "Busca de NCM/CEST por descrição",
+		Routes: permissionGET("/ncm/buscar")},
+}
+
+// permissionsByRoute índice "MÉTODO path" → permissão
+var permissionsByRoute = map[string]*Permission{}
+
+func init() {
+	for i := range Permissions {
+		p := &Permissions[i]
+		p.API, p.Action, _ = strings.Cut(p.Scope, ":")
+		if p.Access == "" {
+			p.Access = PermissionAccessRead
+			for _, r := range p.Routes {
+				if r.Method != "GET" {
+					p.Access = PermissionAccessWrite
+				}
+			}
+		}
+		p.Available = len(p.Routes) > 0
+		for _, r := range p.Routes {
+			permissionsByRoute[r.Method+" "+r.Path] = p
+		}
+	}
+}
+
+// FindPermission permissão pelo scope exato (ex: cep:search)
+func FindPermission(scope string) *Permission {
+	for i := range Permissions {
+		if Permissions[i].Scope == scope {
+			return &Permissions[i]
+		}
+	}
+	return nil
+}
+
+// PermissionForRoute permissão registrada para a rota (path no formato do gin, ex: c.FullPath())
+func PermissionForRoute(method, fullPath string) *Permission {
+	return permissionsByRoute[method+" "+fullPath]
+}
+
+// ScopeGrants indica se o scope concedido à key cobre a permissão:
+//   - "all" → tudo
+//   - "cep" ou "cep:*" → todas as ações da API
+//   - "cep:read" / "cep:write" → ações da API com esse nível de acesso
+//   - "cep:search" → apenas a ação
+//   - padrões glob ("*:lookup", "penal:a*") → ações que casam com o padrão
+func ScopeGrants(grant string, p *Permission) bool {
+	switch grant {
+	case "all", "*", p.API, p.Scope, p.API + ":" + p.Access:
+		return true
+	}
+	if strings.ContainsAny(grant, "*?[") {
+		ok, _ := path.Match(grant, p.Scope)
+		return ok
+	}
+	return false
+}
+
+// ScopesGrant indica se algum dos scopes da key cobre a permissão
+func ScopesGrant(scopes []string, p *Permission) bool {
+	for _, s := range scopes {
+		if ScopeGrants(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionsGranted permissões disponíveis cobertas pelo scope (vazio = scope não concede nada)
+func PermissionsGranted(grant string) []Permission {
+	var out []Permission
+	for i := range Permissions {
+		if Permissions[i].Available && ScopeGrants(grant, &Permissions[i]) {
+			out = append(out, Permissions[i])
+		}
+	}
+	return out
+}
@@ -0,0 +1,357 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tipos de chave PIX (nomenclatura do DICT)
+const (
+	PixChaveCPF      = "CPF"
+	PixChaveCNPJ     = "CNPJ"
+	PixChaveEmail    = "EMAIL"
+	PixChaveTelefone = "PHONE"
+	PixChaveEVP      = "EVP" // Chave aleatória (UUID)
+)
+
+// pixGUI identificador do arranjo PIX no campo 26 do BR Code
+const pixGUI = "br.gov.bcb.pix"
+
+var (
+	pixEmailRegex    = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+	pixTelefoneRegex = regexp.MustCompile(`^\+[1-9][0-9]{9,13}$`)
+	pixTxIDRegex     = regexp.MustCompile(`^[a-zA-Z0-9]+$`)
+	pixEVPRegex      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// PixChave representa o resultado da classificação de uma chave PIX
+type PixChave struct {
+	Chave         string `json:"chave"`         // Chave normalizada (formato aceito pelo DICT)
+	ChaveOriginal string `json:"chaveOriginal"` // Como foi recebida
+	Tipo          string `json:"tipo"`          // CPF, CNPJ, EMAIL, PHONE, EVP ou vazio
+	Valida        bool   `json:"valida"`
+	Motivo        string `json:"motivo,omitempty"` // Motivo quando inválida
+}
+
+// ClassifyPixKey identifica o tipo de uma chave PIX e valida o formato
+func ClassifyPixKey(chave string) PixChave {
+	raw := strings.TrimSpace(chave)
+	result := PixChave{Chave: raw, ChaveOriginal: chave}
+
+	switch {
+	case raw == "":
+		result.Motivo = "chave vazia"
+
+	case strings.Contains(raw, "@"):
+		result.Tipo = PixChaveEmail
+		result.Chave = strings.ToLower(raw)
+		if len(result.Chave) > 77 {
+			result.Motivo = "email deve ter no máximo 77 caracteres"
+		} else if !pixEmailRegex.MatchString(result.Chave) {
+			result.Motivo = "email em formato inválido"
+		} else {
+			result.Valida = true
+		}
+
+	case pixEVPRegex.MatchString(raw):
+		result.Tipo = PixChaveEVP
+		result.Chave = strings.ToLower(raw)
+		result.Valida = true
+
+	case strings.HasPrefix(raw, "+"):
+		result.Tipo = PixChaveTelefone
+		result.Chave = "+" + onlyDigits(raw)
+		if !pixTelefoneRegex.MatchString(result.Chave) {
+			result.Motivo = "telefone deve estar no formato +55DDNNNNNNNNN"
+		} else if strings.HasPrefix(result.Chave, "+55") && len(result.Chave) != 14 && len(result.Chave) != 13 {
+			result.Motivo = "telefone brasileiro deve ter DDD + 8 ou 9 dígitos"
+		} else {
+			result.Valida = true
+		}
+
+	default:
+		digits := onlyDigits(raw)
+		result.Chave = digits
+		switch len(digits) {
+		case 11:
+			result.Tipo = PixChaveCPF
+			if ValidateCPF(digits) {
+				result.Valida = true
+			} else {
+				result.Motivo = "CPF com dígitos verificadores inválidos (telefones devem usar o formato +55DDNNNNNNNNN)"
+			}
+		case 14:
+			result.Tipo = PixChaveCNPJ
+			if ValidateCNPJ(digits) {
+				result.Valida = true
+			} else {
+				result.Motivo = "CNPJ com dígitos verificadores inválidos"
+			}
+		default:
+			result.Chave = raw
+			result.Motivo = "formato não reconhecido (esperado CPF, CNPJ, email, telefone +55 ou chave aleatória)"
+		}
+	}
+
+	return result
+}
+
+// ========================================
+// BR Code (EMV QR Code Merchant Presented Mode)
+// ========================================
+
+// BRCode representa um payload PIX "copia e cola" decodificado
+type BRCode struct {
+	Payload              string            `json:"payload"`
+	Tipo                 string            `json:"tipo"` // "estatico" ou "dinamico"
+	PayloadFormat        string            `json:"payloadFormatIndicator"`
+	PontoIniciacao       string            `json:"pontoIniciacao,omitempty"` // "11" = reutilizável, "12" = uso único
+	Chave                *PixChave         `json:"chave,omitempty"`          // Estático
+	URL                  string            `json:"url,omitempty"`            // Dinâmico (location do PSP)
+	Descricao            string            `json:"descricao,omitempty"`
+	MerchantCategoryCode string            `json:"merchantCategoryCode"`
+	Moeda                string            `json:"moeda"` // "986" = BRL
+	Valor                *float64          `json:"valor,omitempty"`
+	Pais                 string            `json:"pais"`
+	NomeRecebedor        string            `json:"nomeRecebedor"`
+	CidadeRecebedor      string            `json:"cidadeRecebedor"`
+	CEP                  string            `json:"cep,omitempty"`
+	TxID                 string            `json:"txid,omitempty"`
+	CRC                  string            `json:"crc"`
+	Campos               map[string]string `json:"campos"` // Todos os campos de primeiro nível (ID → valor)
+}
+
+// BRCodeInput dados para gerar um BR Code estático
+type BRCodeInput struct {
+	Chave     string
+	Valor     float64 // 0 = valor livre (pagador informa)
+	Descricao string
+	Nome      string
+	Cidade    string
+	CEP       string
+	TxID      string // padrão "***"
+}
+
+type emvField struct {
+	ID    string
+	Value string
+}
+
+// parseEMV faz o parse de uma sequência TLV (ID 2 dígitos, tamanho 2 dígitos, valor)
+func parseEMV(payload string) ([]emvField, error) {
+	fields := []emvField{}
+	for i := 0; i < len(payload); {
+		if i+4 > len(payload) {
+			return nil, &ValidationError{Field: "payload", Message: fmt.Sprintf("campo truncado na posição %d", i)}
+		}
+		id := payload[i : i+2]
+		size, err := strconv.Atoi(payload[i+2 : i+4])
+		if err != nil {
+			return nil, &ValidationError{Field: "payload", Message: fmt.Sprintf("tamanho inválido no campo %s", id)}
+		}
+		if i+4+size > len(payload) {
+			return nil, &ValidationError{Field: "payload", Message: fmt.Sprintf("campo %s excede o tamanho do payload", id)}
+		}
+		fields = append(fields, emvField{ID: id, Value: payload[i+4 : i+4+size]})
+		i += 4 + size
+	}
+	return fields, nil
+}
+
+// emv monta um campo TLV
+func emv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// CRC16CCITT calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) usado no BR Code
+func CRC16CCITT(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+// DecodeBRCode valida o CRC e decodifica um payload PIX "copia e cola"
+func DecodeBRCode(payload string) (*BRCode, error) {
+	payload = strings.TrimSpace(payload)
+
+	if len(payload) < 8 || payload[len(payload)-8:len(payload)-4] != "6304" {
+		return nil, &ValidationError{Field: "payload", Message: "payload deve terminar com o campo CRC (6304XXXX)"}
+	}
+	crc := strings.ToUpper(payload[len(payload)-4:])
+	if expected := CRC16CCITT(payload[:len(payload)-4]); crc != expected {
+		return nil, &ValidationError{Field: "payload", Message: fmt.Sprintf("CRC inválido (esperado %s, recebido %s)", expected, crc)}
+	}
+
+	fields, err := parseEMV(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	code := &BRCode{
+		Payload: payload,
+		Tipo:    "estatico",
+		CRC:     crc,
+		Campos:  map[string]string{},
+	}
+
+	pixFound := false
+	for _, f := range fields {
+		code.Campos[f.ID] = f.Value
+		switch f.ID {
+		case "00":
+			code.PayloadFormat = f.Value
+		case "01":
+			code.PontoIniciacao = f.Value
+		case "52":
+			code.MerchantCategoryCode = f.Value
+		case "53":
+			code.Moeda = f.Value
+		case "54":
+			valor, err := strconv.ParseFloat(f.Value, 64)
+			if err != nil {
+				return nil, &ValidationError{Field: "payload", Message: fmt.Sprintf("valor inválido: %s", f.Value)}
+			}
+			code.Valor = &valor
+		case "58":
+			code.Pais = f.Value
+		case "59":
+			code.NomeRecebedor = f.Value
+		case "60":
+			code.CidadeRecebedor = f.Value
+		case "61":
+			code.CEP = f.Value
+		case "62":
+			sub, err := parseEMV(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			for _, s := range sub {
+				if s.ID == "05" {
+					code.TxID = s.Value
+				}
+			}
+		default:
+			// Merchant Account Information (26-51): procurar o arranjo PIX
+			if id, _ := strconv.Atoi(f.ID); id >= 26 && id <= 51 {
+				sub, err := parseEMV(f.Value)
+				if err != nil {
+					return nil, err
+				}
+				isPix := false
+				for _, s := range sub {
+					if s.ID == "00" && strings.EqualFold(s.Value, pixGUI) {
+						isPix = true
+					}
+				}
+				if !isPix {
+					continue
+				}
+				pixFound = true
+				for _, s := range sub {
+					switch s.ID {
+					case "01":
+						chave := ClassifyPixKey(s.Value)
+						code.Chave = &chave
+					case "02":
+						code.Descricao = s.Value
+					case "25":
+						code.URL = s.Value
+					}
+				}
+			}
+		}
+	}
+
+	if code.PayloadFormat != "01" {
+		return nil, &ValidationError{Field: "payload", Message: "Payload Format Indicator (campo 00) deve ser 01"}
+	}
+	if !pixFound {
+		return nil, &ValidationError{Field: "payload", Message: "payload não contém informações de conta PIX (br.gov.bcb.pix)"}
+	}
+	if code.URL != "" || code.PontoIniciacao == "12" {
+		code.Tipo = "dinamico"
+	}
+
+	return code, nil
+}
+
+// EncodeBRCode gera um BR Code estático a partir de chave, valor e descrição
+func EncodeBRCode(in BRCodeInput) (string, error) {
+	chave := ClassifyPixKey(in.Chave)
+	if !chave.Valida {
+		return "", &ValidationError{Field: "chave", Message: chave.Motivo}
+	}
+
+	nome := emvText(in.Nome, 25)
+	cidade := emvText(in.Cidade, 15)
+	if nome == "" {
+		return "", &ValidationError{Field: "nome", Message: "nome do recebedor é obrigatório"}
+	}
+	if cidade == "" {
+		return "", &ValidationError{Field: "cidade", Message: "cidade do recebedor é obrigatória"}
+	}
+	if in.Valor < 0 {
+		return "", &ValidationError{Field: "valor", Message: "valor não pode ser negativo"}
+	}
+
+	txid := in.TxID
+	if txid == "" {
+		txid = "***"
+	} else if len(txid) > 25 || !pixTxIDRegex.MatchString(txid) {
+		return "", &ValidationError{Field: "txid", Message: "txid deve ser alfanumérico com até 25 caracteres"}
+	}
+
+	account := emv("00", pixGUI) + emv("01", chave.Chave)
+	if descricao := emvText(in.Descricao, 72); descricao != "" {
+		account += emv("02", descricao)
+	}
+	if len(account) > 99 {
+		return "", &ValidationError{Field: "descricao", Message: "chave + descrição excedem o tamanho máximo do campo 26 (99 caracteres)"}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(emv("00", "01"))
+	sb.WriteString(emv("26", account))
+	sb.WriteString(emv("52", "0000"))
+	sb.WriteString(emv("53", "986"))
+	if in.Valor > 0 {
+		sb.WriteString(emv("54", strconv.FormatFloat(in.Valor, 'f', 2, 64)))
+	}
+	sb.WriteString(emv("58", "BR"))
+	sb.WriteString(emv("59", nome))
+	sb.WriteString(emv("60", cidade))
+	if cep := onlyDigits(in.CEP); len(cep) == 8 {
+		sb.WriteString(emv("61", cep))
+	}
+	sb.WriteString(emv("62", emv("05", txid)))
+	sb.WriteString("6304")
+
+	payload := sb.String()
+	return payload + CRC16CCITT(payload), nil
+}
+
+// emvText remove acentos e trunca o texto em até max bytes (o tamanho do campo EMV é em bytes),
+// sem cortar um caractere multibyte no meio (o BR Code recomenda apenas ASCII)
+func emvText(s string, max int) string {
+	s = strings.TrimSpace(RemoveAccents(s))
+	if len(s) > max {
+		cut := max
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		s = strings.TrimSpace(s[:cut])
+	}
+	return s
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Formatos de placa de veículo
+const (
+	PlacaFormatoAntigo   = "ANTIGO"   // AAA9999 (até 2018)
+	PlacaFormatoMercosul = "MERCOSUL" // AAA9A99
+)
+
+var (
+	placaAntigaRegex   = regexp.MustCompile(`^[A-Z]{3}[0-9]{4}$`)
+	placaMercosulRegex = regexp.MustCompile(`^[A-Z]{3}[0-9][A-Z][0-9]{2}$`)
+)
+
+// Placa representa uma placa de veículo validada
+type Placa struct {
+	Placa          string      `json:"placa"`          // Normalizada (ex: ABC1D23)
+	PlacaFormatada string      `json:"placaFormatada"` // ABC-1234 (antiga) ou ABC1D23 (Mercosul)
+	Formato        string      `json:"formato"`        // ANTIGO ou MERCOSUL
+	PlacaAntiga    string      `json:"placaAntiga,omitempty"`
+	PlacaMercosul  string      `json:"placaMercosul"`
+	Faixa          *PlacaFaixa `json:"faixa,omitempty"`   // Faixa de emissão (UF) da série antiga
+	Veiculo        *Veiculo    `json:"veiculo,omitempty"` // Dados de registro (apenas com provider externo)
+}
+
+// PlacaFaixa faixa de séries de placas (formato antigo) emitidas por UF
+type PlacaFaixa struct {
+	Inicio string `bson:"inicio" json:"inicio"` // Prefixo inicial (ex: "AAA")
+	Fim    string `bson:"fim" json:"fim"`       // Prefixo final (ex: "BEZ")
+	UF     string `bson:"uf" json:"uf"`
+}
+
+// Veiculo dados de registro de um veículo (fornecidos por provider externo)
+type Veiculo struct {
+	Marca         string    `json:"marca,omitempty"`
+	Modelo        string    `json:"modelo,omitempty"`
+	AnoFabricacao int       `json:"anoFabricacao,omitempty"`
+	AnoModelo     int       `json:"anoModelo,omitempty"`
+	Cor           string    `json:"cor,omitempty"`
+	Municipio     string    `json:"municipio,omitempty"`
+	UF            string    `json:"uf,omitempty"`
+	Situacao      string    `json:"situacao,omitempty"`
+	Fonte         string    `json:"fonte"`
+	ConsultadoEm  time.Time `json:"consultadoEm"`
+}
+
+// VeiculoProvider fonte externa (opcional) de dados de registro de veículos
+type VeiculoProvider interface {
+	Nome() string
+	ConsultarPlaca(ctx context.Context, placa string) (*Veiculo, error)
+}
+
+// NormalizePlaca remove formatação e converte para maiúsculas
+func NormalizePlaca(placa string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(placa) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// ParsePlaca valida uma placa (antiga ou Mercosul) e calcula a conversão entre os formatos
+//
+// Conversão oficial: o 2º dígito da placa antiga vira letra na Mercosul (0→A, 1→B, ..., 9→J).
+// Placas Mercosul com a 5ª posição entre K e Z não têm equivalente no formato antigo.
+func ParsePlaca(placa string) (*Placa, error) {
+	normalized := NormalizePlaca(placa)
+
+	result := &Placa{Placa: normalized}
+
+	switch {
+	case placaAntigaRegex.MatchString(normalized):
+		result.Formato = PlacaFormatoAntigo
+		result.PlacaAntiga = normalized
+		result.PlacaMercosul = normalized[:4] + string('A'+normalized[4]-'0') + normalized[5:]
+		result.PlacaFormatada = normalized[:3] + "-" + normalized[3:]
+
+	case placaMercosulRegex.MatchString(normalized):
+		result.Formato = PlacaFormatoMercosul
+		result.PlacaMercosul = normalized
+		result.PlacaFormatada = normalized
+		if normalized[4] <= 'J' {
+			result.PlacaAntiga = normalized[:4] + string('0'+normalized[4]-'A') + normalized[5:]
+		}
+
+	default:
+		return nil, &ValidationError{Field: "placa", Message: "placa deve estar no formato AAA9999 (antigo) ou AAA9A99 (Mercosul)"}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Principal identidade autenticada por API key (key, tenant, scopes e limites),
+// resolvida uma vez por requisição pelo AuthAPIKey e guardada no contexto do gin
+type Principal struct {
+	Key        APIKey          `json:"key"`
+	TenantID   string          `json:"tenantId"`
+	TenantName string          `json:"tenantName,omitempty"`
+	RateLimit  RateLimitConfig `json:"rateLimit"`       // Do tenant ou padrão do sistema
+	CustomRate bool            `json:"customRateLimit"` // true = limite personalizado do tenant
+	ResolvedAt time.Time       `json:"resolvedAt"`
+
+	// KeyVerifier HMAC do Key.KeyHash com o segredo do cache: vai para o Redis no lugar do hash e
+	// permite conferir a key sem consultar o MongoDB
+	KeyVerifier string `json:"keyVerifier"`
+}
+
+// Scopes scopes concedidos à key
+func (p *Principal) Scopes() []string {
+	return p.Key.Scopes
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "strings"
+
+// accentReplacer mapeia caracteres acentuados do português para a forma sem acento
+var accentReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+	"º", "o", "ª", "a",
+)
+
+// RemoveAccents remove acentos e cedilha (ex: "São José" → "Sao Jose")
+func RemoveAccents(s string) string {
+	return accentReplacer.Replace(s)
+}
+
+// NormalizeSearchText normaliza texto para busca: minúsculas, sem acentos e sem espaços duplicados
+func NormalizeSearchText(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(RemoveAccents(s))), " ")
+}
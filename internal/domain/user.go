@@ -10,18 +10,72 @@ const (
 	RoleTenantUser UserRole = "TENANT_USER" // Acesso apenas aos próprios dados
 )
 
+// TenantRole papel do usuário dentro do tenant (apenas TENANT_USER)
+type TenantRole string
+
+const (
+	TenantRoleOwner     TenantRole = "owner"     // Dono: tudo, inclusive transferir a propriedade
+	TenantRoleAdmin     TenantRole = "admin"     // Gerencia usuários (exceto owner/admins) e API keys
+	TenantRoleDeveloper TenantRole = "developer" // Gerencia API keys e consulta uso
+	TenantRoleBilling   TenantRole = "billing"   // Consulta uso e configurações
+	TenantRoleViewer    TenantRole = "viewer"    // Somente leitura
+)
+
+// Grupos de papéis usados nas rotas /me (RequireTenantUser)
+var (
+	TenantRolesManageKeys  = []TenantRole{TenantRoleOwner, TenantRoleAdmin, TenantRoleDeveloper}
+	TenantRolesReadKeys    = []TenantRole{TenantRoleOwner, TenantRoleAdmin, TenantRoleDeveloper, TenantRoleViewer}
+	TenantRolesManageUsers = []TenantRole{TenantRoleOwner, TenantRoleAdmin}
+)
+
+// Valid indica se o papel existe
+func (r TenantRole) Valid() bool {
+	switch r {
+	case TenantRoleOwner, TenantRoleAdmin, TenantRoleDeveloper, TenantRoleBilling, TenantRoleViewer:
+		return true
+	}
+	return false
+}
+
+// In indica se o papel está na lista
+func (r TenantRole) In(roles []TenantRole) bool {
+	for _, role := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// CanManage indica se quem tem o papel pode convidar/alterar/remover usuários com o papel alvo
+// (owner gerencia todos exceto outro owner; admin apenas developer, billing e viewer)
+func (r TenantRole) CanManage(target TenantRole) bool {
+	switch r {
+	case TenantRoleOwner:
+		return target != TenantRoleOwner
+	case TenantRoleAdmin:
+		return target == TenantRoleDeveloper || target == TenantRoleBilling || target == TenantRoleViewer
+	}
+	return false
+}
+
 // User representa um usuário do sistema (admin ou tenant user)
 type User struct {
-	ID        string     `bson:"_id,omitempty" json:"id,omitempty"`
-	Email     string     `bson:"email" json:"email"`                           // Email único
-	Password  string     `bson:"password" json:"-"`                            // Hash bcrypt (nunca retornar em JSON)
-	Name      string     `bson:"name" json:"name"`                             // Nome completo
-	Role      UserRole   `bson:"role" json:"role"`                             // SUPER_ADMIN ou TENANT_USER
-	TenantID  string     `bson:"tenantId,omitempty" json:"tenantId,omitempty"` // ID do tenant (null para SUPER_ADMIN)
-	Active    bool       `bson:"active" json:"active"`                         // Usuário ativo?
-	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
-	UpdatedAt time.Time  `bson:"updatedAt" json:"updatedAt"`
-	LastLogin *time.Time `bson:"lastLogin,omitempty" json:"lastLogin,omitempty"` // Último login
+	ID         string     `bson:"_id,omitempty" json:"id,omitempty"`
+	Email      string     `bson:"email" json:"email"`                               // Email único
+	Password   string     `bson:"password" json:"-"`                                // Hash bcrypt (nunca retornar em JSON)
+	Name       string     `bson:"name" json:"name"`                                 // Nome completo
+	Role       UserRole   `bson:"role" json:"role"`                                 // SUPER_ADMIN ou TENANT_USER
+	TenantID   string     `bson:"tenantId,omitempty" json:"tenantId,omitempty"`     // ID do tenant (null para SUPER_ADMIN)
+	TenantRole TenantRole `bson:"tenantRole,omitempty" json:"tenantRole,omitempty"` // Papel no tenant (vazio em usuários antigos = owner)
+	Active     bool       `bson:"active" json:"active"`                             // Usuário ativo?
+	CreatedAt  time.Time  `bson:"createdAt" json:"createdAt"`
+	UpdatedAt  time.Time  `bson:"updatedAt" json:"updatedAt"`
+	LastLogin  *time.Time `bson:"lastLogin,omitempty" json:"lastLogin,omitempty"` // Último login
+
+	EmailVerified     bool       `bson:"emailVerified" json:"emailVerified"`
+	EmailVerifiedAt   *time.Time `bson:"emailVerifiedAt,omitempty" json:"emailVerifiedAt,omitempty"`
+	PasswordChangedAt *time.Time `bson:"passwordChangedAt,omitempty" json:"-"` // Refresh tokens emitidos antes deixam de valer
 }
 
 // IsSuperAdmin verifica se o usuário é super admin
@@ -34,6 +88,17 @@ func (u *User) IsTenantUser() bool {
 	return u.Role == RoleTenantUser
 }
 
+// EffectiveTenantRole papel no tenant (usuários criados antes dos papéis eram o único usuário = owner)
+func (u *User) EffectiveTenantRole() TenantRole {
+	if !u.IsTenantUser() {
+		return ""
+	}
+	if u.TenantRole == "" {
+		return TenantRoleOwner
+	}
+	return u.TenantRole
+}
+
 // CanAccessTenant verifica se o usuário pode acessar dados do tenant
 func (u *User) CanAccessTenant(tenantID string) bool {
 	// Super admin pode acessar qualquer tenant
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// Finalidades dos tokens enviados por email
+const (
+	UserTokenPasswordReset     = "password_reset"
+	UserTokenEmailVerification = "email_verification"
+)
+
+// UserToken token de uso único enviado por email (no banco fica apenas o hash)
+type UserToken struct {
+	ID        string     `bson:"_id,omitempty" json:"id"`
+	UserID    string     `bson:"userId" json:"userId"`
+	Email     string     `bson:"email" json:"email"` // Email para o qual o token foi enviado
+	Purpose   string     `bson:"purpose" json:"purpose"`
+	TokenHash string     `bson:"tokenHash" json:"-"`
+	CreatedAt time.Time  `bson:"createdAt" json:"createdAt"`
+	ExpiresAt time.Time  `bson:"expiresAt" json:"expiresAt"`
+	UsedAt    *time.Time `bson:"usedAt,omitempty" json:"usedAt,omitempty"`
+}
+
+// ForgotPasswordRequest payload de POST /auth/forgot-password
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// ResetPasswordRequest payload de POST /auth/reset-password
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+// VerifyEmailRequest payload de POST /auth/verify-email
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ChangePasswordRequest payload de PUT /me/password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required,min=8"`
+}
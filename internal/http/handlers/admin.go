@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/domain"
 	"github.com/theretech/retech-core/internal/storage"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -103,6 +104,31 @@ func (h *AdminHandler) ListAllAPIKeys(c *gin.Context) {
 	})
 }
 
+// ListPermissions lista todas as permissões (scopes) que podem ser concedidas às API keys
+// GET /admin/permissions?api=cnpj
+func (h *AdminHandler) ListPermissions(c *gin.Context) {
+	api := c.Query("api")
+
+	permissions := []domain.Permission{}
+	for _, p := range domain.Permissions {
+		if api == "" || p.API == api {
+			permissions = append(permissions, p)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"permissions": permissions,
+		"total":       len(permissions),
+		"grants": gin.H{
+			"all":        "Todas as APIs e ações",
+			"cep":        "Todas as ações da API (equivale a cep:*)",
+			"cep:read":   "Ações de consulta (GET) da API; cep:write = ações que recebem payload (POST)",
+			"cep:search": "Apenas a ação",
+			"*:lookup":   "Padrão glob sobre os scopes das ações",
+		},
+	})
+}
+
 // GetUsage retorna dados de uso da API
 // GET /admin/usage
 func (h *AdminHandler) GetUsage(c *gin.Context) {
@@ -162,12 +162,13 @@ func (h *APIKeysHandler) rotateExistingKey(c *gin.Context, in rotateKeyDTO) {
 	}
 
 	newKey := &domain.APIKey{
-		KeyID:     keyId,
-		KeyHash:   hash,
-		OwnerID:   existingKey.OwnerID,
-		Scopes:    existingKey.Scopes,
-		ExpiresAt: now.Add(time.Duration(days) * 24 * time.Hour),
-		Revoked:   false,
+		KeyID:        keyId,
+		KeyHash:      hash,
+		OwnerID:      existingKey.OwnerID,
+		Scopes:       existingKey.Scopes,
+		ExpiresAt:    now.Add(time.Duration(days) * 24 * time.Hour),
+		Revoked:      false,
+		Restrictions: existingKey.Restrictions,
 	}
 
 	if err := h.Repo.Insert(c, newKey); err != nil {
@@ -258,12 +259,13 @@ func (h *APIKeysHandler) Rotate(c *gin.Context) {
 	}
 
 	newKey := &domain.APIKey{
-		KeyID:     keyId,
-		KeyHash:   hash,
-		OwnerID:   existingKey.OwnerID,
-		Scopes:    existingKey.Scopes,
-		ExpiresAt: now.Add(time.Duration(days) * 24 * time.Hour),
-		Revoked:   false,
+		KeyID:        keyId,
+		KeyHash:      hash,
+		OwnerID:      existingKey.OwnerID,
+		Scopes:       existingKey.Scopes,
+		ExpiresAt:    now.Add(time.Duration(days) * 24 * time.Hour),
+		Revoked:      false,
+		Restrictions: existingKey.Restrictions,
 	}
 
 	if err := h.Repo.Insert(c, newKey); err != nil {
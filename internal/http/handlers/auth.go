@@ -8,6 +8,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/theretech/retech-core/internal/auth"
 	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/notify"
 	"github.com/theretech/retech-core/internal/storage"
 	"github.com/theretech/retech-core/internal/utils"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -17,19 +18,25 @@ type AuthHandler struct {
 	users        *storage.UsersRepo
 	tenants      *storage.TenantsRepo
 	apikeys      *storage.APIKeysRepo
+	invitations  *storage.InvitationsRepo
+	userTokens   *storage.UserTokensRepo
 	activityRepo *storage.ActivityLogsRepo
 	settings     *storage.SettingsRepo
 	jwt          *auth.JWTService
+	mailer       notify.Mailer
 }
 
-func NewAuthHandler(users *storage.UsersRepo, tenants *storage.TenantsRepo, apikeys *storage.APIKeysRepo, activityRepo *storage.ActivityLogsRepo, settings *storage.SettingsRepo, jwt *auth.JWTService) *AuthHandler {
+func NewAuthHandler(users *storage.UsersRepo, tenants *storage.TenantsRepo, apikeys *storage.APIKeysRepo, invitations *storage.InvitationsRepo, userTokens *storage.UserTokensRepo, activityRepo *storage.ActivityLogsRepo, settings *storage.SettingsRepo, jwt *auth.JWTService, mailer notify.Mailer) *AuthHandler {
 	return &AuthHandler{
 		users:        users,
 		tenants:      tenants,
 		apikeys:      apikeys,
+		invitations:  invitations,
+		userTokens:   userTokens,
 		activityRepo: activityRepo,
 		settings:     settings,
 		jwt:          jwt,
+		mailer:       mailer,
 	}
 }
 
@@ -228,13 +235,14 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Criar primeiro usuário
+	// Criar primeiro usuário (owner do tenant)
 	user := &domain.User{
-		Email:    req.UserEmail,
-		Name:     req.UserName,
-		Role:     domain.RoleTenantUser,
-		TenantID: tenant.TenantID, // Usar TenantID, não ID
-		Active:   true,
+		Email:      req.UserEmail,
+		Name:       req.UserName,
+		Role:       domain.RoleTenantUser,
+		TenantID:   tenant.TenantID, // Usar TenantID, não ID
+		TenantRole: domain.TenantRoleOwner,
+		Active:     true,
 	}
 
 	if err := h.users.Create(ctx, user, req.UserPassword); err != nil {
@@ -326,6 +334,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		},
 	)
 
+	// Verificação do email (não bloqueia o cadastro)
+	if err := h.sendVerification(c, user); err != nil {
+		fmt.Printf("⚠️ [Register] Erro ao enviar verificação de email: %v\n", err)
+	}
+
 	// Remover senha
 	user.Password = ""
 
@@ -338,6 +351,182 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	})
 }
 
+// AcceptInvitation aceita um convite: cria o usuário no tenant com o papel convidado e já autentica
+// POST /auth/invitations/accept
+func (h *AuthHandler) AcceptInvitation(c *gin.Context) {
+	var req domain.AcceptInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	inv, err := h.invitations.ByTokenHash(ctx, auth.HashOpaqueToken(req.Token))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao buscar convite",
+		})
+		return
+	}
+	if inv == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/invitation-not-found",
+			"title":  "Convite não encontrado",
+			"status": http.StatusNotFound,
+			"detail": "Token de convite inválido",
+		})
+		return
+	}
+	if status := inv.Status(time.Now().UTC()); status != domain.InvitationStatusPending {
+		c.JSON(http.StatusGone, gin.H{
+			"type":   "https://retech-core/errors/invitation-" + status,
+			"title":  "Convite indisponível",
+			"status": http.StatusGone,
+			"detail": "Este convite não pode mais ser usado (" + status + "). Peça um novo convite ao administrador do tenant.",
+		})
+		return
+	}
+
+	tenant, _ := h.tenants.ByTenantID(ctx, inv.TenantID)
+	if tenant == nil || !tenant.Active {
+		c.JSON(http.StatusGone, gin.H{
+			"type":   "https://retech-core/errors/invitation-tenant-inactive",
+			"title":  "Convite indisponível",
+			"status": http.StatusGone,
+			"detail": "O tenant do convite não existe mais ou está inativo",
+		})
+		return
+	}
+
+	if existing, _ := h.users.FindByEmail(ctx, inv.Email); existing != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"type":   "https://retech-core/errors/conflict",
+			"title":  "Email já em uso",
+			"status": http.StatusConflict,
+			"detail": fmt.Sprintf("O email '%s' já está cadastrado.", inv.Email),
+		})
+		return
+	}
+
+	user := &domain.User{
+		Email:      inv.Email,
+		Name:       req.Name,
+		Role:       domain.RoleTenantUser,
+		TenantID:   inv.TenantID,
+		TenantRole: inv.Role,
+		Active:     true,
+	}
+	if err := h.users.Create(ctx, user, req.Password); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao criar usuário",
+		})
+		return
+	}
+
+	// Uso único: se outro aceite (ou revogação/expiração) chegou antes, desfaz o usuário criado
+	accepted, err := h.invitations.Accept(ctx, inv.ID, user.ID)
+	if err != nil || !accepted {
+		_ = h.users.Delete(ctx, user.ID)
+		c.JSON(http.StatusGone, gin.H{
+			"type":   "https://retech-core/errors/invitation-accepted",
+			"title":  "Convite indisponível",
+			"status": http.StatusGone,
+			"detail": "Este convite não pode mais ser usado",
+		})
+		return
+	}
+
+	accessToken, err := h.jwt.GenerateAccessToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao gerar token",
+		})
+		return
+	}
+
+	refreshToken, err := h.jwt.GenerateRefreshToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao gerar refresh token",
+		})
+		return
+	}
+
+	actor := domain.Actor{
+		UserID: user.ID,
+		Email:  user.Email,
+		Name:   user.Name,
+		Role:   string(user.Role),
+	}
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypeInvitationAccepted,
+		domain.ActionAccept,
+		actor,
+		domain.Resource{
+			Type: domain.ResourceTypeInvitation,
+			ID:   inv.ID,
+			Name: inv.Email,
+		},
+		map[string]interface{}{
+			"tenantId":  inv.TenantID,
+			"role":      inv.Role,
+			"invitedBy": inv.InvitedBy.UserID,
+		},
+	)
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypeUserCreated,
+		domain.ActionCreate,
+		actor,
+		domain.Resource{
+			Type: domain.ResourceTypeUser,
+			ID:   user.ID,
+			Name: user.Name,
+		},
+		map[string]interface{}{
+			"email":      user.Email,
+			"role":       user.Role,
+			"tenantId":   user.TenantID,
+			"tenantRole": user.TenantRole,
+			"via":        "invitation",
+		},
+	)
+
+	if err := h.sendVerification(c, user); err != nil {
+		fmt.Printf("⚠️ [AcceptInvitation] Erro ao enviar verificação de email: %v\n", err)
+	}
+
+	c.JSON(http.StatusCreated, domain.LoginResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(h.jwt.GetAccessTTL().Seconds()),
+		User:         user,
+	})
+}
+
 // RefreshToken renova o access token usando refresh token
 // POST /auth/refresh
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
@@ -376,6 +565,17 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	// Troca/redefinição de senha encerra as sessões anteriores
+	if user.PasswordChangedAt != nil && claims.IssuedAt != nil && claims.IssuedAt.Time.Before(user.PasswordChangedAt.Truncate(time.Second)) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"type":   "https://retech-core/errors/unauthorized",
+			"title":  "Unauthorized",
+			"status": http.StatusUnauthorized,
+			"detail": "Sessão encerrada após alteração de senha. Faça login novamente.",
+		})
+		return
+	}
+
 	// Gerar novo access token
 	newAccessToken, err := h.jwt.GenerateAccessToken(user)
 	if err != nil {
@@ -0,0 +1,459 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/auth"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/notify"
+	"github.com/theretech/retech-core/internal/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Intervalo mínimo entre dois emails da mesma finalidade para o mesmo usuário
+const accountEmailCooldown = time.Minute
+
+// ForgotPassword envia o link de redefinição de senha. Responde sempre 202 (não revela se o email existe).
+// POST /auth/forgot-password
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	resp := gin.H{
+		"message": "Se o email estiver cadastrado, você receberá um link para redefinir a senha",
+	}
+
+	user, err := h.users.FindByEmail(ctx, strings.TrimSpace(req.Email))
+	if err != nil {
+		if err != mongo.ErrNoDocuments {
+			fmt.Printf("⚠️ [ForgotPassword] Erro ao buscar usuário: %v\n", err)
+		}
+		c.JSON(http.StatusAccepted, resp)
+		return
+	}
+	if !user.Active {
+		c.JSON(http.StatusAccepted, resp)
+		return
+	}
+
+	if recente, _ := h.userTokens.IssuedSince(ctx, user.ID, domain.UserTokenPasswordReset, time.Now().UTC().Add(-accountEmailCooldown)); recente {
+		c.JSON(http.StatusAccepted, resp)
+		return
+	}
+
+	ttl := time.Duration(envIntTenant("PASSWORD_RESET_TTL_MINUTES", 60)) * time.Minute
+	token, err := h.issueUserToken(ctx, user, domain.UserTokenPasswordReset, ttl)
+	if err != nil {
+		fmt.Printf("⚠️ [ForgotPassword] Erro ao gerar token: %v\n", err)
+		c.JSON(http.StatusAccepted, resp)
+		return
+	}
+
+	h.sendMail(notify.PasswordResetEmail(user.Email, user.Name, accountLink("/redefinir-senha", token), ttl))
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypePasswordResetRequested,
+		domain.ActionRequest,
+		userActor(user),
+		domain.Resource{
+			Type: domain.ResourceTypeUser,
+			ID:   user.ID,
+			Name: user.Name,
+		},
+		map[string]interface{}{
+			"email":     user.Email,
+			"expiresIn": ttl.String(),
+		},
+	)
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// ResetPassword define a nova senha com o token recebido por email (uso único)
+// POST /auth/reset-password
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, t, ok := h.consumeUserToken(c, domain.UserTokenPasswordReset, req.Token)
+	if !ok {
+		return
+	}
+
+	if err := h.users.UpdatePassword(ctx, user.ID, req.Password); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao redefinir senha",
+		})
+		return
+	}
+
+	// O token chegou no email: a caixa de entrada é do usuário
+	if !user.EmailVerified {
+		_, _ = h.users.MarkEmailVerified(ctx, user.ID, t.Email)
+	}
+
+	h.sendMail(notify.PasswordChangedEmail(user.Email, user.Name, time.Now()))
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypePasswordReset,
+		domain.ActionUpdate,
+		userActor(user),
+		domain.Resource{
+			Type: domain.ResourceTypeUser,
+			ID:   user.ID,
+			Name: user.Name,
+		},
+		map[string]interface{}{
+			"email": user.Email,
+			"via":   "forgot-password",
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Senha redefinida com sucesso. Faça login com a nova senha.",
+	})
+}
+
+// VerifyEmail confirma o email com o token recebido (uso único)
+// POST /auth/verify-email
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	user, t, ok := h.consumeUserToken(c, domain.UserTokenEmailVerification, req.Token)
+	if !ok {
+		return
+	}
+
+	if _, err := h.users.MarkEmailVerified(c.Request.Context(), user.ID, t.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao verificar email",
+		})
+		return
+	}
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypeEmailVerified,
+		domain.ActionVerify,
+		userActor(user),
+		domain.Resource{
+			Type: domain.ResourceTypeUser,
+			ID:   user.ID,
+			Name: user.Name,
+		},
+		map[string]interface{}{
+			"email": t.Email,
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Email verificado com sucesso",
+		"emailVerified": true,
+	})
+}
+
+// ResendVerification reenvia o email de verificação do usuário logado
+// POST /auth/verify-email/resend
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user, err := h.users.FindByID(ctx, auth.GetUserID(c))
+	if err != nil || !user.Active {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"type":   "https://retech-core/errors/unauthorized",
+			"title":  "Unauthorized",
+			"status": http.StatusUnauthorized,
+			"detail": "Usuário não encontrado ou inativo",
+		})
+		return
+	}
+
+	if user.EmailVerified {
+		c.JSON(http.StatusOK, gin.H{
+			"message":       "Email já verificado",
+			"emailVerified": true,
+		})
+		return
+	}
+
+	if recente, _ := h.userTokens.IssuedSince(ctx, user.ID, domain.UserTokenEmailVerification, time.Now().UTC().Add(-accountEmailCooldown)); recente {
+		c.Header("Retry-After", fmt.Sprintf("%d", int(accountEmailCooldown.Seconds())))
+		c.JSON(http.StatusTooManyRequests, gin.H{
+			"type":   "https://retech-core/errors/rate-limit-exceeded",
+			"title":  "Too Many Requests",
+			"status": http.StatusTooManyRequests,
+			"detail": "Aguarde um minuto antes de pedir outro email de verificação",
+		})
+		return
+	}
+
+	if err := h.sendVerification(c, user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao gerar verificação de email",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Email de verificação enviado para " + user.Email,
+	})
+}
+
+// ChangePassword troca a senha do usuário logado (exige a senha atual) e devolve novos tokens:
+// refresh tokens emitidos antes da troca deixam de valer
+// PUT /me/password
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	user, err := h.users.FindByID(ctx, auth.GetUserID(c))
+	if err != nil || !user.Active {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"type":   "https://retech-core/errors/unauthorized",
+			"title":  "Unauthorized",
+			"status": http.StatusUnauthorized,
+			"detail": "Usuário não encontrado ou inativo",
+		})
+		return
+	}
+
+	if !h.users.VerifyPassword(user.Password, req.CurrentPassword) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/invalid-password",
+			"title":  "Senha atual incorreta",
+			"status": http.StatusBadRequest,
+			"detail": "A senha atual informada não confere",
+		})
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": "A nova senha deve ser diferente da atual",
+		})
+		return
+	}
+
+	if err := h.users.UpdatePassword(ctx, user.ID, req.NewPassword); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao alterar senha",
+		})
+		return
+	}
+
+	// Links de redefinição pendentes não devem sobrescrever a senha nova
+	_ = h.userTokens.InvalidateAll(ctx, user.ID, domain.UserTokenPasswordReset)
+
+	accessToken, err := h.jwt.GenerateAccessToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao gerar token",
+		})
+		return
+	}
+
+	refreshToken, err := h.jwt.GenerateRefreshToken(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao gerar refresh token",
+		})
+		return
+	}
+
+	h.sendMail(notify.PasswordChangedEmail(user.Email, user.Name, time.Now()))
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypePasswordChanged,
+		domain.ActionUpdate,
+		userActor(user),
+		domain.Resource{
+			Type: domain.ResourceTypeUser,
+			ID:   user.ID,
+			Name: user.Name,
+		},
+		map[string]interface{}{
+			"email": user.Email,
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Senha alterada com sucesso",
+		"accessToken":  accessToken,
+		"refreshToken": refreshToken,
+		"expiresIn":    int64(h.jwt.GetAccessTTL().Seconds()),
+	})
+}
+
+// sendVerification gera o token e envia o email de verificação (cadastro, aceite de convite e reenvio)
+func (h *AuthHandler) sendVerification(c *gin.Context, user *domain.User) error {
+	ttl := time.Duration(envIntTenant("EMAIL_VERIFICATION_TTL_HOURS", 48)) * time.Hour
+	token, err := h.issueUserToken(c.Request.Context(), user, domain.UserTokenEmailVerification, ttl)
+	if err != nil {
+		return err
+	}
+
+	h.sendMail(notify.EmailVerificationEmail(user.Email, user.Name, accountLink("/verificar-email", token), ttl))
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypeEmailVerificationSent,
+		domain.ActionNotify,
+		userActor(user),
+		domain.Resource{
+			Type: domain.ResourceTypeUser,
+			ID:   user.ID,
+			Name: user.Name,
+		},
+		map[string]interface{}{
+			"email":     user.Email,
+			"expiresIn": ttl.String(),
+		},
+	)
+	return nil
+}
+
+func (h *AuthHandler) issueUserToken(ctx context.Context, user *domain.User, purpose string, ttl time.Duration) (string, error) {
+	token, tokenHash, err := auth.NewOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	err = h.userTokens.Issue(ctx, &domain.UserToken{
+		UserID:    user.ID,
+		Email:     user.Email,
+		Purpose:   purpose,
+		TokenHash: tokenHash,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	return token, err
+}
+
+// consumeUserToken valida e consome o token; responde 400 se inválido, expirado, já usado
+// ou se o email do usuário mudou desde o envio
+func (h *AuthHandler) consumeUserToken(c *gin.Context, purpose, token string) (*domain.User, *domain.UserToken, bool) {
+	ctx := c.Request.Context()
+
+	t, err := h.userTokens.Consume(ctx, purpose, auth.HashOpaqueToken(token))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Internal Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao validar token",
+		})
+		return nil, nil, false
+	}
+
+	var user *domain.User
+	if t != nil {
+		user, _ = h.users.FindByID(ctx, t.UserID)
+	}
+	if t == nil || user == nil || user.Email != t.Email {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/invalid-token",
+			"title":  "Token inválido",
+			"status": http.StatusBadRequest,
+			"detail": "Token inválido, expirado ou já utilizado. Solicite um novo link.",
+		})
+		return nil, nil, false
+	}
+	return user, t, true
+}
+
+// sendMail envia em segundo plano: a resposta não espera o SMTP (nem revela, pelo tempo, se o email existe)
+func (h *AuthHandler) sendMail(msg notify.Email) {
+	sendMailAsync(h.mailer, msg)
+}
+
+// sendMailAsync envia fora da requisição (falha de SMTP não afeta a resposta; erro vai para o log)
+func sendMailAsync(mailer notify.Mailer, msg notify.Email) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := mailer.Send(ctx, msg); err != nil {
+			fmt.Printf("⚠️ [Mail] Erro ao enviar '%s' para %s: %v\n", msg.Subject, msg.To, err)
+		}
+	}()
+}
+
+// accountLink link do painel (APP_URL) com o token; sem APP_URL, o email leva apenas o token
+func accountLink(path, token string) string {
+	if appURL := strings.TrimSuffix(os.Getenv("APP_URL"), "/"); appURL != "" {
+		return appURL + path + "?token=" + token
+	}
+	return "Token: " + token
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+)
+
+type BoletoHandler struct {
+	bancos *storage.BancosRepo
+}
+
+func NewBoletoHandler(bancos *storage.BancosRepo) *BoletoHandler {
+	return &BoletoHandler{
+		bancos: bancos,
+	}
+}
+
+// DecodificarRequest payload de decodificação de boleto
+type DecodificarRequest struct {
+	Codigo string `json:"codigo" binding:"required"` // Linha digitável (47/48) ou código de barras (44), com ou sem formatação
+}
+
+// Decodificar valida e decodifica boleto bancário ou de arrecadação (concessionárias)
+// POST /boleto/decodificar
+//
+// 100% offline: valida DVs (módulo 10/11), converte linha digitável ↔ código de barras,
+// extrai valor, vencimento (fator, incluindo o reset de 2025) e banco emissor.
+func (h *BoletoHandler) Decodificar(c *gin.Context) {
+	var req DecodificarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Campo 'codigo' é obrigatório",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// Referência em horário de Brasília para resolver o fator de vencimento
+	now := time.Now()
+	if loc, err := time.LoadLocation("America/Sao_Paulo"); err == nil {
+		now = now.In(loc)
+	}
+	referencia := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	boleto, err := domain.DecodeBoleto(req.Codigo, referencia)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Boleto Inválido",
+			Status:   http.StatusUnprocessableEntity,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// Enriquecer com dados do banco (se o dataset de bancos estiver disponível)
+	if boleto.Bancario != nil {
+		if banco, err := h.bancos.FindByCodigo(c.Request.Context(), boleto.Bancario.CodigoBanco); err == nil {
+			boleto.Bancario.Banco = banco
+		}
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    boleto,
+	})
+}
@@ -91,8 +91,6 @@ func (h *CEPHandler) GetCEP(c *gin.Context) {
 		return
 	}
 
-	ctx := c.Request.Context()
-
 	// ⏱️ Adicionar header com tempo de processamento do servidor (ao final da função)
 	defer func() {
 		serverTime := time.Since(startTime)
@@ -100,6 +98,25 @@ func (h *CEPHandler) GetCEP(c *gin.Context) {
 		fmt.Printf("⏱️ [CEP:%s] Tempo de processamento do servidor: %.2fms\n", cep, float64(serverTime.Microseconds())/1000.0)
 	}()
 
+	response := h.lookupCEP(c, cep)
+	if response == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "CEP Not Found",
+			"status": http.StatusNotFound,
+			"detail": fmt.Sprintf("CEP %s não encontrado", cep),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// lookupCEP consulta o CEP (8 dígitos) nas camadas Redis → MongoDB → ViaCEP → Brasil API, salvando nos caches.
+// Retorna nil quando nenhuma fonte encontrou o CEP. Usado também por /endereco/normalizar.
+func (h *CEPHandler) lookupCEP(c *gin.Context, cep string) *CEPResponse {
+	ctx := c.Request.Context()
+
 	// Carregar configurações de cache
 	settings, err := h.settings.Get(ctx)
 	if err != nil {
@@ -117,8 +134,7 @@ func (h *CEPHandler) GetCEP(c *gin.Context) {
 				if json.Unmarshal([]byte(cachedJSON), &cached) == nil {
 					cached.Source = "redis-cache"
 					fmt.Printf("✅ [CEP:%s] CACHE HIT → Redis L1 (ultra-rápido)\n", cep)
-					c.JSON(http.StatusOK, cached)
-					return // ⚡ <1ms!
+					return &cached // ⚡ <1ms!
 				}
 			}
 			fmt.Printf("⚠️ [CEP:%s] CACHE MISS → Redis L1 (tentando L2...)\n", cep)
@@ -158,8 +174,7 @@ func (h *CEPHandler) GetCEP(c *gin.Context) {
 					}
 				}
 				cached.Source = "mongodb-cache"
-				c.JSON(http.StatusOK, cached)
-				return // ~10ms
+				return &cached // ~10ms
 			} else {
 				fmt.Printf("⚠️ [CEP:%s] CACHE EXPIRADO → MongoDB L2 (TTL: %v, tentando APIs...)\n", cep, time.Since(cachedTime))
 			}
@@ -210,8 +225,7 @@ func (h *CEPHandler) GetCEP(c *gin.Context) {
 			}
 		}
 
-		c.JSON(http.StatusOK, response)
-		return
+		return response
 	}
 
 	fmt.Printf("⚠️ [CEP:%s] ERRO em ViaCEP: %v (tentando Brasil API...)\n", cep, err)
@@ -258,19 +272,13 @@ func (h *CEPHandler) GetCEP(c *gin.Context) {
 			}
 		}
 
-		c.JSON(http.StatusOK, response)
-		return
+		return response
 	}
 
 	fmt.Printf("❌ [CEP:%s] ERRO em Brasil API: %v (nenhuma fonte disponível)\n", cep, err)
 
 	// 4. CEP não encontrado
-	c.JSON(http.StatusNotFound, gin.H{
-		"type":   "https://retech-core/errors/not-found",
-		"title":  "CEP Not Found",
-		"status": http.StatusNotFound,
-		"detail": fmt.Sprintf("CEP %s não encontrado", cep),
-	})
+	return nil
 }
 
 // SearchCEP busca CEP por endereço (busca reversa)
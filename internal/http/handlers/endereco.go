@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// enderecoScoreMunicipioMin confiança mínima do resolver para corrigir o nome da cidade automaticamente
+const enderecoScoreMunicipioMin = 0.8
+
+type EnderecoHandler struct {
+	cep        *CEPHandler
+	geo        *GeoHandler // Índice do resolver de nomes de municípios
+	estados    *storage.EstadosRepo
+	municipios *storage.MunicipiosRepo
+}
+
+func NewEnderecoHandler(cep *CEPHandler, geo *GeoHandler, estados *storage.EstadosRepo, municipios *storage.MunicipiosRepo) *EnderecoHandler {
+	return &EnderecoHandler{
+		cep:        cep,
+		geo:        geo,
+		estados:    estados,
+		municipios: municipios,
+	}
+}
+
+// Normalizar interpreta, valida e padroniza um endereço brasileiro
+// POST /endereco/normalizar
+//
+// Aceita texto livre ("endereco") e/ou campos parciais (cep, logradouro, numero, complemento, bairro, cidade, uf).
+// Cruza com o CEP (ViaCEP/Brasil API com cache) e com os municípios/UFs do IBGE, apontando inconsistências
+// (ex: CEP de outra cidade) e retornando o endereço canônico.
+func (h *EnderecoHandler) Normalizar(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req domain.EnderecoEntrada
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "JSON inválido",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	interpretado := domain.ParseEndereco(req)
+	if interpretado.Vazio() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Informe 'endereco' (texto livre) ou ao menos um dos campos: cep, logradouro, numero, bairro, cidade, uf",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	resultado := domain.NovoEnderecoNormalizado(interpretado)
+	e := &resultado.Endereco
+
+	// 1. CEP: dados dos Correios passam a ser os canônicos
+	if e.CEP != "" {
+		if len(e.CEP) != 8 {
+			resultado.Adicionar("cep", "cep-invalido", domain.EnderecoSeveridadeErro, "CEP deve ter 8 dígitos", e.CEP, "")
+			e.CEP = ""
+		} else if ref := h.cep.lookupCEP(c, e.CEP); ref == nil {
+			resultado.Adicionar("cep", "cep-nao-encontrado", domain.EnderecoSeveridadeErro,
+				fmt.Sprintf("CEP %s não encontrado", domain.FormatarCEP(e.CEP)), e.CEP, "")
+		} else {
+			resultado.ConferirCEP(domain.EnderecoCEP{
+				CEP:        e.CEP,
+				Logradouro: ref.Logradouro,
+				Bairro:     ref.Bairro,
+				Cidade:     ref.Localidade,
+				UF:         ref.UF,
+				CodigoIBGE: ref.IBGE,
+				Fonte:      ref.Source,
+			})
+		}
+	}
+
+	// 2. UF
+	if e.UF != "" {
+		estado, err := h.estados.FindBySigla(ctx, e.UF)
+		if err != nil && err != mongo.ErrNoDocuments {
+			h.erroBanco(c)
+			return
+		}
+		if estado == nil {
+			resultado.Adicionar("uf", "uf-invalida", domain.EnderecoSeveridadeErro, fmt.Sprintf("UF '%s' não existe", e.UF), e.UF, "")
+			e.UF = ""
+		} else {
+			e.Estado = estado.Nome
+		}
+	}
+
+	// 3. Município: código IBGE do CEP ou resolução do nome informado
+	if err := h.conferirMunicipio(c, resultado); err != nil {
+		h.erroBanco(c)
+		return
+	}
+
+	resultado.Finalizar()
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    resultado,
+		Meta: gin.H{
+			"inconsistencias": len(resultado.Inconsistencias),
+		},
+	})
+}
+
+// conferirMunicipio completa cidade/UF/código IBGE a partir do IBGE e aponta cidades inexistentes ou de outra UF
+func (h *EnderecoHandler) conferirMunicipio(c *gin.Context, resultado *domain.EnderecoNormalizado) error {
+	ctx := c.Request.Context()
+	e := &resultado.Endereco
+
+	if e.CodigoIBGE != 0 {
+		municipio, err := h.municipios.FindByID(ctx, e.CodigoIBGE)
+		if err == mongo.ErrNoDocuments {
+			return nil // Código do CEP ainda não está na base do IBGE (mantém o nome do CEP)
+		}
+		if err != nil {
+			return err
+		}
+		h.aplicarMunicipio(ctx, resultado, *municipio)
+		return nil
+	}
+	if e.Cidade == "" {
+		return nil
+	}
+
+	index, err := h.geo.getResolverIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	candidatos := index.Resolver(e.Cidade, e.UF, 1)
+	if len(candidatos) == 0 || candidatos[0].Score < enderecoScoreMunicipioMin {
+		// Existe com esse nome em outra UF?
+		if e.UF != "" {
+			if outros := index.Resolver(e.Cidade, "", 1); len(outros) > 0 && outros[0].Score >= enderecoScoreMunicipioMin {
+				uf := outros[0].Municipio.Microrregiao.Mesorregiao.UF.Sigla
+				resultado.Adicionar("cidade", "municipio-uf-divergente", domain.EnderecoSeveridadeErro,
+					fmt.Sprintf("%s fica em %s, não em %s", outros[0].Municipio.Nome, uf, e.UF), e.Cidade+"/"+e.UF, outros[0].Municipio.Nome+"/"+uf)
+				return nil
+			}
+		}
+		esperado := ""
+		if len(candidatos) > 0 {
+			esperado = candidatos[0].Municipio.Nome // Sugestão de baixa confiança
+		}
+		resultado.Adicionar("cidade", "municipio-nao-encontrado", domain.EnderecoSeveridadeErro,
+			fmt.Sprintf("Município '%s' não encontrado no IBGE", e.Cidade), e.Cidade, esperado)
+		return nil
+	}
+
+	candidato := candidatos[0]
+	if candidato.Correspondencia != domain.MunicipioCorrespondenciaExata {
+		resultado.Adicionar("cidade", "municipio-corrigido", domain.EnderecoSeveridadeAviso,
+			fmt.Sprintf("Município identificado como %s (confiança %.0f%%)", candidato.Municipio.Nome, candidato.Score*100),
+			e.Cidade, candidato.Municipio.Nome)
+	}
+	h.aplicarMunicipio(ctx, resultado, candidato.Municipio)
+	return nil
+}
+
+// aplicarMunicipio usa nome, UF e código do IBGE como canônicos
+func (h *EnderecoHandler) aplicarMunicipio(ctx context.Context, resultado *domain.EnderecoNormalizado, municipio domain.Municipio) {
+	e := &resultado.Endereco
+	uf := municipio.Microrregiao.Mesorregiao.UF
+
+	e.Cidade = municipio.Nome
+	e.CodigoIBGE = municipio.ID
+	if e.UF == "" {
+		e.UF = uf.Sigla
+	}
+	if e.Estado == "" {
+		if estado, err := h.estados.FindBySigla(ctx, e.UF); err == nil {
+			e.Estado = estado.Nome
+		}
+	}
+	resultado.Fontes = append(resultado.Fontes, "ibge")
+}
+
+func (h *EnderecoHandler) erroBanco(c *gin.Context) {
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Type:     "https://retech-core/errors/database-error",
+		Title:    "Database Error",
+		Status:   http.StatusInternalServerError,
+		Detail:   "Erro ao validar endereço",
+		Instance: c.Request.URL.Path,
+	})
+}
@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/theretech/retech-core/internal/cache"
+	"github.com/theretech/retech-core/internal/domain"
 	"github.com/theretech/retech-core/internal/storage"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -16,17 +22,39 @@ import (
 type GeoHandler struct {
 	estados    *storage.EstadosRepo
 	municipios *storage.MunicipiosRepo
+	divisoes   *storage.GeoDivisoesRepo
+	malhas     *storage.GeoMalhasRepo
 	redis      interface{} // interface{} para permitir nil (graceful degradation)
+
+	// Índice em memória do resolver de nomes, reconstruído quando municípios/histórico mudam
+	resolverMu        sync.RWMutex
+	resolverIndex     *domain.MunicipioResolverIndex
+	resolverSignature string    // Quantidade e último updatedAt de municípios + alterações indexados
+	resolverCheckedAt time.Time // Última verificação de alteração
 }
 
-func NewGeoHandler(estados *storage.EstadosRepo, municipios *storage.MunicipiosRepo, redis interface{}) *GeoHandler {
+// geoResolverCheckInterval intervalo entre verificações de alteração (reconstrói o índice do resolver se mudou)
+const geoResolverCheckInterval = 5 * time.Minute
+
+func NewGeoHandler(estados *storage.EstadosRepo, municipios *storage.MunicipiosRepo, divisoes *storage.GeoDivisoesRepo, malhas *storage.GeoMalhasRepo, redis interface{}) *GeoHandler {
 	return &GeoHandler{
 		estados:    estados,
 		municipios: municipios,
+		divisoes:   divisoes,
+		malhas:     malhas,
 		redis:      redis,
 	}
 }
 
+// geoNivelNomes nome de cada nível da divisão regional (mensagens de erro)
+var geoNivelNomes = map[string]string{
+	domain.GeoNivelRegiao:              "Região",
+	domain.GeoNivelMesorregiao:         "Mesorregião",
+	domain.GeoNivelMicrorregiao:        "Microrregião",
+	domain.GeoNivelRegiaoIntermediaria: "Região intermediária",
+	domain.GeoNivelRegiaoImediata:      "Região imediata",
+}
+
 // Response padrão de sucesso
 type SuccessResponse struct {
 	Success bool        `json:"success"`
@@ -177,86 +205,112 @@ func (h *GeoHandler) GetUF(c *gin.Context) {
 // ListMunicipios retorna todos os municípios (ou filtra por UF)
 // GET /geo/municipios
 // GET /geo/municipios?uf=PE
+// GET /geo/municipios?uf=PE&fields=id,nome,uf&sort=-nome&limit=50&cursor=...
+// Sem limit/cursor a lista é completa; com q (busca por nome) a página padrão é de 100 itens.
+// Respostas com ETag forte: envie If-None-Match para revalidar (304 sem corpo).
 func (h *GeoHandler) ListMunicipios(c *gin.Context) {
 	ctx := c.Request.Context()
 	uf := c.Query("uf")
 	query := c.Query("q")
 
-	// ⚡ CACHE REDIS (apenas sem filtros complexos)
-	if query == "" && h.redis != nil {
-		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
-			var redisKey string
-			if uf != "" {
-				redisKey = fmt.Sprintf("geo:municipios:uf:%s", uf)
-			} else {
-				redisKey = "geo:municipios:all"
-			}
+	// Paginação, projeção ou ordenação → consulta parametrizada
+	if query != "" || c.Query("limit") != "" || c.Query("cursor") != "" || c.Query("fields") != "" || c.Query("sort") != "" {
+		h.listMunicipiosPage(c)
+		return
+	}
+
+	var redisKey string
+	if uf != "" {
+		redisKey = fmt.Sprintf("geo:municipios:uf:%s", uf)
+	} else {
+		redisKey = "geo:municipios:all"
+	}
 
+	// ⚡ CACHE REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
 			cachedJSON, err := redisClient.Get(ctx, redisKey)
 			if err == nil && cachedJSON != "" {
-				c.Header("Content-Type", "application/json")
-				c.String(http.StatusOK, cachedJSON)
+				writeJSONWithETag(c, []byte(cachedJSON))
 				return // ⚡ <1ms!
 			}
 		}
 	}
 
-	// Se tem busca por nome
-	if query != "" {
-		municipios, err := h.municipios.Search(ctx, query, uf)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Type:     "https://retech-core/errors/database-error",
-				Title:    "Database Error",
-				Status:   http.StatusInternalServerError,
-				Detail:   "Erro ao buscar municípios",
-				Instance: c.Request.URL.Path,
-			})
-			return
-		}
-
-		c.JSON(http.StatusOK, SuccessResponse{
-			Success: true,
-			Code:    "OK",
-			Data:    municipios,
+	var municipios []domain.Municipio
+	var err error
+	if uf != "" {
+		municipios, err = h.municipios.FindByUF(ctx, uf)
+	} else {
+		// Retorna todos (cuidado: pode ser muito grande - 5.570 municípios, ~500KB; prefira limit/fields)
+		municipios, err = h.municipios.FindAll(ctx)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar municípios",
+			Instance: c.Request.URL.Path,
 		})
 		return
 	}
 
-	// Se filtra por UF
-	if uf != "" {
-		municipios, err := h.municipios.FindByUF(ctx, uf)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Type:     "https://retech-core/errors/database-error",
-				Title:    "Database Error",
-				Status:   http.StatusInternalServerError,
-				Detail:   "Erro ao buscar municípios",
-				Instance: c.Request.URL.Path,
-			})
-			return
+	response := SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    municipios,
+	}
+	body, _ := json.Marshal(response)
+
+	// ✅ SALVAR NO REDIS (dados fixos, cache longo)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
 		}
+	}
 
-		response := SuccessResponse{
-			Success: true,
-			Code:    "OK",
-			Data:    municipios,
+	writeJSONWithETag(c, body)
+}
+
+// listMunicipiosPage busca com cursor, projeção (fields) e ordenação (sort)
+func (h *GeoHandler) listMunicipiosPage(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	q, err := parseMunicipioQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// Buscas por nome não vão para o cache (combinações demais)
+	redisKey := ""
+	if q.Q == "" {
+		cursor := ""
+		if q.Cursor != nil {
+			cursor = q.Cursor.Encode()
 		}
+		redisKey = fmt.Sprintf("geo:municipios:page:%s:%s:%d:%s:%s", q.UF, q.Sort, q.Limit, strings.Join(q.Fields, ","), cursor)
+	}
 
-		// ✅ SALVAR NO REDIS (dados fixos, cache longo)
-		if h.redis != nil {
-			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
-				redisKey := fmt.Sprintf("geo:municipios:uf:%s", uf)
-				redisClient.Set(ctx, redisKey, response, 24*time.Hour)
+	// ⚡ CACHE REDIS
+	if redisKey != "" && h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, redisKey)
+			if err == nil && cachedJSON != "" {
+				writeJSONWithETag(c, []byte(cachedJSON))
+				return // ⚡ <1ms!
 			}
 		}
-
-		c.JSON(http.StatusOK, response)
-		return
 	}
 
-	// Retorna todos (cuidado: pode ser muito grande - 5.570 municípios)
-	municipios, err := h.municipios.FindAll(ctx)
+	municipios, hasMore, err := h.municipios.FindPage(ctx, q)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Type:     "https://retech-core/errors/database-error",
@@ -268,21 +322,116 @@ func (h *GeoHandler) ListMunicipios(c *gin.Context) {
 		return
 	}
 
+	var data interface{} = municipios
+	if len(q.Fields) > 0 {
+		projetados := make([]map[string]interface{}, 0, len(municipios))
+		for _, m := range municipios {
+			projetados = append(projetados, domain.ProjectMunicipio(m, q.Fields))
+		}
+		data = projetados
+	}
+
+	meta := gin.H{
+		"total":   len(municipios),
+		"sort":    q.Sort,
+		"hasMore": hasMore,
+	}
+	if q.Limit > 0 {
+		meta["limit"] = q.Limit
+	}
+	if hasMore {
+		meta["nextCursor"] = q.NextCursor(municipios[len(municipios)-1])
+	}
+
 	response := SuccessResponse{
 		Success: true,
 		Code:    "OK",
-		Data:    municipios,
+		Data:    data,
+		Meta:    meta,
 	}
+	body, _ := json.Marshal(response)
 
-	// ✅ SALVAR NO REDIS (5.570 municípios, ~500KB)
-	if h.redis != nil {
+	// ✅ SALVAR NO REDIS
+	if redisKey != "" && h.redis != nil {
 		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
-			redisKey := "geo:municipios:all"
-			redisClient.Set(ctx, redisKey, response, 24*time.Hour)
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	writeJSONWithETag(c, body)
+}
+
+// parseMunicipioQuery lê uf, q, sort, fields, limit e cursor da query string
+func parseMunicipioQuery(c *gin.Context) (domain.MunicipioQuery, error) {
+	q := domain.MunicipioQuery{
+		UF:   strings.ToUpper(c.Query("uf")),
+		Q:    strings.TrimSpace(c.Query("q")),
+		Sort: strings.ToLower(c.DefaultQuery("sort", "nome")),
+	}
+	if !domain.MunicipioSorts[q.Sort] {
+		return q, fmt.Errorf("sort inválido. Use: nome, -nome, id, -id")
+	}
+
+	fields, err := domain.ParseMunicipioFields(c.Query("fields"))
+	if err != nil {
+		return q, err
+	}
+	q.Fields = fields
+
+	// Página padrão apenas quando há paginação ou busca; fields/sort sozinhos mantêm a lista completa
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > domain.MunicipiosLimitMax {
+			return q, fmt.Errorf("limit deve ser entre 1 e %d", domain.MunicipiosLimitMax)
+		}
+		q.Limit = limit
+	} else if q.Q != "" || c.Query("cursor") != "" {
+		q.Limit = domain.MunicipiosLimitPadrao
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := domain.DecodeMunicipioCursor(cursorStr)
+		if err != nil {
+			return q, err
+		}
+		if cursor.Sort != q.Sort {
+			return q, fmt.Errorf("cursor gerado com sort=%s: mantenha a mesma ordenação entre as páginas", cursor.Sort)
+		}
+		q.Cursor = cursor
+	}
+
+	return q, nil
+}
+
+// writeJSONWithETag responde com ETag forte (hash do corpo) e 304 quando o If-None-Match confere.
+// Dados de referência: o cliente guarda a resposta e só baixa de novo quando o conteúdo muda.
+func writeJSONWithETag(c *gin.Context, body []byte) {
+	writeWithETag(c, "application/json; charset=utf-8", body)
+}
+
+func writeWithETag(c *gin.Context, contentType string, body []byte) {
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, max-age=86400")
+
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, contentType, body)
+}
+
+// etagMatches comparação fraca do If-None-Match (RFC 9110): lista separada por vírgula, "*" e prefixo W/
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidato := range strings.Split(ifNoneMatch, ",") {
+		candidato = strings.TrimPrefix(strings.TrimSpace(candidato), "W/")
+		if candidato == "*" || candidato == etag {
+			return true
+		}
+	}
+	return false
 }
 
 // ListMunicipiosByUF retorna municípios de um estado específico
@@ -297,8 +446,7 @@ func (h *GeoHandler) ListMunicipiosByUF(c *gin.Context) {
 			redisKey := fmt.Sprintf("geo:municipios:uf:%s", uf)
 			cachedJSON, err := redisClient.Get(ctx, redisKey)
 			if err == nil && cachedJSON != "" {
-				c.Header("Content-Type", "application/json")
-				c.String(http.StatusOK, cachedJSON)
+				writeJSONWithETag(c, []byte(cachedJSON))
 				return // ⚡ <1ms!
 			}
 		}
@@ -321,20 +469,22 @@ func (h *GeoHandler) ListMunicipiosByUF(c *gin.Context) {
 		Code:    "OK",
 		Data:    municipios,
 	}
+	body, _ := json.Marshal(response)
 
 	// ✅ SALVAR NO REDIS
 	if h.redis != nil {
 		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
 			redisKey := fmt.Sprintf("geo:municipios:uf:%s", uf)
-			redisClient.Set(ctx, redisKey, response, 24*time.Hour)
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	writeJSONWithETag(c, body)
 }
 
 // GetMunicipio retorna um município pelo ID do IBGE
 // GET /geo/municipios/id/:id
+// GET /geo/municipios/id/:id?include=populacao,area,codigos,ddd,fuso,capital,coordenadas (ou include=all)
 func (h *GeoHandler) GetMunicipio(c *gin.Context) {
 	ctx := c.Request.Context()
 	idStr := c.Param("id")
@@ -351,14 +501,29 @@ func (h *GeoHandler) GetMunicipio(c *gin.Context) {
 		return
 	}
 
+	includes, err := domain.ParseMunicipioIncludes(c.Query("include"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	redisKey := fmt.Sprintf("geo:municipio:id:%d", id)
+	if len(includes) > 0 {
+		redisKey += ":" + strings.Join(includes, ",")
+	}
+
 	// ⚡ CACHE REDIS
 	if h.redis != nil {
 		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
-			redisKey := fmt.Sprintf("geo:municipio:id:%d", id)
 			cachedJSON, err := redisClient.Get(ctx, redisKey)
 			if err == nil && cachedJSON != "" {
-				c.Header("Content-Type", "application/json")
-				c.String(http.StatusOK, cachedJSON)
+				writeJSONWithETag(c, []byte(cachedJSON))
 				return // ⚡ <1ms!
 			}
 		}
@@ -386,19 +551,969 @@ func (h *GeoHandler) GetMunicipio(c *gin.Context) {
 		return
 	}
 
+	var data interface{} = municipio
+	if len(includes) > 0 {
+		enriquecido, err := h.enriquecerMunicipio(ctx, *municipio, includes)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Type:     "https://retech-core/errors/database-error",
+				Title:    "Database Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "Erro ao buscar dados complementares do município",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+		data = enriquecido
+	}
+
 	response := SuccessResponse{
 		Success: true,
 		Code:    "OK",
-		Data:    municipio,
+		Data:    data,
 	}
+	body, _ := json.Marshal(response)
 
 	// ✅ SALVAR NO REDIS
 	if h.redis != nil {
 		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
-			redisKey := fmt.Sprintf("geo:municipio:id:%d", id)
-			redisClient.Set(ctx, redisKey, response, 24*time.Hour)
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
 		}
 	}
 
-	c.JSON(http.StatusOK, response)
+	writeJSONWithETag(c, body)
+}
+
+// GetMunicipioByCodigo busca o município por código alternativo (campo = "siafi" ou "tse")
+// GET /geo/municipios/siafi/:codigo
+// GET /geo/municipios/tse/:codigo
+// Aceita ?include= como /geo/municipios/id/:id (padrão: codigos)
+func (h *GeoHandler) GetMunicipioByCodigo(campo string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		codigo := strings.TrimSpace(c.Param("codigo"))
+		if campo == "siafi" {
+			codigo = domain.NormalizarCodigoSiafi(codigo)
+		} else {
+			codigo = domain.NormalizarCodigoTSE(codigo)
+		}
+		if _, err := strconv.Atoi(codigo); err != nil || len(codigo) > 5 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Type:     "https://retech-core/errors/validation-error",
+				Title:    "Validation Error",
+				Status:   http.StatusBadRequest,
+				Detail:   fmt.Sprintf("Código %s inválido", strings.ToUpper(campo)),
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+
+		includes, err := domain.ParseMunicipioIncludes(c.DefaultQuery("include", "codigos"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Type:     "https://retech-core/errors/validation-error",
+				Title:    "Validation Error",
+				Status:   http.StatusBadRequest,
+				Detail:   err.Error(),
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+
+		redisKey := fmt.Sprintf("geo:municipio:%s:%s:%s", campo, codigo, strings.Join(includes, ","))
+
+		// ⚡ CACHE REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				cachedJSON, err := redisClient.Get(ctx, redisKey)
+				if err == nil && cachedJSON != "" {
+					writeJSONWithETag(c, []byte(cachedJSON))
+					return // ⚡ <1ms!
+				}
+			}
+		}
+
+		dados, err := h.municipios.FindDadosByCodigo(ctx, campo, codigo)
+		var municipio *domain.Municipio
+		if err == nil {
+			municipio, err = h.municipios.FindByID(ctx, dados.ID)
+		}
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, ErrorResponse{
+					Type:     "https://retech-core/errors/not-found",
+					Title:    "Not Found",
+					Status:   http.StatusNotFound,
+					Detail:   fmt.Sprintf("Município com código %s %s não encontrado", strings.ToUpper(campo), codigo),
+					Instance: c.Request.URL.Path,
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Type:     "https://retech-core/errors/database-error",
+				Title:    "Database Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "Erro ao buscar município",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+
+		var data interface{} = municipio
+		if len(includes) > 0 {
+			data = domain.MunicipioEnriquecido{Municipio: *municipio, Dados: dados.Filtrar(includes)}
+		}
+
+		response := SuccessResponse{
+			Success: true,
+			Code:    "OK",
+			Data:    data,
+		}
+		body, _ := json.Marshal(response)
+
+		// ✅ SALVAR NO REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
+			}
+		}
+
+		writeJSONWithETag(c, body)
+	}
+}
+
+// GetMunicipioHistorico retorna as alterações (criação, renomeação, incorporação, mudança de código) de um município
+// GET /geo/municipios/id/:id/historico
+// Aceita também códigos extintos: "vigente" = false e "atual" aponta para o município que o substituiu
+func (h *GeoHandler) GetMunicipioHistorico(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "ID inválido",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	redisKey := fmt.Sprintf("geo:municipio:historico:%d", id)
+
+	// ⚡ CACHE REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, redisKey)
+			if err == nil && cachedJSON != "" {
+				writeJSONWithETag(c, []byte(cachedJSON))
+				return // ⚡ <1ms!
+			}
+		}
+	}
+
+	alteracoes, err := h.municipios.FindAlteracoes(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar histórico do município",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	municipio, err := h.municipios.FindByID(ctx, id)
+	if err != nil && err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar município",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	historico := domain.HistoricoMunicipio(alteracoes, id)
+	data := gin.H{
+		"codigo":     id,
+		"vigente":    municipio != nil,
+		"alteracoes": historico,
+	}
+	if municipio != nil {
+		data["municipio"] = municipio
+	} else if resolucao, ok := domain.ResolverCodigoLegado(alteracoes, id); ok {
+		data["atual"] = resolucao.Codigo
+	} else {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Type:     "https://retech-core/errors/not-found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "Município não encontrado (nem no histórico de alterações)",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	response := SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    data,
+		Meta:    gin.H{"total": len(historico)},
+	}
+	body, _ := json.Marshal(response)
+
+	// ✅ SALVAR NO REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
+		}
+	}
+
+	writeJSONWithETag(c, body)
+}
+
+// ResolverMunicipioLegado mapeia um código IBGE ou nome antigo para o município vigente
+// GET /geo/municipios/legado?codigo=3515004
+// GET /geo/municipios/legado?codigo=351500 (6 dígitos, sem dígito verificador)
+// GET /geo/municipios/legado?nome=Embu&uf=SP
+func (h *GeoHandler) ResolverMunicipioLegado(c *gin.Context) {
+	ctx := c.Request.Context()
+	codigoStr := strings.TrimSpace(c.Query("codigo"))
+	nome := strings.TrimSpace(c.Query("nome"))
+	uf := strings.ToUpper(strings.TrimSpace(c.Query("uf")))
+
+	codigo, err := strconv.Atoi(codigoStr)
+	switch {
+	case codigoStr == "" && nome == "":
+		err = fmt.Errorf("informe ?codigo= ou ?nome=")
+	case codigoStr != "" && nome != "":
+		err = fmt.Errorf("informe apenas um entre ?codigo= e ?nome=")
+	case codigoStr != "" && (err != nil || (len(codigoStr) != 6 && len(codigoStr) != 7)):
+		err = fmt.Errorf("código deve ter 6 ou 7 dígitos")
+	default:
+		err = nil
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	redisKey := fmt.Sprintf("geo:municipio:legado:%s:%s:%s", codigoStr, domain.NormalizeSearchText(nome), uf)
+
+	// ⚡ CACHE REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, redisKey)
+			if err == nil && cachedJSON != "" {
+				writeJSONWithETag(c, []byte(cachedJSON))
+				return // ⚡ <1ms!
+			}
+		}
+	}
+
+	var resolucoes []domain.MunicipioLegadoResolucao
+	if codigoStr != "" {
+		resolucoes, err = h.resolverCodigoLegado(ctx, codigo, len(codigoStr) == 6)
+	} else {
+		resolucoes, err = h.resolverNomeLegado(ctx, nome, uf)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao resolver município",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+	if len(resolucoes) == 0 {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Type:     "https://retech-core/errors/not-found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "Nenhum município vigente ou histórico corresponde ao código/nome informado",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// Anexar o município vigente de cada resolução
+	for i := range resolucoes {
+		if resolucoes[i].Municipio != nil {
+			continue
+		}
+		municipio, err := h.municipios.FindByID(ctx, resolucoes[i].Codigo)
+		if err != nil && err != mongo.ErrNoDocuments {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Type:     "https://retech-core/errors/database-error",
+				Title:    "Database Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "Erro ao buscar município",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+		resolucoes[i].Municipio = municipio
+	}
+
+	response := SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    resolucoes,
+		Meta:    gin.H{"total": len(resolucoes)},
+	}
+	body, _ := json.Marshal(response)
+
+	// ✅ SALVAR NO REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
+		}
+	}
+
+	writeJSONWithETag(c, body)
+}
+
+// resolverCodigoLegado código vigente → ele mesmo; extinto → segue incorporações/mudanças de código
+func (h *GeoHandler) resolverCodigoLegado(ctx context.Context, codigo int, semDV bool) ([]domain.MunicipioLegadoResolucao, error) {
+	var (
+		municipio *domain.Municipio
+		err       error
+	)
+	if semDV {
+		municipio, err = h.municipios.FindByCodigoSemDV(ctx, codigo)
+	} else {
+		municipio, err = h.municipios.FindByID(ctx, codigo)
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	alteracoes, err := h.municipios.FindAlteracoes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if municipio != nil {
+		motivo := "atual"
+		if semDV {
+			motivo = "codigo-6-digitos"
+		}
+		return []domain.MunicipioLegadoResolucao{{
+			Codigo:     municipio.ID,
+			Motivo:     motivo,
+			Municipio:  municipio,
+			Alteracoes: domain.HistoricoMunicipio(alteracoes, municipio.ID),
+		}}, nil
+	}
+
+	resolucao, ok := domain.ResolverCodigoLegado(alteracoes, codigo)
+	if semDV {
+		resolucao, ok = domain.ResolverCodigoSemDV(alteracoes, codigo)
+	}
+	if !ok {
+		return nil, nil
+	}
+	return []domain.MunicipioLegadoResolucao{resolucao}, nil
+}
+
+// resolverNomeLegado nome vigente (igualdade sem acento/caixa) e nomes anteriores do histórico
+func (h *GeoHandler) resolverNomeLegado(ctx context.Context, nome, uf string) ([]domain.MunicipioLegadoResolucao, error) {
+	// Comparação sem acento é feita em memória (regex do Mongo não ignora acentos)
+	var candidatos []domain.Municipio
+	var err error
+	if uf != "" {
+		candidatos, err = h.municipios.FindByUF(ctx, uf)
+	} else {
+		candidatos, err = h.municipios.FindAll(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resolucoes := []domain.MunicipioLegadoResolucao{}
+	vistos := map[int]bool{}
+	normalizado := domain.NormalizeSearchText(nome)
+	for i := range candidatos {
+		if domain.NormalizeSearchText(candidatos[i].Nome) == normalizado {
+			vistos[candidatos[i].ID] = true
+			resolucoes = append(resolucoes, domain.MunicipioLegadoResolucao{
+				Codigo:     candidatos[i].ID,
+				Motivo:     "atual",
+				Municipio:  &candidatos[i],
+				Alteracoes: []domain.MunicipioAlteracao{},
+			})
+		}
+	}
+
+	alteracoes, err := h.municipios.FindAlteracoes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, resolucao := range domain.ResolverNomeLegado(alteracoes, nome, uf) {
+		if !vistos[resolucao.Codigo] {
+			vistos[resolucao.Codigo] = true
+			resolucoes = append(resolucoes, resolucao)
+		}
+	}
+	return resolucoes, nil
+}
+
+// ResolverMunicipio mapeia nome livre (CSV, formulários) para municípios do IBGE, com ranking por confiança
+// GET /geo/municipios/resolver?nome=sao jose dos campo&uf=SP&limit=5
+// Ignora acentos/caixa/pontuação, expande abreviações ("S.", "Sta.", "Pres."), unifica "D'Oeste",
+// tolera erros de digitação e aceita a UF junto do nome ("Recife/PE", "Recife - PE").
+func (h *GeoHandler) ResolverMunicipio(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	nome, ufNome := domain.SepararUFNome(c.Query("nome"))
+	uf := strings.ToUpper(strings.TrimSpace(c.Query("uf")))
+	if uf == "" {
+		uf = ufNome
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(domain.MunicipioResolverLimitPadrao)))
+	switch {
+	case domain.NormalizarNomeMunicipio(nome) == "":
+		err = fmt.Errorf("parâmetro 'nome' é obrigatório")
+	case uf != "" && !domain.SiglasUF[uf]:
+		err = fmt.Errorf("UF '%s' inválida", uf)
+	case err != nil || limit < 1 || limit > domain.MunicipioResolverLimitMax:
+		err = fmt.Errorf("limit deve estar entre 1 e %d", domain.MunicipioResolverLimitMax)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	index, err := h.getResolverIndex(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao carregar índice de municípios",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	candidatos := index.Resolver(nome, uf, limit)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    candidatos,
+		Meta: gin.H{
+			"nome":        nome,
+			"normalizado": domain.NormalizarNomeMunicipio(nome),
+			"uf":          uf,
+			"total":       len(candidatos),
+		},
+	})
+}
+
+// getResolverIndex retorna o índice do resolver, reconstruindo-o quando municípios ou histórico mudaram
+func (h *GeoHandler) getResolverIndex(ctx context.Context) (*domain.MunicipioResolverIndex, error) {
+	h.resolverMu.RLock()
+	index := h.resolverIndex
+	checkedAt := h.resolverCheckedAt
+	h.resolverMu.RUnlock()
+
+	if index != nil && time.Since(checkedAt) < geoResolverCheckInterval {
+		return index, nil
+	}
+
+	h.resolverMu.Lock()
+	defer h.resolverMu.Unlock()
+
+	// Outra requisição pode ter verificado enquanto esperávamos o lock
+	if h.resolverIndex != nil && time.Since(h.resolverCheckedAt) < geoResolverCheckInterval {
+		return h.resolverIndex, nil
+	}
+
+	totalMunicipios, err := h.municipios.Count(ctx)
+	if err != nil {
+		return h.fallbackResolverIndex(err)
+	}
+	municipiosUpdatedAt, err := h.municipios.LastUpdatedAt(ctx)
+	if err != nil {
+		return h.fallbackResolverIndex(err)
+	}
+	alteracoes, err := h.municipios.FindAlteracoes(ctx)
+	if err != nil {
+		return h.fallbackResolverIndex(err)
+	}
+	// Quantidade sozinha não detecta renomeações (re-seed com o mesmo total): updatedAt muda a cada gravação
+	var alteracoesUpdatedAt time.Time
+	for _, a := range alteracoes {
+		if a.UpdatedAt.After(alteracoesUpdatedAt) {
+			alteracoesUpdatedAt = a.UpdatedAt
+		}
+	}
+	signature := fmt.Sprintf("%d:%d:%d:%d", totalMunicipios, municipiosUpdatedAt.UnixNano(), len(alteracoes), alteracoesUpdatedAt.UnixNano())
+	if h.resolverIndex != nil && signature == h.resolverSignature {
+		h.resolverCheckedAt = time.Now()
+		return h.resolverIndex, nil
+	}
+
+	municipios, err := h.municipios.FindAll(ctx)
+	if err != nil {
+		return h.fallbackResolverIndex(err)
+	}
+
+	h.resolverIndex = domain.NewMunicipioResolverIndex(municipios, alteracoes)
+	h.resolverSignature = signature
+	h.resolverCheckedAt = time.Now()
+	return h.resolverIndex, nil
+}
+
+// fallbackResolverIndex mantém o índice antigo (se houver) quando a reconstrução falha
+func (h *GeoHandler) fallbackResolverIndex(err error) (*domain.MunicipioResolverIndex, error) {
+	if h.resolverIndex != nil {
+		return h.resolverIndex, nil
+	}
+	return nil, err
+}
+
+// enriquecerMunicipio anexa os dados complementares pedidos (sem dataset carregado, "dados" fica ausente)
+func (h *GeoHandler) enriquecerMunicipio(ctx context.Context, municipio domain.Municipio, includes []string) (domain.MunicipioEnriquecido, error) {
+	enriquecido := domain.MunicipioEnriquecido{Municipio: municipio}
+
+	dados, err := h.municipios.FindDados(ctx, municipio.ID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return enriquecido, nil
+		}
+		return enriquecido, err
+	}
+	enriquecido.Dados = dados.Filtrar(includes)
+	return enriquecido, nil
+}
+
+// ListDivisoes retorna as divisões de um nível (regiões, mesorregiões, microrregiões, regiões intermediárias/imediatas)
+// GET /geo/mesorregioes
+// GET /geo/mesorregioes?uf=PE
+func (h *GeoHandler) ListDivisoes(nivel string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		uf := strings.ToUpper(c.Query("uf"))
+
+		redisKey := fmt.Sprintf("geo:%s:all", nivel)
+		if uf != "" {
+			redisKey = fmt.Sprintf("geo:%s:uf:%s", nivel, uf)
+		}
+
+		// ⚡ CACHE REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				cachedJSON, err := redisClient.Get(ctx, redisKey)
+				if err == nil && cachedJSON != "" {
+					c.Header("Content-Type", "application/json")
+					c.String(http.StatusOK, cachedJSON)
+					return // ⚡ <1ms!
+				}
+			}
+		}
+
+		// 🗄️ BUSCAR DO MONGODB (collection pré-agregada no seed)
+		divisoes, err := h.divisoes.FindAll(ctx, nivel, uf)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Type:     "https://retech-core/errors/database-error",
+				Title:    "Database Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   fmt.Sprintf("Erro ao buscar %s", strings.ToLower(geoNivelNomes[nivel])),
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+
+		response := SuccessResponse{
+			Success: true,
+			Code:    "OK",
+			Data:    divisoes,
+			Meta: gin.H{
+				"nivel": nivel,
+				"total": len(divisoes),
+			},
+		}
+
+		// ✅ SALVAR NO REDIS (cache longo, dados fixos)
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				redisClient.Set(ctx, redisKey, response, 24*time.Hour)
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// GetDivisao retorna uma divisão pelo ID do IBGE (regiões também aceitam a sigla: /geo/regioes/NE)
+// GET /geo/mesorregioes/:id
+func (h *GeoHandler) GetDivisao(nivel string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		redisKey := fmt.Sprintf("geo:%s:id:%s", nivel, strings.ToUpper(c.Param("id")))
+
+		// ⚡ CACHE REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				cachedJSON, err := redisClient.Get(ctx, redisKey)
+				if err == nil && cachedJSON != "" {
+					c.Header("Content-Type", "application/json")
+					c.String(http.StatusOK, cachedJSON)
+					return // ⚡ <1ms!
+				}
+			}
+		}
+
+		divisao, ok := h.findDivisao(c, nivel)
+		if !ok {
+			return
+		}
+
+		response := SuccessResponse{
+			Success: true,
+			Code:    "OK",
+			Data:    divisao,
+		}
+
+		// ✅ SALVAR NO REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				redisClient.Set(ctx, redisKey, response, 24*time.Hour)
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// ListMunicipiosByDivisao retorna os municípios de uma divisão
+// GET /geo/regioes-imediatas/:id/municipios
+func (h *GeoHandler) ListMunicipiosByDivisao(nivel string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		redisKey := fmt.Sprintf("geo:%s:id:%s:municipios", nivel, strings.ToUpper(c.Param("id")))
+
+		// ⚡ CACHE REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				cachedJSON, err := redisClient.Get(ctx, redisKey)
+				if err == nil && cachedJSON != "" {
+					writeJSONWithETag(c, []byte(cachedJSON))
+					return // ⚡ <1ms!
+				}
+			}
+		}
+
+		divisao, ok := h.findDivisao(c, nivel)
+		if !ok {
+			return
+		}
+
+		municipios, err := h.municipios.FindByDivisao(ctx, nivel, divisao.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Type:     "https://retech-core/errors/database-error",
+				Title:    "Database Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "Erro ao buscar municípios",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+
+		response := SuccessResponse{
+			Success: true,
+			Code:    "OK",
+			Data:    municipios,
+			Meta: gin.H{
+				"nivel":   nivel,
+				"divisao": domain.GeoDivisaoRef{ID: divisao.ID, Nome: divisao.Nome},
+				"total":   len(municipios),
+			},
+		}
+		body, _ := json.Marshal(response)
+
+		// ✅ SALVAR NO REDIS
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
+			}
+		}
+
+		writeJSONWithETag(c, body)
+	}
+}
+
+// findDivisao busca a divisão do parâmetro :id, respondendo 400/404/500 quando não encontrada
+func (h *GeoHandler) findDivisao(c *gin.Context, nivel string) (*domain.GeoDivisao, bool) {
+	ctx := c.Request.Context()
+	idStr := c.Param("id")
+
+	var divisao *domain.GeoDivisao
+	var err error
+	if id, convErr := strconv.Atoi(idStr); convErr == nil {
+		divisao, err = h.divisoes.FindByID(ctx, nivel, id)
+	} else if nivel == domain.GeoNivelRegiao {
+		divisao, err = h.divisoes.FindRegiaoBySigla(ctx, idStr)
+	} else {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "ID inválido",
+			Instance: c.Request.URL.Path,
+		})
+		return nil, false
+	}
+
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Type:     "https://retech-core/errors/not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   fmt.Sprintf("%s não encontrada", geoNivelNomes[nivel]),
+				Instance: c.Request.URL.Path,
+			})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   fmt.Sprintf("Erro ao buscar %s", strings.ToLower(geoNivelNomes[nivel])),
+			Instance: c.Request.URL.Path,
+		})
+		return nil, false
+	}
+	return divisao, true
+}
+
+// GetMunicipioGeoJSON retorna o contorno do município como GeoJSON (Feature)
+// GET /geo/municipios/id/:id/geojson?qualidade=intermediaria
+// qualidade: maxima (original), intermediaria (padrão, ~100 m) ou minima (~1 km)
+func (h *GeoHandler) GetMunicipioGeoJSON(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "ID inválido",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	municipio, err := h.municipios.FindByID(ctx, id)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Type:     "https://retech-core/errors/not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "Município não encontrado",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar município",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	h.writeMalha(c, domain.GeoMalhaMunicipio, municipio.ID, gin.H{
+		"codigo": municipio.ID,
+		"nome":   municipio.Nome,
+		"uf":     municipio.Microrregiao.Mesorregiao.UF.Sigla,
+	})
+}
+
+// GetUFGeoJSON retorna o contorno do estado como GeoJSON (Feature)
+// GET /geo/ufs/:sigla/geojson?qualidade=minima
+func (h *GeoHandler) GetUFGeoJSON(c *gin.Context) {
+	ctx := c.Request.Context()
+	sigla := strings.ToUpper(c.Param("sigla"))
+
+	estado, err := h.estados.FindBySigla(ctx, sigla)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Type:     "https://retech-core/errors/not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "Estado não encontrado",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar estado",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	h.writeMalha(c, domain.GeoMalhaUF, estado.ID, gin.H{
+		"codigo": estado.ID,
+		"nome":   estado.Nome,
+		"sigla":  estado.Sigla,
+	})
+}
+
+// writeMalha responde a malha na qualidade pedida como Feature GeoJSON (com cache e ETag)
+func (h *GeoHandler) writeMalha(c *gin.Context, tipo string, codigo int, properties gin.H) {
+	ctx := c.Request.Context()
+
+	qualidade := strings.ToLower(c.DefaultQuery("qualidade", domain.GeoQualidadeIntermediaria))
+	if _, ok := domain.GeoQualidadeTolerancia[qualidade]; !ok && qualidade != domain.GeoQualidadeMaxima {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "qualidade inválida. Use: maxima, intermediaria ou minima",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	redisKey := fmt.Sprintf("geo:malha:%s:%d:%s", tipo, codigo, qualidade)
+
+	// ⚡ CACHE REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, redisKey)
+			if err == nil && cachedJSON != "" {
+				writeWithETag(c, "application/geo+json", []byte(cachedJSON))
+				return // ⚡ <1ms!
+			}
+		}
+	}
+
+	malha, err := h.malhas.FindByCodigo(ctx, tipo, codigo, qualidade)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Type:     "https://retech-core/errors/not-found",
+				Title:    "Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   "Malha não disponível para este código",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/database-error",
+			Title:    "Database Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar malha",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	properties["qualidade"] = qualidade
+	feature := domain.GeoFeature{
+		Type:       "Feature",
+		Properties: properties,
+		Geometry:   malha.GeometriaQualidade(qualidade),
+	}
+	body, _ := json.Marshal(feature)
+
+	// ✅ SALVAR NO REDIS (contornos mudam só no seed)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			redisClient.SetString(ctx, redisKey, string(body), 24*time.Hour)
+		}
+	}
+
+	writeWithETag(c, "application/geo+json", body)
+}
+
+// Reverse retorna o município que contém a coordenada (point-in-polygon na malha original)
+// GET /geo/reverse?lat=-8.0476&lng=-34.8770
+func (h *GeoHandler) Reverse(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	lat, errLat := strconv.ParseFloat(c.Query("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.Query("lng"), 64)
+	if errLat != nil || errLng != nil || lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Parâmetros 'lat' (-90 a 90) e 'lng' (-180 a 180) são obrigatórios (graus decimais)",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	codigo, err := h.malhas.FindContaining(ctx, domain.GeoMalhaMunicipio, lng, lat)
+	if err == nil {
+		var municipio *domain.Municipio
+		municipio, err = h.municipios.FindByID(ctx, codigo)
+		if err == nil {
+			c.JSON(http.StatusOK, SuccessResponse{
+				Success: true,
+				Code:    "OK",
+				Data:    municipio,
+				Meta: gin.H{
+					"lat": lat,
+					"lng": lng,
+				},
+			})
+			return
+		}
+	}
+
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Type:     "https://retech-core/errors/not-found",
+			Title:    "Not Found",
+			Status:   http.StatusNotFound,
+			Detail:   "Nenhum município contém a coordenada informada",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Type:     "https://retech-core/errors/database-error",
+		Title:    "Database Error",
+		Status:   http.StatusInternalServerError,
+		Detail:   "Erro ao buscar município pela coordenada",
+		Instance: c.Request.URL.Path,
+	})
 }
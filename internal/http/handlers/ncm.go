@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/cache"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+	"github.com/theretech/retech-core/internal/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type NCMHandler struct {
+	ncm          *storage.NCMRepo
+	activityRepo *storage.ActivityLogsRepo
+	redis        interface{} // interface{} para permitir nil (graceful degradation)
+}
+
+func NewNCMHandler(ncm *storage.NCMRepo, activityRepo *storage.ActivityLogsRepo, redis interface{}) *NCMHandler {
+	return &NCMHandler{
+		ncm:          ncm,
+		activityRepo: activityRepo,
+		redis:        redis,
+	}
+}
+
+// GetNCM retorna um código NCM com hierarquia, filhos diretos e CESTs aplicáveis
+// GET /ncm/:codigo
+// Aceita código com ou sem formatação: "84713012", "8471.30.12", "8471"
+func (h *NCMHandler) GetNCM(c *gin.Context) {
+	ctx := c.Request.Context()
+	codigo := domain.NormalizeNCM(c.Param("codigo"))
+
+	if domain.NCMNivelPorTamanho(codigo) == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Código NCM deve ter 2, 4, 5, 6, 7 ou 8 dígitos",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// ✅ VERIFICAR REDIS
+	redisKey := fmt.Sprintf("ncm:codigo:%s", codigo)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, redisKey)
+			if err == nil && cachedJSON != "" {
+				c.Header("Content-Type", "application/json")
+				c.String(http.StatusOK, cachedJSON)
+				return
+			}
+		}
+	}
+
+	ncm, err := h.ncm.FindByCodigo(ctx, codigo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Type:     "https://retech-core/errors/not-found",
+				Title:    "NCM Not Found",
+				Status:   http.StatusNotFound,
+				Detail:   fmt.Sprintf("NCM %s não encontrado", domain.FormatNCM(codigo)),
+				Instance: c.Request.URL.Path,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/internal-error",
+			Title:    "Internal Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar NCM",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	filhos, err := h.ncm.FindFilhos(ctx, codigo)
+	if err != nil {
+		filhos = []domain.NCM{}
+	}
+
+	cests, err := h.ncm.FindCESTByNCM(ctx, codigo)
+	if err != nil {
+		cests = []domain.CEST{}
+	}
+
+	response := SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data: gin.H{
+			"ncm":    ncm,
+			"filhos": filhos,
+			"cest":   cests,
+		},
+	}
+
+	// ✅ SALVAR NO REDIS (cache longo, dados fixos - invalidado na reimportação)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			if err := redisClient.Set(ctx, redisKey, response, 24*time.Hour); err != nil {
+				fmt.Printf("⚠️ Erro ao salvar no Redis: %v\n", err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SearchNCM busca NCMs por descrição ou código (sem acentos, todos os termos devem aparecer)
+// GET /ncm/buscar?q=smartphone&nivel=subitem&limit=20&page=1
+func (h *NCMHandler) SearchNCM(c *gin.Context) {
+	ctx := c.Request.Context()
+	query := domain.NormalizeSearchText(c.Query("q"))
+	nivel := c.Query("nivel")
+
+	if len(query) < 2 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Parâmetro 'q' deve ter ao menos 2 caracteres",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	page, _ := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	if page < 1 {
+		page = 1
+	}
+
+	// ✅ VERIFICAR REDIS
+	redisKey := fmt.Sprintf("ncm:buscar:%s:%s:%d:%d", query, nivel, limit, page)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, redisKey)
+			if err == nil && cachedJSON != "" {
+				c.Header("Content-Type", "application/json")
+				c.String(http.StatusOK, cachedJSON)
+				return
+			}
+		}
+	}
+
+	ncms, total, err := h.ncm.Search(ctx, strings.Fields(query), nivel, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Type:     "https://retech-core/errors/internal-error",
+			Title:    "Internal Error",
+			Status:   http.StatusInternalServerError,
+			Detail:   "Erro ao buscar NCMs",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	response := SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    ncms,
+		Meta: gin.H{
+			"total": total,
+			"page":  page,
+			"limit": limit,
+			"query": query,
+		},
+	}
+
+	// ✅ SALVAR NO REDIS (TTL curto: buscas variam muito)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			if err := redisClient.Set(ctx, redisKey, response, 1*time.Hour); err != nil {
+				fmt.Printf("⚠️ Erro ao salvar no Redis: %v\n", err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Reimport reimporta a tabela NCM a partir do JSON do Portal Único Siscomex
+// POST /admin/ncm/reimport (body = arquivo "Tabela NCM vigente" em JSON)
+//
+// Códigos ausentes no arquivo são removidos. O cache Redis de NCM é invalidado.
+func (h *NCMHandler) Reimport(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": "Envie o JSON da tabela NCM do Siscomex no corpo da requisição",
+		})
+		return
+	}
+
+	ncms, err := domain.ParseNCMSiscomex(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+
+	inserted, updated, removed, err := h.ncm.UpsertMany(ctx, ncms)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/database-error",
+			"title":  "Database Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao importar tabela NCM",
+		})
+		return
+	}
+
+	// 🗑️ Invalidar cache
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			if err := redisClient.FlushPattern(ctx, "ncm:*"); err != nil {
+				fmt.Printf("⚠️ Erro ao limpar cache NCM no Redis: %v\n", err)
+			}
+		}
+	}
+
+	utils.LogActivity(
+		c,
+		h.activityRepo,
+		domain.ActivityTypeNCMReimported,
+		domain.ActionUpdate,
+		utils.BuildActorFromContext(c),
+		domain.Resource{
+			Type: domain.ResourceTypeSystem,
+			ID:   "ncm",
+			Name: "Tabela NCM",
+		},
+		map[string]interface{}{
+			"total":    len(ncms),
+			"inserted": inserted,
+			"updated":  updated,
+			"removed":  removed,
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Tabela NCM reimportada com sucesso",
+		"total":    len(ncms),
+		"inserted": inserted,
+		"updated":  updated,
+		"removed":  removed,
+	})
+}
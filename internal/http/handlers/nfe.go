@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/cache"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type NFeHandler struct {
+	db      *storage.Mongo
+	estados *storage.EstadosRepo
+	redis   interface{} // interface{} para permitir nil (graceful degradation)
+}
+
+func NewNFeHandler(db *storage.Mongo, estados *storage.EstadosRepo, redis interface{}) *NFeHandler {
+	return &NFeHandler{
+		db:      db,
+		estados: estados,
+		redis:   redis,
+	}
+}
+
+// GetChave valida e decodifica uma chave de acesso (NF-e, NFC-e, CT-e, MDF-e)
+// GET /nfe/chave/:chave
+// GET /nfe/chave/:chave?enriquecer=true (adiciona dados do emitente se o CNPJ estiver no cache)
+//
+// 100% offline: não consulta a SEFAZ. Serve para triagem antes da consulta de situação.
+func (h *NFeHandler) GetChave(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	chave, err := domain.ParseChaveAcesso(c.Param("chave"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Chave de Acesso Inválida",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// UF do emitente (cUF = código IBGE do estado)
+	var uf *domain.Estado
+	if estado, err := h.estados.FindByID(ctx, chave.CodigoUF); err == nil {
+		uf = estado
+	}
+
+	avisos := []string{}
+	if uf == nil {
+		avisos = append(avisos, fmt.Sprintf("Código de UF %02d não corresponde a nenhum estado", chave.CodigoUF))
+	}
+	if !chave.CNPJValido {
+		avisos = append(avisos, "CNPJ do emitente inválido (pode ser emitente pessoa física com CPF)")
+	}
+	if _, ok := domain.ModelosDocumentoFiscal[chave.Modelo]; !ok {
+		avisos = append(avisos, fmt.Sprintf("Modelo %s não reconhecido", chave.Modelo))
+	}
+
+	data := gin.H{
+		"chave":  chave,
+		"uf":     uf,
+		"avisos": avisos,
+	}
+
+	// 🔎 Enriquecimento opcional (apenas cache: Redis → MongoDB, sem chamar APIs externas)
+	if c.Query("enriquecer") == "true" && chave.CNPJValido {
+		if emitente := h.findCachedCNPJ(c, chave.CNPJEmitente); emitente != nil {
+			data["emitente"] = emitente
+		}
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    data,
+	})
+}
+
+// findCachedCNPJ busca o CNPJ nas camadas de cache da API de CNPJ (sem fallback externo)
+func (h *NFeHandler) findCachedCNPJ(c *gin.Context, cnpj string) *domain.CNPJ {
+	ctx := c.Request.Context()
+
+	// ⚡ CAMADA 1: REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, fmt.Sprintf("cnpj:%s", cnpj))
+			if err == nil && cachedJSON != "" {
+				var cached domain.CNPJ
+				if json.Unmarshal([]byte(cachedJSON), &cached) == nil {
+					cached.Source = "redis-cache"
+					return &cached
+				}
+			}
+		}
+	}
+
+	// 🗄️ CAMADA 2: MONGODB
+	var cached domain.CNPJ
+	err := h.db.DB.Collection("cnpj_cache").FindOne(ctx, bson.M{"cnpj": cnpj}).Decode(&cached)
+	if err != nil {
+		return nil
+	}
+	cached.Source = "mongodb-cache"
+	return &cached
+}
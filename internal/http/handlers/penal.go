@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,9 +23,27 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// penalIndexCheckInterval intervalo entre verificações de alteração na collection (reconstrói os índices se mudou)
+const penalIndexCheckInterval = 1 * time.Minute
+
+var errPenalIndexIndisponivel = errors.New("índices penais indisponíveis")
+
+// penalIndexes índices em memória gerados a partir do mesmo snapshot da collection
+type penalIndexes struct {
+	search       *domain.PenalSearchIndex
+	autocomplete *domain.PenalAutocompleteIndex
+	signature    string // Quantidade + último updatedAt dos artigos indexados
+}
+
 type PenalHandler struct {
 	db    *storage.Mongo
 	redis interface{} // interface{} para permitir nil (graceful degradation)
+
+	// Índices em memória (busca BM25 e autocomplete): verificados/reconstruídos em background
+	// e trocados atomicamente, sem bloquear as requisições
+	indexes        atomic.Pointer[penalIndexes]
+	refreshMu      sync.Mutex // Serializa verificações e reconstruções
+	indexCheckedAt time.Time  // Última verificação, com ou sem sucesso (protegido por refreshMu)
 }
 
 func NewPenalHandler(db *storage.Mongo, redis interface{}) *PenalHandler {
@@ -189,8 +213,25 @@ func (h *PenalHandler) GetArtigo(c *gin.Context) {
 	codigo = strings.TrimSpace(codigo)
 	codigoNormalizado := strings.ToLower(codigo)
 
+	// 📅 Redação vigente em uma data (ex: data do fato) - YYYY-MM-DD
+	data := strings.TrimSpace(c.Query("data"))
+	if data != "" {
+		if _, err := time.Parse(domain.DataLayout, data); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"type":   "https://retech-core/errors/validation-error",
+				"title":  "Validation Error",
+				"status": http.StatusBadRequest,
+				"detail": "Parâmetro 'data' deve estar no formato YYYY-MM-DD",
+			})
+			return
+		}
+	}
+
 	// Criar chave de cache
 	cacheKey := fmt.Sprintf("penal:artigo:%s", codigoNormalizado)
+	if data != "" {
+		cacheKey += ":" + data
+	}
 
 	// ⚡ CACHE REDIS
 	if h.redis != nil {
@@ -308,6 +349,59 @@ func (h *PenalHandler) GetArtigo(c *gin.Context) {
 		}
 	}
 
+	if data != "" {
+		versao, err := h.versaoVigente(ctx, artigo.IdUnico, data)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"type":   "https://retech-core/errors/database-error",
+				"title":  "Database Error",
+				"status": http.StatusInternalServerError,
+				"detail": "Erro ao buscar versões do artigo",
+			})
+			return
+		}
+		if versao == nil {
+			c.JSON(http.StatusNotFound, gin.H{
+				"type":   "https://retech-core/errors/not-found",
+				"title":  "Artigo Not In Force",
+				"status": http.StatusNotFound,
+				"detail": fmt.Sprintf("Não há redação de %s vigente em %s", artigo.IdUnico, data),
+			})
+			return
+		}
+
+		// Redação da época no lugar da atual
+		artigo.Descricao = versao.Descricao
+		artigo.TextoCompleto = versao.TextoCompleto
+		artigo.PenaMin = versao.PenaMin
+		artigo.PenaMax = versao.PenaMax
+		pena := domain.ParsePena(versao.PenaMin, versao.PenaMax)
+		artigo.Pena = &pena
+		artigo.HashConteudo = versao.HashConteudo
+		artigo.VigenteDesde = versao.VigenteDesde
+		artigo.LeiAlteradora = versao.LeiAlteradora
+		artigo.Versao = versao.Versao
+
+		response := gin.H{
+			"success": true,
+			"code":    "OK",
+			"data":    artigo,
+			"meta": gin.H{
+				"data":         data,
+				"versao":       versao.Versao,
+				"vigenteDesde": versao.VigenteDesde,
+				"vigenteAte":   versao.VigenteAte,
+			},
+		}
+		if h.redis != nil {
+			if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+				redisClient.Set(ctx, cacheKey, response, 24*time.Hour)
+			}
+		}
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
 	response := gin.H{
 		"success": true,
 		"code":    "OK",
@@ -324,8 +418,11 @@ func (h *PenalHandler) GetArtigo(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// SearchArtigos busca artigos por texto (descrição)
-// GET /penal/search?q=texto
+// SearchArtigos busca artigos por relevância (BM25 sobre índice invertido em memória)
+// GET /penal/search?q=texto&legislacao=CP&tipo=crime&page=1&limit=20
+//
+// Busca sem acentos e com stemming ("homicidios" encontra "Homicídio"), termos incompletos
+// são expandidos por prefixo e os trechos retornam com <mark> nos termos encontrados.
 func (h *PenalHandler) SearchArtigos(c *gin.Context) {
 	ctx := c.Request.Context()
 	query := strings.TrimSpace(c.Query("q"))
@@ -340,9 +437,236 @@ func (h *PenalHandler) SearchArtigos(c *gin.Context) {
 		return
 	}
 
-	// Criar chave de cache
-	queryLower := strings.ToLower(query)
-	cacheKey := fmt.Sprintf("penal:search:%s", queryLower)
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if page < 1 {
+		page = 1
+	}
+
+	index, err := h.getSearchIndex(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/database-error",
+			"title":  "Database Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao carregar índice de busca",
+		})
+		return
+	}
+
+	// ⚡ Índice em memória (<1ms): sem cache Redis para não criar uma chave por consulta
+	results, total := index.Search(query, domain.PenalSearchOptions{
+		Legislacao: c.Query("legislacao"),
+		Tipo:       c.Query("tipo"),
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	})
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"code":    "OK",
+		"data":    results,
+		"meta": gin.H{
+			"total":      total,
+			"page":       page,
+			"limit":      limit,
+			"totalPages": (total + limit - 1) / limit,
+			"query":      query,
+		},
+	})
+}
+
+// StartIndexRefresher constrói os índices em memória e, a cada penalIndexCheckInterval, verifica
+// se a collection mudou e os reconstrói em background, até o contexto ser cancelado
+func (h *PenalHandler) StartIndexRefresher(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(penalIndexCheckInterval)
+		defer ticker.Stop()
+		for {
+			if _, err := h.refreshIndexes(ctx); err != nil {
+				fmt.Printf("⚠️ Erro ao construir índices penais: %v\n", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// getSearchIndex retorna o índice de busca atual
+func (h *PenalHandler) getSearchIndex(ctx context.Context) (*domain.PenalSearchIndex, error) {
+	indexes, err := h.getIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexes.search, nil
+}
+
+// getAutocompleteIndex retorna o índice de prefixos (mesmo ciclo de vida do índice de busca)
+func (h *PenalHandler) getAutocompleteIndex(ctx context.Context) (*domain.PenalAutocompleteIndex, error) {
+	indexes, err := h.getIndexes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return indexes.autocomplete, nil
+}
+
+// getIndexes retorna os índices atuais sem consultar o MongoDB. Só constrói na requisição se ainda
+// não houver índice (ex: a construção inicial falhou), no máximo uma tentativa por intervalo.
+func (h *PenalHandler) getIndexes(ctx context.Context) (*penalIndexes, error) {
+	if indexes := h.indexes.Load(); indexes != nil {
+		return indexes, nil
+	}
+
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+
+	// A construção em background pode ter terminado enquanto esperávamos o lock
+	if indexes := h.indexes.Load(); indexes != nil {
+		return indexes, nil
+	}
+	if !h.indexCheckedAt.IsZero() && time.Since(h.indexCheckedAt) < penalIndexCheckInterval {
+		return nil, errPenalIndexIndisponivel
+	}
+	return h.rebuildIndexes(ctx)
+}
+
+// refreshIndexes verifica se a collection mudou e, se mudou, reconstrói os índices
+func (h *PenalHandler) refreshIndexes(ctx context.Context) (*penalIndexes, error) {
+	h.refreshMu.Lock()
+	defer h.refreshMu.Unlock()
+	return h.rebuildIndexes(ctx)
+}
+
+// rebuildIndexes (com refreshMu) reconstrói os índices se a assinatura da collection mudou
+// e publica a nova versão com uma troca atômica
+func (h *PenalHandler) rebuildIndexes(ctx context.Context) (*penalIndexes, error) {
+	current := h.indexes.Load()
+
+	collection := h.db.DB.Collection("penal_artigos")
+	signature, err := penalIndexSignature(ctx, collection)
+	if err != nil {
+		return h.fallbackIndexes(current, err)
+	}
+	if current != nil && signature == current.signature {
+		h.indexCheckedAt = time.Now()
+		return current, nil
+	}
+
+	cursor, err := collection.Find(ctx, bson.M{})
+	if err != nil {
+		return h.fallbackIndexes(current, err)
+	}
+	defer cursor.Close(ctx)
+
+	var artigos []domain.ArtigoPenal
+	if err := cursor.All(ctx, &artigos); err != nil {
+		return h.fallbackIndexes(current, err)
+	}
+
+	next := &penalIndexes{
+		search:       domain.NewPenalSearchIndex(artigos),
+		autocomplete: domain.NewPenalAutocompleteIndex(artigos),
+		signature:    signature,
+	}
+	h.indexes.Store(next)
+	h.indexCheckedAt = time.Now()
+	return next, nil
+}
+
+// penalIndexSignature identifica o estado da collection (seed/importação alteram quantidade ou updatedAt)
+func penalIndexSignature(ctx context.Context, collection *mongo.Collection) (string, error) {
+	count, err := collection.CountDocuments(ctx, bson.M{})
+	if err != nil {
+		return "", err
+	}
+	var last struct {
+		UpdatedAt time.Time `bson:"updatedAt"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "updatedAt", Value: -1}}).SetProjection(bson.M{"updatedAt": 1})
+	if err := collection.FindOne(ctx, bson.M{}, opts).Decode(&last); err != nil && err != mongo.ErrNoDocuments {
+		return "", err
+	}
+	return fmt.Sprintf("%d:%d", count, last.UpdatedAt.UnixNano()), nil
+}
+
+// Autocomplete sugestões para digitação (typeahead) por código ou descrição
+// GET /penal/autocomplete?prefix=121 §2&legislacao=CP&limit=10
+//
+// Índice de prefixos em memória: sem consulta ao MongoDB nem chaves no Redis por prefixo digitado.
+func (h *PenalHandler) Autocomplete(c *gin.Context) {
+	ctx := c.Request.Context()
+	prefix := strings.TrimSpace(c.Query("prefix"))
+
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation",
+			"title":  "Invalid Query",
+			"status": http.StatusBadRequest,
+			"detail": "Parâmetro 'prefix' é obrigatório",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	index, err := h.getAutocompleteIndex(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/database-error",
+			"title":  "Database Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao carregar índice de autocomplete",
+		})
+		return
+	}
+
+	items := index.Suggest(prefix, c.Query("legislacao"), limit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"code":    "OK",
+		"data":    items,
+		"meta": gin.H{
+			"prefix": prefix,
+			"total":  len(items),
+		},
+	})
+}
+
+// fallbackIndexes mantém os índices antigos (se houver) quando a reconstrução falha; a falha
+// também conta como verificação, para a próxima tentativa esperar o intervalo
+func (h *PenalHandler) fallbackIndexes(current *penalIndexes, err error) (*penalIndexes, error) {
+	h.indexCheckedAt = time.Now()
+	if current != nil {
+		fmt.Printf("⚠️ Erro ao atualizar índices penais (mantendo os anteriores): %v\n", err)
+		return current, nil
+	}
+	return nil, err
+}
+
+// GetArvore retorna o artigo como árvore (caput → parágrafos → incisos → alíneas)
+// GET /penal/artigos/:idUnico/arvore
+// Aceita qualquer dispositivo do artigo ("CP:121" ou "CP:121.2"); a árvore parte sempre do caput.
+func (h *PenalHandler) GetArvore(c *gin.Context) {
+	ctx := c.Request.Context()
+	idUnico := strings.TrimSpace(c.Param("codigo"))
+
+	legCode, codigo := "CP", idUnico
+	if parts := strings.SplitN(idUnico, ":", 2); len(parts) == 2 {
+		legCode, codigo = strings.ToUpper(parts[0]), parts[1]
+	}
+	base := domain.PenalArtigoBase(codigo)
+
+	cacheKey := fmt.Sprintf("penal:arvore:%s:%s", legCode, base)
 
 	// ⚡ CACHE REDIS
 	if h.redis != nil {
@@ -356,70 +680,341 @@ func (h *PenalHandler) SearchArtigos(c *gin.Context) {
 		}
 	}
 
-	// 🗄️ BUSCAR DO MONGODB
-	collection := h.db.DB.Collection("penal_artigos")
+	// 🗄️ Árvore gravada no seed (penal_arvores)
+	var arvore domain.PenalArvore
+	err := h.db.DB.Collection("penal_arvores").FindOne(ctx, bson.M{"idUnico": legCode + ":" + base}).Decode(&arvore)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "Artigo Not Found",
+			"status": http.StatusNotFound,
+			"detail": fmt.Sprintf("Artigo %s não encontrado. Use o formato 'CODIGO:ARTIGO' (ex: 'CP:121')", idUnico),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/database-error",
+			"title":  "Database Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao buscar artigo",
+		})
+		return
+	}
 
-	// Busca em múltiplos campos
-	filter := bson.M{
-		"$or": []bson.M{
-			{"descricao": bson.M{"$regex": query, "$options": "i"}},
-			{"textoCompleto": bson.M{"$regex": query, "$options": "i"}},
-			{"busca": bson.M{"$regex": queryLower, "$options": "i"}},
-		},
+	response := gin.H{
+		"success": true,
+		"code":    "OK",
+		"data":    arvore,
 	}
 
-	findOptions := options.Find().
-		SetSort(bson.D{{Key: "artigo", Value: 1}}).
-		SetLimit(50)
+	// ⚡ Salvar no Redis (dados fixos)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			redisClient.Set(ctx, cacheKey, response, 24*time.Hour)
+		}
+	}
 
-	cursor, err := collection.Find(ctx, filter, findOptions)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEstrutura retorna o sumário de uma legislação (partes, títulos, capítulos e artigos)
+// GET /penal/legislacoes/:leg/estrutura
+// :leg = código curto ("CP", "LCP", "DRG", "CTB", ...)
+func (h *PenalHandler) GetEstrutura(c *gin.Context) {
+	ctx := c.Request.Context()
+	leg := strings.ToUpper(strings.TrimSpace(c.Param("leg")))
+
+	cacheKey := fmt.Sprintf("penal:estrutura:%s", leg)
+
+	// ⚡ CACHE REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, cacheKey)
+			if err == nil && cachedJSON != "" {
+				c.Header("Content-Type", "application/json")
+				c.String(http.StatusOK, cachedJSON)
+				return
+			}
+		}
+	}
+
+	var estrutura domain.PenalEstrutura
+	err := h.db.DB.Collection("penal_estrutura").FindOne(ctx, bson.M{"codigo": leg}).Decode(&estrutura)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{
+				"type":   "https://retech-core/errors/not-found",
+				"title":  "Legislação Not Found",
+				"status": http.StatusNotFound,
+				"detail": fmt.Sprintf("Legislação %s não encontrada. Use o código curto (ex: 'CP', 'LCP', 'DRG')", leg),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"type":   "https://retech-core/errors/database-error",
 			"title":  "Database Error",
 			"status": http.StatusInternalServerError,
-			"detail": "Erro ao buscar artigos",
+			"detail": "Erro ao buscar estrutura da legislação",
 		})
 		return
 	}
-	defer cursor.Close(ctx)
 
-	var artigos []domain.ArtigoPenal
-	if err := cursor.All(ctx, &artigos); err != nil {
+	response := gin.H{
+		"success": true,
+		"code":    "OK",
+		"data":    estrutura,
+	}
+
+	// ⚡ Salvar no Redis (dados fixos)
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			redisClient.Set(ctx, cacheKey, response, 24*time.Hour)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetVersoes lista as redações de um dispositivo com o período de vigência de cada uma
+// GET /penal/artigos/:idUnico/versoes
+func (h *PenalHandler) GetVersoes(c *gin.Context) {
+	ctx := c.Request.Context()
+	idUnico := strings.TrimSpace(c.Param("codigo"))
+
+	versoes, err := h.findVersoes(ctx, idUnico)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"type":   "https://retech-core/errors/database-error",
 			"title":  "Database Error",
 			"status": http.StatusInternalServerError,
-			"detail": "Erro ao processar resultados",
+			"detail": "Erro ao buscar versões do artigo",
+		})
+		return
+	}
+	if len(versoes) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "Artigo Not Found",
+			"status": http.StatusNotFound,
+			"detail": fmt.Sprintf("Nenhuma versão encontrada para %s. Use o formato 'CODIGO:ARTIGO' (ex: 'CP:213')", idUnico),
 		})
 		return
 	}
 
-	// Converter para formato de resposta
-	results := make([]domain.PenalResponse, 0, len(artigos))
-	for _, artigo := range artigos {
-		results = append(results, domain.PenalResponse{
-			Codigo:          artigo.Codigo,
-			CodigoFormatado: artigo.CodigoFormatado,
-			Descricao:       artigo.Descricao,
-			Tipo:            artigo.Tipo,
-			Legislacao:      artigo.Legislacao,
-			LegislacaoNome:  artigo.LegislacaoNome,
-			IdUnico:         artigo.IdUnico,
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"code":    "OK",
+		"data":    versoes,
+		"meta": gin.H{
+			"idUnico": idUnico,
+			"total":   len(versoes),
+		},
+	})
+}
+
+// GetDiffVersoes compara duas redações de um dispositivo (padrão: penúltima → atual)
+// GET /penal/artigos/:idUnico/diff?de=1&para=2
+func (h *PenalHandler) GetDiffVersoes(c *gin.Context) {
+	ctx := c.Request.Context()
+	idUnico := strings.TrimSpace(c.Param("codigo"))
+
+	versoes, err := h.findVersoes(ctx, idUnico)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/database-error",
+			"title":  "Database Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao buscar versões do artigo",
+		})
+		return
+	}
+	if len(versoes) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "Artigo Not Found",
+			"status": http.StatusNotFound,
+			"detail": fmt.Sprintf("Nenhuma versão encontrada para %s. Use o formato 'CODIGO:ARTIGO' (ex: 'CP:213')", idUnico),
 		})
+		return
+	}
+
+	ultima := versoes[len(versoes)-1].Versao
+	de, errDe := strconv.Atoi(c.DefaultQuery("de", strconv.Itoa(ultima-1)))
+	para, errPara := strconv.Atoi(c.DefaultQuery("para", strconv.Itoa(ultima)))
+	versaoDe, versaoPara := findVersao(versoes, de), findVersao(versoes, para)
+	if errDe != nil || errPara != nil || versaoDe == nil || versaoPara == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": fmt.Sprintf("Parâmetros 'de' e 'para' devem ser versões existentes de %s (1 a %d)", idUnico, ultima),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"code":    "OK",
+		"data":    domain.DiffVersoes(*versaoDe, *versaoPara),
+	})
+}
+
+// findVersoes retorna as versões do dispositivo em ordem (1 = mais antiga)
+func (h *PenalHandler) findVersoes(ctx context.Context, idUnico string) ([]domain.ArtigoPenalVersao, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "versao", Value: 1}})
+	cursor, err := h.db.DB.Collection("penal_artigos_versoes").Find(ctx, bson.M{"idUnico": idUnico}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	versoes := []domain.ArtigoPenalVersao{}
+	if err := cursor.All(ctx, &versoes); err != nil {
+		return nil, err
+	}
+	return versoes, nil
+}
+
+// versaoVigente retorna a versão em vigor na data (nil se o dispositivo não existia).
+// Artigos sem histórico registrado valem desde sempre na redação atual.
+func (h *PenalHandler) versaoVigente(ctx context.Context, idUnico, data string) (*domain.ArtigoPenalVersao, error) {
+	versoes, err := h.findVersoes(ctx, idUnico)
+	if err != nil {
+		return nil, err
+	}
+	if len(versoes) == 0 {
+		var artigo domain.ArtigoPenal
+		if err := h.db.DB.Collection("penal_artigos").FindOne(ctx, bson.M{"idUnico": idUnico}).Decode(&artigo); err != nil {
+			return nil, err
+		}
+		versao := domain.NewVersaoFromArtigo(artigo)
+		versao.Versao = 1
+		versoes = append(versoes, versao)
+	}
+	return domain.VersaoVigenteEm(versoes, data), nil
+}
+
+func findVersao(versoes []domain.ArtigoPenalVersao, numero int) *domain.ArtigoPenalVersao {
+	for i := range versoes {
+		if versoes[i].Versao == numero {
+			return &versoes[i]
+		}
+	}
+	return nil
+}
+
+// GetReferencias retorna o grafo de citações do dispositivo (e dos seus parágrafos/incisos)
+// GET /penal/artigos/:idUnico/referencias
+// saidas = dispositivos citados no texto; entradas = dispositivos que o citam
+func (h *PenalHandler) GetReferencias(c *gin.Context) {
+	ctx := c.Request.Context()
+	idUnico := strings.TrimSpace(c.Param("codigo"))
+	if !strings.Contains(idUnico, ":") {
+		idUnico = "CP:" + idUnico
+	}
+
+	cacheKey := fmt.Sprintf("penal:referencias:%s", idUnico)
+
+	// ⚡ CACHE REDIS
+	if h.redis != nil {
+		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
+			cachedJSON, err := redisClient.Get(ctx, cacheKey)
+			if err == nil && cachedJSON != "" {
+				c.Header("Content-Type", "application/json")
+				c.String(http.StatusOK, cachedJSON)
+				return
+			}
+		}
+	}
+
+	// 🗄️ "CP:121" inclui as citações de/para "CP:121.*"
+	escopo := bson.M{"$regex": "^" + regexp.QuoteMeta(idUnico) + `(\.|$)`}
+	artigosColl := h.db.DB.Collection("penal_artigos")
+	if count, err := artigosColl.CountDocuments(ctx, bson.M{"idUnico": escopo}); err != nil || count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "Artigo Not Found",
+			"status": http.StatusNotFound,
+			"detail": fmt.Sprintf("Artigo %s não encontrado. Use o formato 'CODIGO:ARTIGO' (ex: 'DRG:35')", idUnico),
+		})
+		return
+	}
+
+	refsColl := h.db.DB.Collection("penal_referencias")
+	var saidas, entradas []domain.PenalReferencia
+	for _, consulta := range []struct {
+		campo   string
+		destino *[]domain.PenalReferencia
+	}{{"origem", &saidas}, {"destino", &entradas}} {
+		cursor, err := refsColl.Find(ctx, bson.M{consulta.campo: escopo}, options.Find().SetSort(bson.D{{Key: "origem", Value: 1}, {Key: "citado", Value: 1}}))
+		if err == nil {
+			err = cursor.All(ctx, consulta.destino)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"type":   "https://retech-core/errors/database-error",
+				"title":  "Database Error",
+				"status": http.StatusInternalServerError,
+				"detail": "Erro ao buscar referências do artigo",
+			})
+			return
+		}
+	}
+
+	// Resumo dos dispositivos da outra ponta (uma única consulta)
+	ids := []string{}
+	for _, ref := range saidas {
+		ids = append(ids, ref.Destino)
+	}
+	for _, ref := range entradas {
+		ids = append(ids, ref.Origem)
+	}
+	resumos := map[string]*domain.PenalResponse{}
+	if len(ids) > 0 {
+		cursor, err := artigosColl.Find(ctx, bson.M{"idUnico": bson.M{"$in": ids}})
+		if err == nil {
+			var artigos []domain.ArtigoPenal
+			if cursor.All(ctx, &artigos) == nil {
+				for _, artigo := range artigos {
+					resumos[artigo.IdUnico] = &domain.PenalResponse{
+						Codigo:          artigo.Codigo,
+						CodigoFormatado: artigo.CodigoFormatado,
+						Descricao:       artigo.Descricao,
+						Tipo:            artigo.Tipo,
+						Legislacao:      artigo.Legislacao,
+						LegislacaoNome:  artigo.LegislacaoNome,
+						IdUnico:         artigo.IdUnico,
+					}
+				}
+			}
+		}
+	}
+
+	itensSaida := make([]domain.PenalReferenciaItem, 0, len(saidas))
+	for _, ref := range saidas {
+		itensSaida = append(itensSaida, domain.PenalReferenciaItem{PenalReferencia: ref, Artigo: resumos[ref.Destino]})
+	}
+	itensEntrada := make([]domain.PenalReferenciaItem, 0, len(entradas))
+	for _, ref := range entradas {
+		itensEntrada = append(itensEntrada, domain.PenalReferenciaItem{PenalReferencia: ref, Artigo: resumos[ref.Origem]})
 	}
 
 	response := gin.H{
 		"success": true,
 		"code":    "OK",
-		"data":    results,
+		"data": gin.H{
+			"saidas":   itensSaida,
+			"entradas": itensEntrada,
+		},
 		"meta": gin.H{
-			"total": len(results),
-			"query": query,
+			"idUnico":       idUnico,
+			"totalSaidas":   len(itensSaida),
+			"totalEntradas": len(itensEntrada),
 		},
 	}
 
-	// ⚡ Salvar no Redis (cache 24h para buscas)
+	// ⚡ Salvar no Redis (grafo só muda no seed)
 	if h.redis != nil {
 		if redisClient, ok := h.redis.(*cache.RedisClient); ok {
 			redisClient.Set(ctx, cacheKey, response, 24*time.Hour)
@@ -429,6 +1024,194 @@ func (h *PenalHandler) SearchArtigos(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// DosimetriaRequest corpo do cálculo de dosimetria
+type DosimetriaRequest struct {
+	IdUnico              string                `json:"idUnico"`              // Artigo com a pena base (ex: "CP:157")
+	PenaMin              string                `json:"penaMin"`              // Alternativa ao idUnico: "Reclusão, de 4 a 10 anos"
+	PenaMax              string                `json:"penaMax"`              // "e multa"
+	Qualificadora        string                `json:"qualificadora"`        // idUnico da qualificadora (substitui a pena base)
+	Majorantes           []DosimetriaCausaBody `json:"majorantes"`           // Causas de aumento
+	Minorantes           []DosimetriaCausaBody `json:"minorantes"`           // Causas de diminuição
+	ReducaoPrescricional bool                  `json:"reducaoPrescricional"` // Art. 115 CP (menor de 21 / maior de 70)
+	DataFato             string                `json:"dataFato"`             // YYYY-MM-DD: usa a redação vigente na data do fato
+}
+
+// DosimetriaCausaBody causa de aumento/diminuição informada pelo cliente
+type DosimetriaCausaBody struct {
+	Descricao string `json:"descricao"`
+	Fracao    string `json:"fracao"` // "1/3", "de 1/3 até metade", "de um sexto a dois terços"
+}
+
+// Dosimetria calcula a faixa de pena após qualificadora, majorantes e minorantes, com prescrição (art. 109 CP)
+// POST /penal/dosimetria
+// A pena base vem da qualificadora (se informada), do artigo (idUnico) ou do texto penaMin/penaMax.
+func (h *PenalHandler) Dosimetria(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req DosimetriaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": "JSON inválido: " + err.Error(),
+		})
+		return
+	}
+
+	if req.IdUnico == "" && req.Qualificadora == "" && req.PenaMin == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": "Informe 'idUnico', 'qualificadora' ou 'penaMin'",
+		})
+		return
+	}
+
+	if req.DataFato != "" {
+		if _, err := time.Parse(domain.DataLayout, req.DataFato); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"type":   "https://retech-core/errors/validation-error",
+				"title":  "Validation Error",
+				"status": http.StatusBadRequest,
+				"detail": "Campo 'dataFato' deve estar no formato YYYY-MM-DD",
+			})
+			return
+		}
+	}
+
+	var artigo, qualificadora *domain.ArtigoPenal
+	pena := domain.ParsePena(req.PenaMin, req.PenaMax)
+	if req.IdUnico != "" {
+		if artigo = h.findArtigoDosimetria(ctx, c, req.IdUnico, req.DataFato); artigo == nil {
+			return
+		}
+		pena = *artigo.Pena
+	}
+	if req.Qualificadora != "" {
+		if qualificadora = h.findArtigoDosimetria(ctx, c, req.Qualificadora, req.DataFato); qualificadora == nil {
+			return
+		}
+		pena = *qualificadora.Pena
+	}
+
+	majorantes, err := parseCausasPena(req.Majorantes)
+	if err != nil {
+		dosimetriaValidationError(c, err)
+		return
+	}
+	minorantes, err := parseCausasPena(req.Minorantes)
+	if err != nil {
+		dosimetriaValidationError(c, err)
+		return
+	}
+
+	resultado, err := domain.CalcularDosimetria(domain.DosimetriaInput{
+		Pena:                 pena,
+		Majorantes:           majorantes,
+		Minorantes:           minorantes,
+		ReducaoPrescricional: req.ReducaoPrescricional,
+	})
+	if err != nil {
+		dosimetriaValidationError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"code":    "OK",
+		"data": gin.H{
+			"artigo":        artigo,
+			"qualificadora": qualificadora,
+			"resultado":     resultado,
+		},
+	})
+}
+
+// findArtigoDosimetria busca o artigo por idUnico (na redação vigente em dataFato, se informada) e garante
+// a pena estruturada (artigos gravados antes da migration 008 ainda não têm o campo).
+// Responde 404/500 e retorna nil em caso de erro.
+func (h *PenalHandler) findArtigoDosimetria(ctx context.Context, c *gin.Context, idUnico, dataFato string) *domain.ArtigoPenal {
+	var artigo domain.ArtigoPenal
+	err := h.db.DB.Collection("penal_artigos").FindOne(ctx, bson.M{"idUnico": strings.TrimSpace(idUnico)}).Decode(&artigo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{
+				"type":   "https://retech-core/errors/not-found",
+				"title":  "Artigo Not Found",
+				"status": http.StatusNotFound,
+				"detail": fmt.Sprintf("Artigo %s não encontrado. Use o formato 'CODIGO:ARTIGO' (ex: 'CP:121')", idUnico),
+			})
+			return nil
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/database-error",
+			"title":  "Database Error",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao buscar artigo",
+		})
+		return nil
+	}
+
+	if dataFato != "" {
+		versao, err := h.versaoVigente(ctx, artigo.IdUnico, dataFato)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"type":   "https://retech-core/errors/database-error",
+				"title":  "Database Error",
+				"status": http.StatusInternalServerError,
+				"detail": "Erro ao buscar versões do artigo",
+			})
+			return nil
+		}
+		if versao == nil {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"type":   "https://retech-core/errors/validation-error",
+				"title":  "Artigo Not In Force",
+				"status": http.StatusUnprocessableEntity,
+				"detail": fmt.Sprintf("Não há redação de %s vigente em %s", artigo.IdUnico, dataFato),
+			})
+			return nil
+		}
+		artigo.Descricao, artigo.TextoCompleto = versao.Descricao, versao.TextoCompleto
+		artigo.PenaMin, artigo.PenaMax = versao.PenaMin, versao.PenaMax
+		artigo.VigenteDesde, artigo.LeiAlteradora, artigo.Versao = versao.VigenteDesde, versao.LeiAlteradora, versao.Versao
+		artigo.Pena = nil
+	}
+
+	if artigo.Pena == nil {
+		pena := domain.ParsePena(artigo.PenaMin, artigo.PenaMax)
+		artigo.Pena = &pena
+	}
+	return &artigo
+}
+
+func parseCausasPena(body []DosimetriaCausaBody) ([]domain.CausaPena, error) {
+	causas := make([]domain.CausaPena, 0, len(body))
+	for _, b := range body {
+		causa, err := domain.NewCausaPena(b.Descricao, b.Fracao)
+		if err != nil {
+			return nil, err
+		}
+		causas = append(causas, causa)
+	}
+	return causas, nil
+}
+
+func dosimetriaValidationError(c *gin.Context, err error) {
+	detail := err.Error()
+	if ve, ok := err.(*domain.ValidationError); ok {
+		detail = ve.Message
+	}
+	c.JSON(http.StatusUnprocessableEntity, gin.H{
+		"type":   "https://retech-core/errors/validation-error",
+		"title":  "Validation Error",
+		"status": http.StatusUnprocessableEntity,
+		"detail": detail,
+	})
+}
+
 // GetCacheStats retorna estatísticas do cache de Artigos Penais
 // GET /admin/cache/penal/stats
 func (h *PenalHandler) GetCacheStats(c *gin.Context) {
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/domain"
+)
+
+type PixHandler struct{}
+
+func NewPixHandler() *PixHandler {
+	return &PixHandler{}
+}
+
+// BRCodeDecodeRequest payload de decodificação de BR Code
+type BRCodeDecodeRequest struct {
+	Payload string `json:"payload" binding:"required"` // PIX "copia e cola"
+}
+
+// BRCodeEncodeRequest payload de geração de BR Code estático
+type BRCodeEncodeRequest struct {
+	Chave     string  `json:"chave" binding:"required"`
+	Valor     float64 `json:"valor"` // opcional (0 = pagador informa o valor)
+	Descricao string  `json:"descricao"`
+	Nome      string  `json:"nome" binding:"required"`   // Nome do recebedor (máx. 25, sem acentos)
+	Cidade    string  `json:"cidade" binding:"required"` // Cidade do recebedor (máx. 15, sem acentos)
+	CEP       string  `json:"cep"`
+	TxID      string  `json:"txid"` // opcional (padrão "***")
+}
+
+// DecodeBRCode valida o CRC e decodifica um BR Code (estático ou dinâmico)
+// POST /pix/brcode/decode
+func (h *PixHandler) DecodeBRCode(c *gin.Context) {
+	var req BRCodeDecodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Campo 'payload' é obrigatório",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	code, err := domain.DecodeBRCode(req.Payload)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "BR Code Inválido",
+			Status:   http.StatusUnprocessableEntity,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    code,
+	})
+}
+
+// EncodeBRCode gera um BR Code estático (PIX "copia e cola")
+// POST /pix/brcode/encode
+func (h *PixHandler) EncodeBRCode(c *gin.Context) {
+	var req BRCodeEncodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Validation Error",
+			Status:   http.StatusBadRequest,
+			Detail:   "Campos 'chave', 'nome' e 'cidade' são obrigatórios",
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	payload, err := domain.EncodeBRCode(domain.BRCodeInput{
+		Chave:     req.Chave,
+		Valor:     req.Valor,
+		Descricao: req.Descricao,
+		Nome:      req.Nome,
+		Cidade:    req.Cidade,
+		CEP:       req.CEP,
+		TxID:      req.TxID,
+	})
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Dados Inválidos",
+			Status:   http.StatusUnprocessableEntity,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	// Devolver também a versão decodificada (útil para conferência)
+	code, _ := domain.DecodeBRCode(payload)
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data: gin.H{
+			"payload": payload,
+			"brcode":  code,
+		},
+	})
+}
+
+// TipoChave classifica uma chave PIX (CPF, CNPJ, EMAIL, PHONE ou EVP)
+// GET /pix/chave/:chave/tipo
+//
+// Apenas validação de formato: não consulta o DICT.
+func (h *PixHandler) TipoChave(c *gin.Context) {
+	chave := domain.ClassifyPixKey(c.Param("chave"))
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data:    chave,
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/storage"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type PlacaHandler struct {
+	faixas   *storage.PlacasFaixasRepo
+	provider domain.VeiculoProvider // opcional (nil = apenas validação offline)
+}
+
+func NewPlacaHandler(faixas *storage.PlacasFaixasRepo, provider domain.VeiculoProvider) *PlacaHandler {
+	return &PlacaHandler{
+		faixas:   faixas,
+		provider: provider,
+	}
+}
+
+// GetPlaca valida uma placa, converte entre os formatos antigo/Mercosul e informa a UF de emissão
+// GET /placa/:placa
+// GET /placa/:placa?consultar=true (dados de registro, se houver provider configurado)
+//
+// A UF vem da faixa de séries do formato antigo. Para placas Mercosul convertidas,
+// a faixa da placa antiga equivalente é usada (a UF atual pode ser outra após transferência).
+func (h *PlacaHandler) GetPlaca(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	placa, err := domain.ParsePlaca(c.Param("placa"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Type:     "https://retech-core/errors/validation-error",
+			Title:    "Placa Inválida",
+			Status:   http.StatusBadRequest,
+			Detail:   err.Error(),
+			Instance: c.Request.URL.Path,
+		})
+		return
+	}
+
+	avisos := []string{}
+
+	if placa.PlacaAntiga != "" {
+		faixa, err := h.faixas.FindByPrefixo(ctx, placa.PlacaAntiga[:3])
+		switch {
+		case err == mongo.ErrNoDocuments:
+			avisos = append(avisos, "Série não encontrada na tabela de faixas por UF")
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Type:     "https://retech-core/errors/database-error",
+				Title:    "Database Error",
+				Status:   http.StatusInternalServerError,
+				Detail:   "Erro ao consultar a tabela de faixas por UF",
+				Instance: c.Request.URL.Path,
+			})
+			return
+		default:
+			placa.Faixa = faixa
+			if placa.Formato == domain.PlacaFormatoMercosul {
+				avisos = append(avisos, "UF estimada pela série da placa antiga equivalente; veículos emplacados já no padrão Mercosul podem ser de outra UF")
+			}
+		}
+	} else {
+		avisos = append(avisos, "Placa emitida no padrão Mercosul sem equivalente no formato antigo")
+	}
+
+	if c.Query("consultar") == "true" {
+		if h.provider == nil {
+			avisos = append(avisos, "Consulta de dados do veículo indisponível (nenhum provider configurado)")
+		} else if veiculo, err := h.provider.ConsultarPlaca(ctx, placa.PlacaMercosul); err == nil {
+			placa.Veiculo = veiculo
+		} else {
+			fmt.Printf("⚠️ Erro ao consultar placa em %s: %v\n", h.provider.Nome(), err)
+			avisos = append(avisos, "Consulta de dados do veículo indisponível no momento ("+h.provider.Nome()+")")
+		}
+	}
+
+	c.JSON(http.StatusOK, SuccessResponse{
+		Success: true,
+		Code:    "OK",
+		Data: gin.H{
+			"placa":  placa,
+			"avisos": avisos,
+		},
+	})
+}
@@ -17,22 +17,26 @@ import (
 	"github.com/theretech/retech-core/internal/auth"
 	"github.com/theretech/retech-core/internal/domain"
 	"github.com/theretech/retech-core/internal/storage"
+	"github.com/theretech/retech-core/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type TenantHandler struct {
-	apikeys *storage.APIKeysRepo
-	users   *storage.UsersRepo
-	tenants *storage.TenantsRepo
-	db      *storage.Mongo
+	apikeys      *storage.APIKeysRepo
+	users        *storage.UsersRepo
+	tenants      *storage.TenantsRepo
+	activityLogs *storage.ActivityLogsRepo
+	db           *storage.Mongo
 }
 
-func NewTenantHandler(apikeys *storage.APIKeysRepo, users *storage.UsersRepo, tenants *storage.TenantsRepo, m *storage.Mongo) *TenantHandler {
+func NewTenantHandler(apikeys *storage.APIKeysRepo, users *storage.UsersRepo, tenants *storage.TenantsRepo, activityLogs *storage.ActivityLogsRepo, m *storage.Mongo) *TenantHandler {
 	return &TenantHandler{
-		apikeys: apikeys,
-		users:   users,
-		tenants: tenants,
-		db:      m,
+		apikeys:      apikeys,
+		users:        users,
+		tenants:      tenants,
+		activityLogs: activityLogs,
+		db:           m,
 	}
 }
 
@@ -75,6 +79,17 @@ func (h *TenantHandler) ListMyAPIKeys(c *gin.Context) {
 		return
 	}
 
+	// ⏰ Situação de cada key em relação à validade (active, expiring, grace, expired, revoked)
+	policy := domain.APIKeyExpiryPolicyFromEnv()
+	now := time.Now().UTC()
+	for _, k := range keys {
+		key := domain.APIKey{Revoked: k["revoked"] == true}
+		if expiresAt, ok := k["expiresAt"].(primitive.DateTime); ok {
+			key.ExpiresAt = expiresAt.Time()
+		}
+		k["status"] = key.Status(now, policy)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"apikeys": keys,
 		"total":   len(keys),
@@ -96,7 +111,8 @@ func (h *TenantHandler) CreateAPIKey(c *gin.Context) {
 	}
 
 	var req struct {
-		Name string `json:"name" binding:"required"`
+		Name         string                     `json:"name" binding:"required"`
+		Restrictions *domain.APIKeyRestrictions `json:"restrictions"` // Opcional: IPs/CIDRs, origens e user agents permitidos
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -109,6 +125,19 @@ func (h *TenantHandler) CreateAPIKey(c *gin.Context) {
 		return
 	}
 
+	if err := req.Restrictions.Normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+	if req.Restrictions.Empty() {
+		req.Restrictions = nil
+	}
+
 	ctx := c.Request.Context()
 
 	// ✅ Gerar API key REAL (mesmo algoritmo do admin)
@@ -123,13 +152,14 @@ func (h *TenantHandler) CreateAPIKey(c *gin.Context) {
 
 	// Usar domain.APIKey para garantir consistência
 	k := &domain.APIKey{
-		KeyID:     keyId,
-		KeyHash:   hash,
-		OwnerID:   tenantID,
-		Scopes:    []string{"geo:read"},
-		ExpiresAt: now.Add(time.Duration(days) * 24 * time.Hour),
-		Revoked:   false,
-		CreatedAt: now,
+		KeyID:        keyId,
+		KeyHash:      hash,
+		OwnerID:      tenantID,
+		Scopes:       []string{"geo:read"},
+		ExpiresAt:    now.Add(time.Duration(days) * 24 * time.Hour),
+		Revoked:      false,
+		CreatedAt:    now,
+		Restrictions: req.Restrictions,
 	}
 
 	if err := h.apikeys.Insert(ctx, k); err != nil {
@@ -149,9 +179,10 @@ func (h *TenantHandler) CreateAPIKey(c *gin.Context) {
 
 	// ⚠️ IMPORTANTE: Retornar a chave COMPLETA apenas agora!
 	c.JSON(http.StatusCreated, gin.H{
-		"key":       keyId + "." + keySecret, // ← Chave completa!
-		"expiresAt": k.ExpiresAt,
-		"name":      req.Name,
+		"key":          keyId + "." + keySecret, // ← Chave completa!
+		"expiresAt":    k.ExpiresAt,
+		"name":         req.Name,
+		"restrictions": k.Restrictions,
 	})
 }
 
@@ -232,13 +263,14 @@ func (h *TenantHandler) RotateAPIKey(c *gin.Context) {
 	}
 
 	newKey := &domain.APIKey{
-		KeyID:     keyId,
-		KeyHash:   hash,
-		OwnerID:   tenantID,
-		Scopes:    existingKey.Scopes,
-		ExpiresAt: now.Add(time.Duration(days) * 24 * time.Hour),
-		Revoked:   false,
-		CreatedAt: now,
+		KeyID:        keyId,
+		KeyHash:      hash,
+		OwnerID:      tenantID,
+		Scopes:       existingKey.Scopes,
+		ExpiresAt:    now.Add(time.Duration(days) * 24 * time.Hour),
+		Revoked:      false,
+		CreatedAt:    now,
+		Restrictions: existingKey.Restrictions,
 	}
 
 	if err := h.apikeys.Insert(ctx, newKey); err != nil {
@@ -264,6 +296,269 @@ func (h *TenantHandler) RotateAPIKey(c *gin.Context) {
 	})
 }
 
+// ExtendAPIKey estende a validade de uma API key do tenant logado (mantém a mesma chave)
+// POST /me/apikeys/:id/extend
+//
+// Body opcional: {"days": 90} (padrão: APIKEY_TTL_DAYS). A nova validade conta a partir da validade atual
+// (ou de agora, se a key já expirou) e é limitada a APIKEY_MAX_TTL_DAYS a partir de agora.
+func (h *TenantHandler) ExtendAPIKey(c *gin.Context) {
+	tenantID := auth.GetTenantID(c)
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"type":   "https://retech-core/errors/unauthorized",
+			"title":  "Unauthorized",
+			"status": http.StatusUnauthorized,
+			"detail": "Tenant ID não encontrado",
+		})
+		return
+	}
+
+	var req struct {
+		Days int `json:"days"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"type":   "https://retech-core/errors/validation-error",
+				"title":  "Validation Error",
+				"status": http.StatusBadRequest,
+				"detail": err.Error(),
+			})
+			return
+		}
+	}
+
+	policy := domain.APIKeyExpiryPolicyFromEnv()
+	maxDays := int(policy.MaxTTL.Hours() / 24)
+	if req.Days == 0 {
+		req.Days = envIntTenant("APIKEY_TTL_DAYS", 90)
+	}
+	if req.Days < 1 || req.Days > maxDays {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": fmt.Sprintf("'days' deve estar entre 1 e %d", maxDays),
+		})
+		return
+	}
+
+	keyID := c.Param("id")
+	ctx := c.Request.Context()
+
+	existingKey, err := h.apikeys.ByKeyIDAny(ctx, keyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Erro interno",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao buscar API key",
+		})
+		return
+	}
+
+	if existingKey == nil || existingKey.OwnerID != tenantID {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "API Key não encontrada",
+			"status": http.StatusNotFound,
+			"detail": "API key não existe",
+		})
+		return
+	}
+
+	if existingKey.Revoked {
+		c.JSON(http.StatusConflict, gin.H{
+			"type":   "https://retech-core/errors/conflict",
+			"title":  "API Key revogada",
+			"status": http.StatusConflict,
+			"detail": "API key revogada não pode ser estendida. Crie uma nova key",
+		})
+		return
+	}
+
+	now := time.Now().UTC()
+	base := existingKey.ExpiresAt
+	if base.Before(now) {
+		base = now
+	}
+	expiresAt := base.Add(time.Duration(req.Days) * 24 * time.Hour)
+	if limite := now.Add(policy.MaxTTL); expiresAt.After(limite) {
+		expiresAt = limite
+	}
+
+	if err := h.apikeys.Extend(ctx, keyID, expiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Erro interno",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao estender API key",
+		})
+		return
+	}
+
+	utils.LogActivity(
+		c,
+		h.activityLogs,
+		domain.ActivityTypeAPIKeyExtended,
+		domain.ActionExtend,
+		utils.BuildActorFromContext(c),
+		domain.Resource{
+			Type: domain.ResourceTypeAPIKey,
+			ID:   keyID,
+			Name: tenantID,
+		},
+		map[string]interface{}{
+			"tenantId":          tenantID,
+			"previousExpiresAt": existingKey.ExpiresAt,
+			"expiresAt":         expiresAt,
+			"days":              req.Days,
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"keyId":             keyID,
+		"previousExpiresAt": existingKey.ExpiresAt,
+		"expiresAt":         expiresAt,
+		"message":           "Validade da API key estendida com sucesso",
+	})
+}
+
+// GetAPIKey detalha uma API key do tenant logado (sem o hash)
+// GET /me/apikeys/:id
+func (h *TenantHandler) GetAPIKey(c *gin.Context) {
+	k, ok := h.findMyAPIKey(c)
+	if !ok {
+		return
+	}
+
+	policy := domain.APIKeyExpiryPolicyFromEnv()
+	c.JSON(http.StatusOK, gin.H{
+		"keyId":        k.KeyID,
+		"scopes":       k.Scopes,
+		"expiresAt":    k.ExpiresAt,
+		"revoked":      k.Revoked,
+		"createdAt":    k.CreatedAt,
+		"status":       k.Status(time.Now().UTC(), policy),
+		"restrictions": k.Restrictions,
+	})
+}
+
+// UpdateAPIKey altera as restrições de uso de uma API key do tenant logado
+// PATCH /me/apikeys/:id
+//
+// Body: {"restrictions": {"allowedIps": [...], "allowedOrigins": [...], "allowedUserAgents": [...]}}
+// Listas vazias (ou "restrictions": null) removem a restrição.
+func (h *TenantHandler) UpdateAPIKey(c *gin.Context) {
+	var req struct {
+		Restrictions *domain.APIKeyRestrictions `json:"restrictions"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+	if err := req.Restrictions.Normalize(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"type":   "https://retech-core/errors/validation-error",
+			"title":  "Validation Error",
+			"status": http.StatusBadRequest,
+			"detail": err.Error(),
+		})
+		return
+	}
+	if req.Restrictions.Empty() {
+		req.Restrictions = nil
+	}
+
+	k, ok := h.findMyAPIKey(c)
+	if !ok {
+		return
+	}
+	if k.Revoked {
+		c.JSON(http.StatusConflict, gin.H{
+			"type":   "https://retech-core/errors/conflict",
+			"title":  "API Key revogada",
+			"status": http.StatusConflict,
+			"detail": "API key revogada não pode ser alterada",
+		})
+		return
+	}
+
+	if err := h.apikeys.UpdateRestrictions(c.Request.Context(), k.KeyID, req.Restrictions); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Erro interno",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao atualizar API key",
+		})
+		return
+	}
+
+	utils.LogActivity(
+		c,
+		h.activityLogs,
+		domain.ActivityTypeAPIKeyUpdated,
+		domain.ActionUpdate,
+		utils.BuildActorFromContext(c),
+		domain.Resource{
+			Type: domain.ResourceTypeAPIKey,
+			ID:   k.KeyID,
+			Name: k.OwnerID,
+		},
+		map[string]interface{}{
+			"tenantId":             k.OwnerID,
+			"previousRestrictions": k.Restrictions,
+			"restrictions":         req.Restrictions,
+		},
+	)
+
+	c.JSON(http.StatusOK, gin.H{
+		"keyId":        k.KeyID,
+		"restrictions": req.Restrictions,
+		"message":      "API key atualizada com sucesso",
+	})
+}
+
+// findMyAPIKey busca a key do parâmetro :id garantindo que pertence ao tenant logado (responde o erro se não)
+func (h *TenantHandler) findMyAPIKey(c *gin.Context) (*domain.APIKey, bool) {
+	tenantID := auth.GetTenantID(c)
+	if tenantID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"type":   "https://retech-core/errors/unauthorized",
+			"title":  "Unauthorized",
+			"status": http.StatusUnauthorized,
+			"detail": "Tenant ID não encontrado",
+		})
+		return nil, false
+	}
+
+	k, err := h.apikeys.ByKeyIDAny(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"type":   "https://retech-core/errors/internal-error",
+			"title":  "Erro interno",
+			"status": http.StatusInternalServerError,
+			"detail": "Erro ao buscar API key",
+		})
+		return nil, false
+	}
+	if k == nil || k.OwnerID != tenantID {
+		c.JSON(http.StatusNotFound, gin.H{
+			"type":   "https://retech-core/errors/not-found",
+			"title":  "API Key não encontrada",
+			"status": http.StatusNotFound,
+			"detail": "API key não existe",
+		})
+		return nil, false
+	}
+	return k, true
+}
+
 // DeleteAPIKey deleta uma API key do tenant logado
 // DELETE /me/apikeys/:id
 func (h *TenantHandler) DeleteAPIKey(c *gin.Context) {
@@ -290,11 +585,8 @@ func (h *TenantHandler) DeleteAPIKey(c *gin.Context) {
 	}
 
 	// Revogar (soft delete)
-	_, err = h.db.DB.Collection("api_keys").UpdateOne(ctx, bson.M{"keyId": keyID}, bson.M{
-		"$set": bson.M{"revoked": true},
-	})
-
-	if err != nil {
+	// Via repositório para invalidar o cache de principals
+	if err := h.apikeys.Revoke(ctx, keyID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"type":   "https://retech-core/errors/internal-error",
 			"title":  "Internal Error",
@@ -376,6 +668,17 @@ func (h *TenantHandler) GetMyConfig(c *gin.Context) {
 					},
 				},
 			},
+			{
+				"category": "Endereço",
+				"items": []gin.H{
+					{
+						"method":      "POST",
+						"path":        "/endereco/normalizar",
+						"description": "🆕 Interpreta endereço em texto livre ou campos, cruza com CEP e IBGE e retorna o endereço canônico com inconsistências",
+						"available":   true,
+					},
+				},
+			},
 			{
 				"category": "CNPJ",
 				"items": []gin.H{
@@ -405,7 +708,7 @@ func (h *TenantHandler) GetMyConfig(c *gin.Context) {
 					{
 						"method":      "GET",
 						"path":        "/geo/municipios",
-						"description": "Lista todos os municípios",
+						"description": "Lista todos os municípios (paginação por cursor: limit/cursor, fields, sort; ETag/If-None-Match)",
 						"available":   true,
 					},
 					{
@@ -414,6 +717,66 @@ func (h *TenantHandler) GetMyConfig(c *gin.Context) {
 						"description": "Municípios de um estado",
 						"available":   true,
 					},
+					{
+						"method":      "GET",
+						"path":        "/geo/municipios/id/:id",
+						"description": "🆕 Município pelo código IBGE (?include=populacao,area,codigos,ddd,fuso,capital,coordenadas ou all)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/municipios/siafi/:codigo",
+						"description": "🆕 Município pelo código SIAFI (também: /geo/municipios/tse/:codigo)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/municipios/id/:id/historico",
+						"description": "🆕 Histórico do município (criações, renomeações, incorporações) com data de vigência",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/municipios/legado",
+						"description": "🆕 Código IBGE ou nome antigo → município vigente (?codigo= ou ?nome=&uf=)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/municipios/resolver",
+						"description": "🆕 Nome livre → municípios ranqueados por confiança (sem acento, abreviações, erros de digitação)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/municipios/id/:id/geojson",
+						"description": "🆕 Contorno do município em GeoJSON (?qualidade=maxima|intermediaria|minima). Também: /geo/ufs/:sigla/geojson",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/reverse",
+						"description": "🆕 Município que contém a coordenada GPS (?lat=&lng=)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/regioes",
+						"description": "🆕 Regiões (N, NE, CO, SE, S) com UFs e total de municípios",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/mesorregioes",
+						"description": "🆕 Mesorregiões (?uf=PE). Também: /microrregioes, /regioes-intermediarias, /regioes-imediatas",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/geo/regioes-imediatas/:id/municipios",
+						"description": "🆕 Municípios de qualquer divisão regional (regioes, mesorregioes, microrregioes, regioes-intermediarias, regioes-imediatas)",
+						"available":   true,
+					},
 				},
 			},
 			{
@@ -434,7 +797,122 @@ func (h *TenantHandler) GetMyConfig(c *gin.Context) {
 					{
 						"method":      "GET",
 						"path":        "/penal/search",
-						"description": "🆕 Busca artigos por texto (descrição ou texto completo). Ideal para busca inteligente",
+						"description": "🆕 Busca artigos por relevância (sem acentos, com trechos destacados). Filtros: legislacao, tipo, page, limit",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/penal/autocomplete",
+						"description": "🆕 Sugestões para digitação por código (\"121 §2\") ou descrição (\"lesão corp\"), índice em memória",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/penal/artigos/:idUnico/arvore",
+						"description": "🆕 Artigo em árvore: caput → parágrafos → incisos → alíneas",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/penal/legislacoes/:leg/estrutura",
+						"description": "🆕 Sumário da legislação: partes, títulos, capítulos e artigos",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/penal/artigos/:idUnico/versoes",
+						"description": "🆕 Redações do dispositivo com vigência e lei alteradora (use ?data=YYYY-MM-DD em /penal/artigos/:idUnico para a redação da época)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/penal/artigos/:idUnico/diff",
+						"description": "🆕 Diferenças palavra a palavra entre duas versões (?de=1&para=2)",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/penal/artigos/:idUnico/referencias",
+						"description": "🆕 Referências cruzadas: dispositivos citados (saídas) e que citam o artigo (entradas)",
+						"available":   true,
+					},
+					{
+						"method":      "POST",
+						"path":        "/penal/dosimetria",
+						"description": "🆕 Faixa de pena com qualificadora, majorantes e minorantes (frações) + prescrição (art. 109 CP)",
+						"available":   true,
+					},
+				},
+			},
+			{
+				"category": "NF-e",
+				"items": []gin.H{
+					{
+						"method":      "GET",
+						"path":        "/nfe/chave/:chave",
+						"description": "🆕 Valida e decodifica chave de acesso NF-e/NFC-e/CT-e (44 dígitos, offline). Use ?enriquecer=true para dados do emitente",
+						"available":   true,
+					},
+				},
+			},
+			{
+				"category": "Boleto",
+				"items": []gin.H{
+					{
+						"method":      "POST",
+						"path":        "/boleto/decodificar",
+						"description": "🆕 Decodifica linha digitável ou código de barras (bancário e concessionárias): valor, vencimento e banco",
+						"available":   true,
+					},
+				},
+			},
+			{
+				"category": "PIX",
+				"items": []gin.H{
+					{
+						"method":      "POST",
+						"path":        "/pix/brcode/decode",
+						"description": "🆕 Decodifica PIX copia e cola (BR Code): chave, valor, recebedor, txid e validação de CRC",
+						"available":   true,
+					},
+					{
+						"method":      "POST",
+						"path":        "/pix/brcode/encode",
+						"description": "🆕 Gera BR Code estático a partir de chave, valor e descrição",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/pix/chave/:chave/tipo",
+						"description": "🆕 Identifica o tipo da chave PIX (CPF, CNPJ, email, telefone ou aleatória)",
+						"available":   true,
+					},
+				},
+			},
+			{
+				"category": "Veículos",
+				"items": []gin.H{
+					{
+						"method":      "GET",
+						"path":        "/placa/:placa",
+						"description": "🆕 Valida placa (antiga ou Mercosul), converte entre formatos e informa a UF de emissão",
+						"available":   true,
+					},
+				},
+			},
+			{
+				"category": "NCM",
+				"items": []gin.H{
+					{
+						"method":      "GET",
+						"path":        "/ncm/:codigo",
+						"description": "🆕 Consulta NCM com hierarquia, subcódigos e CEST aplicáveis",
+						"available":   true,
+					},
+					{
+						"method":      "GET",
+						"path":        "/ncm/buscar?q=",
+						"description": "🆕 Busca NCM por descrição (sem acentos)",
 						"available":   true,
 					},
 				},
@@ -0,0 +1,391 @@
+package handlers
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/auth"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/notify"
+	"github.com/theretech/retech-core/internal/storage"
+	"github.com/theretech/retech-core/internal/utils"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TenantUsersHandler usuários do tenant: convites, papéis, remoção e transferência de propriedade.
+// As regras usam o papel atual no banco (o do JWT só é atualizado no próximo refresh).
+type TenantUsersHandler struct {
+	users        *storage.UsersRepo
+	invitations  *storage.InvitationsRepo
+	activityLogs *storage.ActivityLogsRepo
+	mailer       notify.Mailer
+}
+
+func NewTenantUsersHandler(users *storage.UsersRepo, invitations *storage.InvitationsRepo, activityLogs *storage.ActivityLogsRepo, mailer notify.Mailer) *TenantUsersHandler {
+	return &TenantUsersHandler{
+		users:        users,
+		invitations:  invitations,
+		activityLogs: activityLogs,
+		mailer:       mailer,
+	}
+}
+
+// ListUsers lista os usuários do tenant
+// GET /me/users
+func (h *TenantUsersHandler) ListUsers(c *gin.Context) {
+	users, err := h.users.ListByTenant(c.Request.Context(), auth.GetTenantID(c))
+	if err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao listar usuários")
+		return
+	}
+
+	out := make([]*domain.User, 0, len(users))
+	for _, u := range users {
+		u.TenantRole = u.EffectiveTenantRole()
+		out = append(out, u)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users": out,
+		"total": len(out),
+	})
+}
+
+// ListInvitations convites em aberto (pendentes e expirados)
+// GET /me/users/invitations
+func (h *TenantUsersHandler) ListInvitations(c *gin.Context) {
+	invitations, err := h.invitations.ListOpen(c.Request.Context(), auth.GetTenantID(c))
+	if err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao listar convites")
+		return
+	}
+
+	now := time.Now().UTC()
+	out := make([]gin.H, 0, len(invitations))
+	for i := range invitations {
+		out = append(out, invitationResponse(&invitations[i], now))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"invitations": out,
+		"total":       len(out),
+	})
+}
+
+// InviteUser convida um email para o tenant com um papel; o token de aceite vai apenas por email
+// (INVITATION_RETURN_TOKEN=true também o devolve na resposta, só para desenvolvimento)
+// POST /me/users/invitations
+func (h *TenantUsersHandler) InviteUser(c *gin.Context) {
+	var req domain.InviteUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", err.Error())
+		return
+	}
+
+	me, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	if !req.Role.Valid() || req.Role == domain.TenantRoleOwner {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error",
+			"role inválido: use admin, developer, billing ou viewer (owner só por transferência)")
+		return
+	}
+	if !me.EffectiveTenantRole().CanManage(req.Role) {
+		tenantUserError(c, http.StatusForbidden, "forbidden", "Forbidden", "Seu papel não permite convidar usuários como "+string(req.Role))
+		return
+	}
+
+	ctx := c.Request.Context()
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	existing, err := h.users.FindByEmail(ctx, email)
+	if err != nil && err != mongo.ErrNoDocuments {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao verificar email")
+		return
+	}
+	if existing != nil {
+		// Mensagem única: não revela se a conta é deste ou de outro tenant
+		tenantUserError(c, http.StatusConflict, "conflict", "Email já em uso", "Não é possível convidar este email")
+		return
+	}
+
+	token, tokenHash, err := auth.NewOpaqueToken()
+	if err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao gerar convite")
+		return
+	}
+
+	// Reenvio: o convite anterior para o mesmo email deixa de valer
+	if err := h.invitations.RevokeOpenByEmail(ctx, me.TenantID, email); err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao gerar convite")
+		return
+	}
+
+	now := time.Now().UTC()
+	ttl := time.Duration(envIntTenant("INVITATION_TTL_HOURS", 72)) * time.Hour
+	inv := &domain.Invitation{
+		TenantID:  me.TenantID,
+		Email:     email,
+		Role:      req.Role,
+		TokenHash: tokenHash,
+		InvitedBy: userActor(me),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if err := h.invitations.Insert(ctx, inv); err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao salvar convite")
+		return
+	}
+
+	utils.LogActivity(c, h.activityLogs, domain.ActivityTypeInvitationCreated, domain.ActionInvite, userActor(me),
+		domain.Resource{Type: domain.ResourceTypeInvitation, ID: inv.ID, Name: inv.Email},
+		map[string]interface{}{"tenantId": inv.TenantID, "email": inv.Email, "role": inv.Role, "expiresAt": inv.ExpiresAt})
+
+	sendMailAsync(h.mailer, notify.InvitationEmail(inv.Email, me.Name, string(inv.Role), accountLink("/convite", token), ttl))
+
+	resp := invitationResponse(inv, now)
+	resp["message"] = "Convite enviado para " + inv.Email
+	if os.Getenv("INVITATION_RETURN_TOKEN") == "true" {
+		resp["token"] = token // ⚠️ Apenas desenvolvimento (sem SMTP)
+	}
+
+	c.JSON(http.StatusCreated, resp)
+}
+
+// RevokeInvitation revoga um convite em aberto
+// DELETE /me/users/invitations/:id
+func (h *TenantUsersHandler) RevokeInvitation(c *gin.Context) {
+	me, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	inv, err := h.invitations.ByID(ctx, me.TenantID, c.Param("id"))
+	if err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao buscar convite")
+		return
+	}
+	if inv == nil {
+		tenantUserError(c, http.StatusNotFound, "not-found", "Not Found", "Convite não encontrado")
+		return
+	}
+	if !me.EffectiveTenantRole().CanManage(inv.Role) {
+		tenantUserError(c, http.StatusForbidden, "forbidden", "Forbidden", "Seu papel não permite revogar convites como "+string(inv.Role))
+		return
+	}
+
+	revoked, err := h.invitations.Revoke(ctx, me.TenantID, inv.ID)
+	if err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao revogar convite")
+		return
+	}
+	if !revoked {
+		tenantUserError(c, http.StatusConflict, "conflict", "Conflict", "Convite já aceito ou revogado")
+		return
+	}
+
+	utils.LogActivity(c, h.activityLogs, domain.ActivityTypeInvitationRevoked, domain.ActionRevoke, userActor(me),
+		domain.Resource{Type: domain.ResourceTypeInvitation, ID: inv.ID, Name: inv.Email},
+		map[string]interface{}{"tenantId": inv.TenantID, "email": inv.Email, "role": inv.Role})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Convite revogado com sucesso",
+	})
+}
+
+// UpdateUser altera o papel de um usuário do tenant
+// PATCH /me/users/:id
+func (h *TenantUsersHandler) UpdateUser(c *gin.Context) {
+	var req domain.UpdateTenantUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", err.Error())
+		return
+	}
+
+	me, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	target, ok := h.findUser(c, me)
+	if !ok {
+		return
+	}
+
+	if !req.Role.Valid() || req.Role == domain.TenantRoleOwner {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error",
+			"role inválido: use admin, developer, billing ou viewer (owner só por transferência)")
+		return
+	}
+	if target.ID == me.ID {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", "Não é possível alterar o próprio papel")
+		return
+	}
+
+	from := target.EffectiveTenantRole()
+	if !me.EffectiveTenantRole().CanManage(from) || !me.EffectiveTenantRole().CanManage(req.Role) {
+		tenantUserError(c, http.StatusForbidden, "forbidden", "Forbidden", "Seu papel não permite alterar "+string(from)+" para "+string(req.Role))
+		return
+	}
+
+	if err := h.users.UpdateTenantRole(c.Request.Context(), target.ID, req.Role); err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao atualizar usuário")
+		return
+	}
+	target.TenantRole = req.Role
+
+	utils.LogActivity(c, h.activityLogs, domain.ActivityTypeUserUpdated, domain.ActionUpdate, userActor(me),
+		domain.Resource{Type: domain.ResourceTypeUser, ID: target.ID, Name: target.Name},
+		map[string]interface{}{"tenantId": me.TenantID, "email": target.Email, "from": from, "to": req.Role})
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":    target,
+		"message": "Papel atualizado (vale a partir do próximo refresh do token do usuário)",
+	})
+}
+
+// RemoveUser remove um usuário do tenant
+// DELETE /me/users/:id
+func (h *TenantUsersHandler) RemoveUser(c *gin.Context) {
+	me, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	target, ok := h.findUser(c, me)
+	if !ok {
+		return
+	}
+
+	if target.ID == me.ID {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", "Não é possível remover a si mesmo")
+		return
+	}
+	role := target.EffectiveTenantRole()
+	if !me.EffectiveTenantRole().CanManage(role) {
+		tenantUserError(c, http.StatusForbidden, "forbidden", "Forbidden", "Seu papel não permite remover usuários "+string(role))
+		return
+	}
+
+	if err := h.users.Delete(c.Request.Context(), target.ID); err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao remover usuário")
+		return
+	}
+
+	utils.LogActivity(c, h.activityLogs, domain.ActivityTypeUserRemoved, domain.ActionDelete, userActor(me),
+		domain.Resource{Type: domain.ResourceTypeUser, ID: target.ID, Name: target.Name},
+		map[string]interface{}{"tenantId": me.TenantID, "email": target.Email, "role": role})
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Usuário removido do tenant",
+	})
+}
+
+// TransferOwnership transfere a propriedade do tenant (o owner atual passa a admin)
+// POST /me/users/transfer-ownership
+func (h *TenantUsersHandler) TransferOwnership(c *gin.Context) {
+	var req domain.TransferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", err.Error())
+		return
+	}
+
+	me, ok := h.currentUser(c)
+	if !ok {
+		return
+	}
+	if me.EffectiveTenantRole() != domain.TenantRoleOwner {
+		tenantUserError(c, http.StatusForbidden, "forbidden", "Forbidden", "Apenas o owner pode transferir a propriedade do tenant")
+		return
+	}
+
+	ctx := c.Request.Context()
+	target, err := h.users.FindByID(ctx, req.UserID)
+	if err != nil || target.TenantID != me.TenantID {
+		tenantUserError(c, http.StatusNotFound, "not-found", "Not Found", "Usuário não encontrado")
+		return
+	}
+	if target.ID == me.ID {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", "Você já é o owner do tenant")
+		return
+	}
+	if !target.Active {
+		tenantUserError(c, http.StatusBadRequest, "validation-error", "Validation Error", "O novo owner precisa ser um usuário ativo")
+		return
+	}
+
+	previousRole := target.EffectiveTenantRole()
+
+	// Promove antes de rebaixar: em caso de falha o tenant nunca fica sem owner
+	if err := h.users.UpdateTenantRole(ctx, target.ID, domain.TenantRoleOwner); err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao transferir propriedade")
+		return
+	}
+	if err := h.users.UpdateTenantRole(ctx, me.ID, domain.TenantRoleAdmin); err != nil {
+		tenantUserError(c, http.StatusInternalServerError, "internal-error", "Internal Error", "Erro ao transferir propriedade")
+		return
+	}
+
+	utils.LogActivity(c, h.activityLogs, domain.ActivityTypeTenantTransferred, domain.ActionTransfer, userActor(me),
+		domain.Resource{Type: domain.ResourceTypeTenant, ID: me.TenantID},
+		map[string]interface{}{"from": me.ID, "fromEmail": me.Email, "to": target.ID, "toEmail": target.Email, "previousRole": previousRole})
+
+	c.JSON(http.StatusOK, gin.H{
+		"ownerId": target.ID,
+		"message": "Propriedade transferida. Seu papel agora é admin (faça refresh do token)",
+	})
+}
+
+// currentUser usuário autenticado (papel atual no banco); responde 401 se não existe mais ou foi desativado
+func (h *TenantUsersHandler) currentUser(c *gin.Context) (*domain.User, bool) {
+	me, err := h.users.FindByID(c.Request.Context(), auth.GetUserID(c))
+	if err != nil || !me.Active || me.TenantID != auth.GetTenantID(c) {
+		tenantUserError(c, http.StatusUnauthorized, "unauthorized", "Unauthorized", "Usuário não encontrado ou inativo")
+		return nil, false
+	}
+	return me, true
+}
+
+// findUser usuário :id do mesmo tenant; responde 404 caso contrário
+func (h *TenantUsersHandler) findUser(c *gin.Context, me *domain.User) (*domain.User, bool) {
+	target, err := h.users.FindByID(c.Request.Context(), c.Param("id"))
+	if err != nil || target.TenantID != me.TenantID {
+		tenantUserError(c, http.StatusNotFound, "not-found", "Not Found", "Usuário não encontrado")
+		return nil, false
+	}
+	return target, true
+}
+
+func userActor(u *domain.User) domain.Actor {
+	return domain.Actor{
+		UserID: u.ID,
+		Email:  u.Email,
+		Name:   u.Name,
+		Role:   string(u.Role),
+	}
+}
+
+func invitationResponse(inv *domain.Invitation, now time.Time) gin.H {
+	return gin.H{
+		"id":        inv.ID,
+		"email":     inv.Email,
+		"role":      inv.Role,
+		"status":    inv.Status(now),
+		"invitedBy": inv.InvitedBy,
+		"createdAt": inv.CreatedAt,
+		"expiresAt": inv.ExpiresAt,
+	}
+}
+
+func tenantUserError(c *gin.Context, status int, errType, title, detail string) {
+	c.JSON(status, gin.H{
+		"type":   "https://retech-core/errors/" + errType,
+		"title":  title,
+		"status": status,
+		"detail": detail,
+	})
+}
@@ -1,14 +1,19 @@
 package http
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 
 	"github.com/theretech/retech-core/internal/auth"
+	"github.com/theretech/retech-core/internal/domain"
 	"github.com/theretech/retech-core/internal/http/handlers"
 	"github.com/theretech/retech-core/internal/middleware"
+	"github.com/theretech/retech-core/internal/notify"
 	"github.com/theretech/retech-core/internal/storage"
 )
 
@@ -28,6 +33,16 @@ func NewRouter(
 ) *gin.Engine {
 	r := gin.New()
 
+	// 🛡️ PROXIES CONFIÁVEIS: ClientIP() só considera X-Forwarded-For/X-Real-IP vindos de TRUSTED_PROXIES
+	// (sem a variável, usa o IP da conexão: o cabeçalho não burla as restrições de IP das API keys)
+	if err := r.SetTrustedProxies(trustedProxiesFromEnv()); err != nil {
+		log.Warn().Err(err).Msg("TRUSTED_PROXIES inválido: cabeçalhos de proxy serão ignorados")
+		_ = r.SetTrustedProxies(nil)
+	}
+	if platform := os.Getenv("TRUSTED_PLATFORM"); platform != "" {
+		r.TrustedPlatform = platform // Cabeçalho com o IP do cliente definido pela plataforma (ex: CF-Connecting-IP)
+	}
+
 	// 🌐 CORS DINÂMICO (lê de admin/settings)
 	r.Use(func(c *gin.Context) {
 		ctx := c.Request.Context()
@@ -55,7 +70,8 @@ func NewRouter(
 		if isPublicRoute {
 			c.Header("Access-Control-Allow-Origin", origin)
 			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-API-Key, Cache-Control, Pragma, Expires, X-Browser-Fingerprint, X-Client-IP")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-API-Key, Cache-Control, Pragma, Expires, X-Browser-Fingerprint, X-Client-IP, If-None-Match")
+			c.Header("Access-Control-Expose-Headers", "ETag")
 			c.Header("Access-Control-Allow-Credentials", "true")
 			c.Header("Access-Control-Max-Age", "86400")
 
@@ -94,7 +110,8 @@ func NewRouter(
 		if allowed {
 			c.Header("Access-Control-Allow-Origin", origin)
 			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-API-Key, Cache-Control, Pragma, Expires, X-Browser-Fingerprint, X-Client-IP")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-Requested-With, X-API-Key, Cache-Control, Pragma, Expires, X-Browser-Fingerprint, X-Client-IP, If-None-Match")
+			c.Header("Access-Control-Expose-Headers", "ETag")
 			c.Header("Access-Control-Allow-Credentials", "true")
 			c.Header("Access-Control-Max-Age", "86400")
 		}
@@ -114,6 +131,10 @@ func NewRouter(
 		c.Next()
 	})
 
+	// 🔑 Principal das API keys (LRU + Redis, invalidado por revogação/rotação/tenant/settings)
+	principals := auth.NewPrincipalResolver(apikeys, tenants, settings, redisClient)
+	principals.Listen(context.Background())
+
 	// Middlewares globais
 	rateLimiter := middleware.NewRateLimiter(m.DB, tenants, settings)
 	playgroundRateLimiter := middleware.NewPlaygroundRateLimiter(m.DB, settings)
@@ -137,8 +158,15 @@ func NewRouter(
 	// Public playground/tools endpoints (sem API Key, rate limit por IP)
 	cepHandler := handlers.NewCEPHandler(m, redisClient, settings)
 	cnpjHandler := handlers.NewCNPJHandler(m, redisClient, settings)
-	geoHandler := handlers.NewGeoHandler(estados, municipios, redisClient)
+	geoHandler := handlers.NewGeoHandler(estados, municipios, storage.NewGeoDivisoesRepo(m.DB), storage.NewGeoMalhasRepo(m.DB), redisClient)
+	enderecoHandler := handlers.NewEnderecoHandler(cepHandler, geoHandler, estados, municipios)
 	penalHandler := handlers.NewPenalHandler(m, redisClient)
+	penalHandler.StartIndexRefresher(context.Background()) // Índices de busca/autocomplete prontos antes da primeira consulta e atualizados em background
+	nfeHandler := handlers.NewNFeHandler(m, estados, redisClient)
+	boletoHandler := handlers.NewBoletoHandler(storage.NewBancosRepo(m.DB))
+	pixHandler := handlers.NewPixHandler()
+	ncmHandler := handlers.NewNCMHandler(storage.NewNCMRepo(m.DB), activityLogs, redisClient)
+	placaHandler := handlers.NewPlacaHandler(storage.NewPlacasFaixasRepo(m.DB), nil) // sem provider de registro por enquanto
 
 	// 🔒 ROTAS PÚBLICAS COM SEGURANÇA MULTI-CAMADA
 	// API Key Demo (obrigatória) + Scopes + Rate limiting por IP + Fingerprinting + Throttling
@@ -146,8 +174,8 @@ func NewRouter(
 	{
 		// CEP (requer scope 'cep')
 		publicGroup.GET("/cep/:codigo",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "cep"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("cep"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			cepHandler.GetCEP,
@@ -155,8 +183,8 @@ func NewRouter(
 
 		// CEP Search - Busca reversa (requer scope 'cep')
 		publicGroup.GET("/cep/buscar",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "cep"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("cep"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			cepHandler.SearchCEP,
@@ -164,8 +192,8 @@ func NewRouter(
 
 		// CNPJ (requer scope 'cnpj')
 		publicGroup.GET("/cnpj/:numero",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "cnpj"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("cnpj"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			cnpjHandler.GetCNPJ,
@@ -173,16 +201,16 @@ func NewRouter(
 
 		// GEO (requer scope 'geo')
 		publicGroup.GET("/geo/ufs",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "geo"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("geo"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			geoHandler.ListUFs,
 		)
 
 		publicGroup.GET("/geo/ufs/:sigla",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "geo"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("geo"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			geoHandler.GetUF,
@@ -190,8 +218,8 @@ func NewRouter(
 
 		// PENAL (requer scope 'penal')
 		publicGroup.GET("/penal/artigos",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "penal"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("penal"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			penalHandler.ListArtigos,
@@ -199,16 +227,16 @@ func NewRouter(
 
 		// Usar *codigo para permitir códigos com : (ex: DRG:33)
 		publicGroup.GET("/penal/artigos/*codigo",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "penal"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("penal"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			penalHandler.GetArtigo,
 		)
 
 		publicGroup.GET("/penal/search",
-			auth.AuthAPIKey(apikeys),
-			auth.RequireScope(apikeys, "penal"), // ✅ Valida scope!
+			auth.AuthAPIKey(principals),
+			auth.RequireScope("penal"), // ✅ Valida scope!
 			playgroundRateLimiter.Middleware(),
 			usageLogger.Middleware(),
 			penalHandler.SearchArtigos,
@@ -216,21 +244,31 @@ func NewRouter(
 	}
 
 	// Auth endpoints (públicos)
-	authHandler := handlers.NewAuthHandler(users, tenants, apikeys, activityLogs, settings, jwtService)
+	invitations := storage.NewInvitationsRepo(m.DB)
+	mailer := notify.NewMailerFromEnv(log)
+	authHandler := handlers.NewAuthHandler(users, tenants, apikeys, invitations, storage.NewUserTokensRepo(m.DB), activityLogs, settings, jwtService, mailer)
 	authGroup := r.Group("/auth")
 	{
 		authGroup.POST("/login", authHandler.Login)
 		authGroup.POST("/register", authHandler.Register)
 		authGroup.POST("/refresh", authHandler.RefreshToken)
+		authGroup.POST("/invitations/accept", authHandler.AcceptInvitation) // Cria o usuário convidado e já autentica
+		authGroup.POST("/forgot-password", authHandler.ForgotPassword)
+		authGroup.POST("/reset-password", authHandler.ResetPassword)
+		authGroup.POST("/verify-email", authHandler.VerifyEmail)
+		authGroup.POST("/verify-email/resend", auth.AuthJWT(jwtService), authHandler.ResendVerification)
 		authGroup.GET("/me", auth.AuthJWT(jwtService), authHandler.Me)
 	}
 
+	// Minha conta: qualquer usuário autenticado (tenant ou SUPER_ADMIN), fora do grupo /me de tenant
+	r.PUT("/me/password", auth.AuthJWT(jwtService), authHandler.ChangePassword) // Exige a senha atual; devolve novos tokens
+
 	// GEO endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
 	geoGroup := r.Group("/geo")
 	geoGroup.Use(
 		maintenanceMiddleware.Middleware(), // Verifica manutenção
-		auth.AuthAPIKey(apikeys),           // Requer API Key válida
-		auth.RequireScope(apikeys, "geo"),  // ✅ Verifica scope 'geo' ou 'all'
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("geo"),           // ✅ Verifica scope 'geo' ou 'all'
 		rateLimiter.Middleware(),           // Aplica rate limiting
 		usageLogger.Middleware(),           // Loga uso
 	)
@@ -239,15 +277,40 @@ func NewRouter(
 		geoGroup.GET("/ufs/:sigla", geoHandler.GetUF)
 		geoGroup.GET("/municipios", geoHandler.ListMunicipios)
 		geoGroup.GET("/municipios/:uf", geoHandler.ListMunicipiosByUF)
-		geoGroup.GET("/municipios/id/:id", geoHandler.GetMunicipio)
+		geoGroup.GET("/municipios/id/:id", geoHandler.GetMunicipio) // ?include=populacao,codigos,... ou all
+		geoGroup.GET("/municipios/id/:id/geojson", geoHandler.GetMunicipioGeoJSON) // ?qualidade=maxima|intermediaria|minima
+		geoGroup.GET("/municipios/siafi/:codigo", geoHandler.GetMunicipioByCodigo("siafi"))
+		geoGroup.GET("/municipios/tse/:codigo", geoHandler.GetMunicipioByCodigo("tse"))
+		geoGroup.GET("/municipios/id/:id/historico", geoHandler.GetMunicipioHistorico)
+		geoGroup.GET("/municipios/legado", geoHandler.ResolverMunicipioLegado) // ?codigo= (6 ou 7 dígitos) ou ?nome=&uf=
+		geoGroup.GET("/municipios/resolver", geoHandler.ResolverMunicipio)     // ?nome=&uf=&limit= (ranking por similaridade)
+		geoGroup.GET("/ufs/:sigla/geojson", geoHandler.GetUFGeoJSON)
+		geoGroup.GET("/reverse", geoHandler.Reverse) // ?lat=&lng= → município que contém o ponto
+
+		// Divisão regional do IBGE (collections pré-agregadas no seed)
+		geoGroup.GET("/regioes", geoHandler.ListDivisoes(domain.GeoNivelRegiao))
+		geoGroup.GET("/regioes/:id", geoHandler.GetDivisao(domain.GeoNivelRegiao)) // :id = ID do IBGE ou sigla (NE)
+		geoGroup.GET("/regioes/:id/municipios", geoHandler.ListMunicipiosByDivisao(domain.GeoNivelRegiao))
+		geoGroup.GET("/mesorregioes", geoHandler.ListDivisoes(domain.GeoNivelMesorregiao)) // ?uf=PE
+		geoGroup.GET("/mesorregioes/:id", geoHandler.GetDivisao(domain.GeoNivelMesorregiao))
+		geoGroup.GET("/mesorregioes/:id/municipios", geoHandler.ListMunicipiosByDivisao(domain.GeoNivelMesorregiao))
+		geoGroup.GET("/microrregioes", geoHandler.ListDivisoes(domain.GeoNivelMicrorregiao))
+		geoGroup.GET("/microrregioes/:id", geoHandler.GetDivisao(domain.GeoNivelMicrorregiao))
+		geoGroup.GET("/microrregioes/:id/municipios", geoHandler.ListMunicipiosByDivisao(domain.GeoNivelMicrorregiao))
+		geoGroup.GET("/regioes-intermediarias", geoHandler.ListDivisoes(domain.GeoNivelRegiaoIntermediaria))
+		geoGroup.GET("/regioes-intermediarias/:id", geoHandler.GetDivisao(domain.GeoNivelRegiaoIntermediaria))
+		geoGroup.GET("/regioes-intermediarias/:id/municipios", geoHandler.ListMunicipiosByDivisao(domain.GeoNivelRegiaoIntermediaria))
+		geoGroup.GET("/regioes-imediatas", geoHandler.ListDivisoes(domain.GeoNivelRegiaoImediata))
+		geoGroup.GET("/regioes-imediatas/:id", geoHandler.GetDivisao(domain.GeoNivelRegiaoImediata))
+		geoGroup.GET("/regioes-imediatas/:id/municipios", geoHandler.ListMunicipiosByDivisao(domain.GeoNivelRegiaoImediata))
 	}
 
 	// CEP endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
 	cepGroup := r.Group("/cep")
 	cepGroup.Use(
 		maintenanceMiddleware.Middleware(), // Verifica manutenção
-		auth.AuthAPIKey(apikeys),           // Requer API Key válida
-		auth.RequireScope(apikeys, "cep"),  // ✅ Verifica scope 'cep' ou 'all'
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("cep"),           // ✅ Verifica scope 'cep' ou 'all'
 		rateLimiter.Middleware(),           // Aplica rate limiting
 		usageLogger.Middleware(),           // Loga uso
 	)
@@ -256,12 +319,25 @@ func NewRouter(
 		cepGroup.GET("/buscar", cepHandler.SearchCEP) // Busca reversa
 	}
 
+	// ENDEREÇO endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
+	enderecoGroup := r.Group("/endereco")
+	enderecoGroup.Use(
+		maintenanceMiddleware.Middleware(), // Verifica manutenção
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("endereco"),      // ✅ Verifica scope 'endereco' ou 'all'
+		rateLimiter.Middleware(),           // Aplica rate limiting
+		usageLogger.Middleware(),           // Loga uso
+	)
+	{
+		enderecoGroup.POST("/normalizar", enderecoHandler.Normalizar) // Texto livre/campos → endereço canônico (CEP + IBGE)
+	}
+
 	// CNPJ endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
 	cnpjGroup := r.Group("/cnpj")
 	cnpjGroup.Use(
 		maintenanceMiddleware.Middleware(), // Verifica manutenção
-		auth.AuthAPIKey(apikeys),           // Requer API Key válida
-		auth.RequireScope(apikeys, "cnpj"), // ✅ Verifica scope 'cnpj' ou 'all'
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("cnpj"),          // ✅ Verifica scope 'cnpj' ou 'all'
 		rateLimiter.Middleware(),           // Aplica rate limiting
 		usageLogger.Middleware(),           // Loga uso
 	)
@@ -273,15 +349,90 @@ func NewRouter(
 	penalGroup := r.Group("/penal")
 	penalGroup.Use(
 		maintenanceMiddleware.Middleware(), // Verifica manutenção
-		auth.AuthAPIKey(apikeys),           // Requer API Key válida
-		auth.RequireScope(apikeys, "penal"), // ✅ Verifica scope 'penal' ou 'all'
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("penal"),         // ✅ Verifica scope 'penal' ou 'all'
 		rateLimiter.Middleware(),           // Aplica rate limiting
 		usageLogger.Middleware(),           // Loga uso
 	)
 	{
 		penalGroup.GET("/artigos", penalHandler.ListArtigos)
 		penalGroup.GET("/artigos/:codigo", penalHandler.GetArtigo)
+		penalGroup.GET("/artigos/:codigo/arvore", penalHandler.GetArvore) // :codigo = idUnico (ex: CP:121)
+		penalGroup.GET("/artigos/:codigo/versoes", penalHandler.GetVersoes)
+		penalGroup.GET("/artigos/:codigo/diff", penalHandler.GetDiffVersoes) // ?de=1&para=2
+		penalGroup.GET("/artigos/:codigo/referencias", penalHandler.GetReferencias) // citações (saídas e entradas)
+		penalGroup.GET("/legislacoes/:leg/estrutura", penalHandler.GetEstrutura)
 		penalGroup.GET("/search", penalHandler.SearchArtigos)
+		penalGroup.GET("/autocomplete", penalHandler.Autocomplete) // ?prefix=121 §2
+		penalGroup.POST("/dosimetria", penalHandler.Dosimetria)
+	}
+
+	// NF-e endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
+	nfeGroup := r.Group("/nfe")
+	nfeGroup.Use(
+		maintenanceMiddleware.Middleware(), // Verifica manutenção
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("nfe"),           // ✅ Verifica scope 'nfe' ou 'all'
+		rateLimiter.Middleware(),           // Aplica rate limiting
+		usageLogger.Middleware(),           // Loga uso
+	)
+	{
+		nfeGroup.GET("/chave/:chave", nfeHandler.GetChave) // Validação offline da chave de acesso
+	}
+
+	// BOLETO endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
+	boletoGroup := r.Group("/boleto")
+	boletoGroup.Use(
+		maintenanceMiddleware.Middleware(), // Verifica manutenção
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("boleto"),        // ✅ Verifica scope 'boleto' ou 'all'
+		rateLimiter.Middleware(),           // Aplica rate limiting
+		usageLogger.Middleware(),           // Loga uso
+	)
+	{
+		boletoGroup.POST("/decodificar", boletoHandler.Decodificar)
+	}
+
+	// PIX endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
+	pixGroup := r.Group("/pix")
+	pixGroup.Use(
+		maintenanceMiddleware.Middleware(), // Verifica manutenção
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("pix"),           // ✅ Verifica scope 'pix' ou 'all'
+		rateLimiter.Middleware(),           // Aplica rate limiting
+		usageLogger.Middleware(),           // Loga uso
+	)
+	{
+		pixGroup.POST("/brcode/decode", pixHandler.DecodeBRCode)
+		pixGroup.POST("/brcode/encode", pixHandler.EncodeBRCode)
+		pixGroup.GET("/chave/:chave/tipo", pixHandler.TipoChave)
+	}
+
+	// PLACA endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
+	placaGroup := r.Group("/placa")
+	placaGroup.Use(
+		maintenanceMiddleware.Middleware(), // Verifica manutenção
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("placa"),         // ✅ Verifica scope 'placa' ou 'all'
+		rateLimiter.Middleware(),           // Aplica rate limiting
+		usageLogger.Middleware(),           // Loga uso
+	)
+	{
+		placaGroup.GET("/:placa", placaHandler.GetPlaca)
+	}
+
+	// NCM endpoints (protegidos por API Key + rate limit + logging + manutenção + scopes)
+	ncmGroup := r.Group("/ncm")
+	ncmGroup.Use(
+		maintenanceMiddleware.Middleware(), // Verifica manutenção
+		auth.AuthAPIKey(principals),        // Requer API Key válida
+		auth.RequireScope("ncm"),           // ✅ Verifica scope 'ncm' ou 'all'
+		rateLimiter.Middleware(),           // Aplica rate limiting
+		usageLogger.Middleware(),           // Loga uso
+	)
+	{
+		ncmGroup.GET("/buscar", ncmHandler.SearchNCM)
+		ncmGroup.GET("/:codigo", ncmHandler.GetNCM)
 	}
 
 	// Admin endpoints (protegidos por JWT + role SUPER_ADMIN)
@@ -303,6 +454,7 @@ func NewRouter(
 		adminGroup.POST("/apikeys", apikeysHandler.Create)
 		adminGroup.POST("/apikeys/rotate", apikeysHandler.Rotate)
 		adminGroup.POST("/apikeys/revoke", apikeysHandler.Revoke)
+		adminGroup.GET("/permissions", adminHandler.ListPermissions) // Catálogo de scopes (api:ação) e rotas cobertas
 
 		// Analytics (admin only)
 		adminGroup.GET("/stats", adminHandler.GetStats)
@@ -324,8 +476,12 @@ func NewRouter(
 		adminGroup.DELETE("/cache/cep", cepHandler.ClearCache)
 		adminGroup.GET("/cache/cnpj/stats", cnpjHandler.GetCacheStats)
 		adminGroup.DELETE("/cache/cnpj", cnpjHandler.ClearCache)
+		adminGroup.GET("/cache/principals/stats", principals.GetStats) // Acertos do cache de API keys e latência por origem
 		adminGroup.GET("/cache/penal/stats", penalHandler.GetCacheStats)
 
+		// Datasets (admin only)
+		adminGroup.POST("/ncm/reimport", ncmHandler.Reimport)
+
 		// Redis Cache Management (admin only)
 		redisStatsHandler := handlers.NewRedisStatsHandler(redisClient)
 		adminGroup.GET("/cache/redis/stats", redisStatsHandler.GetStats)
@@ -341,16 +497,32 @@ func NewRouter(
 		adminGroup.GET("/activity/resource/:type/:id", activityHandler.GetByResource)
 	}
 
-	// Tenant endpoints (protegidos por JWT + role TENANT_USER)
-	tenantHandler := handlers.NewTenantHandler(apikeys, users, tenants, m)
+	// Tenant endpoints (protegidos por JWT + role TENANT_USER; papéis no tenant por rota)
+	tenantHandler := handlers.NewTenantHandler(apikeys, users, tenants, activityLogs, m)
+	tenantUsersHandler := handlers.NewTenantUsersHandler(users, invitations, activityLogs, mailer)
+	readKeys := auth.RequireTenantRole(users, domain.TenantRolesReadKeys...) // Papel conferido no banco (o do JWT pode estar defasado)
+	manageKeys := auth.RequireTenantRole(users, domain.TenantRolesManageKeys...)
+	manageUsers := auth.RequireTenantRole(users, domain.TenantRolesManageUsers...)
 	meGroup := r.Group("/me")
 	meGroup.Use(auth.AuthJWT(jwtService), auth.RequireTenantUser())
 	{
 		// Minhas API Keys
-		meGroup.GET("/apikeys", tenantHandler.ListMyAPIKeys)
-		meGroup.POST("/apikeys", tenantHandler.CreateAPIKey)
-		meGroup.POST("/apikeys/:id/rotate", tenantHandler.RotateAPIKey)
-		meGroup.DELETE("/apikeys/:id", tenantHandler.DeleteAPIKey)
+		meGroup.GET("/apikeys", readKeys, tenantHandler.ListMyAPIKeys)
+		meGroup.POST("/apikeys", manageKeys, tenantHandler.CreateAPIKey)
+		meGroup.POST("/apikeys/:id/rotate", manageKeys, tenantHandler.RotateAPIKey)
+		meGroup.POST("/apikeys/:id/extend", manageKeys, tenantHandler.ExtendAPIKey)
+		meGroup.GET("/apikeys/:id", readKeys, tenantHandler.GetAPIKey)
+		meGroup.PATCH("/apikeys/:id", manageKeys, tenantHandler.UpdateAPIKey) // Restrições: IPs/CIDRs, Origin/Referer e User-Agent
+		meGroup.DELETE("/apikeys/:id", manageKeys, tenantHandler.DeleteAPIKey)
+
+		// Usuários do tenant (owner, admin, developer, billing, viewer)
+		meGroup.GET("/users", tenantUsersHandler.ListUsers)
+		meGroup.GET("/users/invitations", manageUsers, tenantUsersHandler.ListInvitations)
+		meGroup.POST("/users/invitations", manageUsers, tenantUsersHandler.InviteUser)
+		meGroup.DELETE("/users/invitations/:id", manageUsers, tenantUsersHandler.RevokeInvitation)
+		meGroup.PATCH("/users/:id", manageUsers, tenantUsersHandler.UpdateUser)
+		meGroup.DELETE("/users/:id", manageUsers, tenantUsersHandler.RemoveUser)
+		meGroup.POST("/users/transfer-ownership", auth.RequireTenantRole(users, domain.TenantRoleOwner), tenantUsersHandler.TransferOwnership)
 
 		// Meu uso
 		meGroup.GET("/stats", tenantHandler.GetMyStats)   // Métricas rápidas para dashboard
@@ -358,5 +530,21 @@ func NewRouter(
 		meGroup.GET("/config", tenantHandler.GetMyConfig) // Configurações para docs
 	}
 
+	// 🔐 Rotas de API sem permissão no registro (domain.Permissions) exigem a API inteira
+	if rotas := auth.UnregisteredRoutes(r.Routes()); len(rotas) > 0 {
+		log.Warn().Strs("routes", rotas).Msg("rotas sem permissão registrada (exigem scope da API inteira)")
+	}
+
 	return r
 }
+
+// trustedProxiesFromEnv lê TRUSTED_PROXIES (IPs/CIDRs separados por vírgula); vazio = nenhum proxy confiável
+func trustedProxiesFromEnv() []string {
+	var proxies []string
+	for _, p := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
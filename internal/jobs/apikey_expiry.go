@@ -0,0 +1,132 @@
+package jobs
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/theretech/retech-core/internal/domain"
+	"github.com/theretech/retech-core/internal/notify"
+	"github.com/theretech/retech-core/internal/storage"
+)
+
+// APIKeyExpiryNotifier verifica periodicamente as API keys perto de expirar (ou recém-expiradas),
+// registra o aviso no activity log e notifica o webhook. Cada aviso é enviado uma única vez por validade.
+type APIKeyExpiryNotifier struct {
+	apikeys  *storage.APIKeysRepo
+	activity *storage.ActivityLogsRepo
+	webhook  *notify.Webhook
+	policy   domain.APIKeyExpiryPolicy
+	interval time.Duration
+	log      zerolog.Logger
+}
+
+// NewAPIKeyExpiryNotifier intervalo em APIKEY_EXPIRY_CHECK_MINUTES (padrão: 60; 0 desabilita)
+func NewAPIKeyExpiryNotifier(apikeys *storage.APIKeysRepo, activity *storage.ActivityLogsRepo, webhook *notify.Webhook, log zerolog.Logger) *APIKeyExpiryNotifier {
+	minutes := 60
+	if v, err := strconv.Atoi(os.Getenv("APIKEY_EXPIRY_CHECK_MINUTES")); err == nil && v >= 0 {
+		minutes = v
+	}
+	return &APIKeyExpiryNotifier{
+		apikeys:  apikeys,
+		activity: activity,
+		webhook:  webhook,
+		policy:   domain.APIKeyExpiryPolicyFromEnv(),
+		interval: time.Duration(minutes) * time.Minute,
+		log:      log,
+	}
+}
+
+// Start roda uma verificação imediata e depois a cada intervalo, até o contexto ser cancelado
+func (n *APIKeyExpiryNotifier) Start(ctx context.Context) {
+	if n.interval <= 0 {
+		n.log.Warn().Msg("⏰ Avisos de expiração de API keys desabilitados (APIKEY_EXPIRY_CHECK_MINUTES=0)")
+		return
+	}
+	n.log.Info().Dur("interval", n.interval).Bool("webhook", n.webhook.Enabled()).Msg("⏰ Agendador de expiração de API keys iniciado")
+
+	go func() {
+		ticker := time.NewTicker(n.interval)
+		defer ticker.Stop()
+		for {
+			if err := n.Run(ctx); err != nil {
+				n.log.Error().Err(err).Msg("apikey_expiry_check_error")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run executa uma verificação
+func (n *APIKeyExpiryNotifier) Run(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	expirando, err := n.apikeys.FindExpiringToNotify(ctx, now, now.Add(n.policy.Notice))
+	if err != nil {
+		return err
+	}
+	for _, k := range expirando {
+		n.notificar(ctx, k, domain.ActivityTypeAPIKeyExpiring, "expiringNotifiedAt", now)
+	}
+
+	// Expiradas recentemente (janela do aviso, para não notificar keys vencidas há muito tempo)
+	expiradas, err := n.apikeys.FindExpiredToNotify(ctx, now.Add(-n.policy.Notice), now)
+	if err != nil {
+		return err
+	}
+	for _, k := range expiradas {
+		n.notificar(ctx, k, domain.ActivityTypeAPIKeyExpired, "expiredNotifiedAt", now)
+	}
+
+	if len(expirando)+len(expiradas) > 0 {
+		n.log.Info().Int("expiring", len(expirando)).Int("expired", len(expiradas)).Msg("⏰ Avisos de expiração de API keys processados")
+	}
+	return nil
+}
+
+// notificar envia o webhook, grava o activity log e marca o aviso como enviado.
+// Se o webhook falhar, nada é marcado e o aviso é repetido na próxima verificação.
+func (n *APIKeyExpiryNotifier) notificar(ctx context.Context, k domain.APIKey, evento, campo string, now time.Time) {
+	dados := map[string]interface{}{
+		"tenantId":   k.OwnerID,
+		"keyId":      k.KeyID,
+		"expiresAt":  k.ExpiresAt,
+		"graceUntil": k.GraceUntil(n.policy),
+		"daysLeft":   int(k.ExpiresAt.Sub(now).Hours() / 24),
+	}
+
+	if err := n.webhook.Send(ctx, evento, dados); err != nil {
+		n.log.Warn().Err(err).Str("keyId", k.KeyID).Str("event", evento).Msg("apikey_expiry_webhook_error")
+		return
+	}
+
+	dados["webhook"] = n.webhook.Enabled()
+	if err := n.activity.Log(ctx, &domain.ActivityLog{
+		Timestamp: now,
+		Type:      evento,
+		Action:    domain.ActionNotify,
+		Actor: domain.Actor{
+			UserID: "system",
+			Name:   "Agendador de expiração de API keys",
+			Role:   "SYSTEM",
+		},
+		Resource: domain.Resource{
+			Type: domain.ResourceTypeAPIKey,
+			ID:   k.KeyID,
+			Name: k.OwnerID,
+		},
+		Metadata: dados,
+	}); err != nil {
+		n.log.Warn().Err(err).Str("keyId", k.KeyID).Msg("apikey_expiry_activity_error")
+	}
+
+	if err := n.apikeys.MarkNotified(ctx, k.KeyID, campo, now); err != nil {
+		n.log.Warn().Err(err).Str("keyId", k.KeyID).Msg("apikey_expiry_mark_error")
+	}
+}
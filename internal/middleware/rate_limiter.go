@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/theretech/retech-core/internal/auth"
 	"github.com/theretech/retech-core/internal/domain"
 	"github.com/theretech/retech-core/internal/storage"
 	"go.mongodb.org/mongo-driver/bson"
@@ -56,8 +57,13 @@ func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 
 		fmt.Printf("🔑 [RATE LIMITER] API Key: %s... | Tenant: %s\n", apiKey[:20], tenantID)
 
-		// Buscar configuração de rate limit para o tenant
-		config := rl.getRateLimitConfig(tenantID)
+		// Configuração de rate limit: do principal resolvido pelo AuthAPIKey (cacheado) ou do banco
+		var config domain.RateLimitConfig
+		if p := auth.GetPrincipal(c); p != nil {
+			config = p.RateLimit
+		} else {
+			config = rl.getRateLimitConfig(tenantID)
+		}
 
 		fmt.Printf("🔍 Rate Limit Config para tenant %s: %d/dia, %d/min\n", tenantID, config.RequestsPerDay, config.RequestsPerMinute)
 
@@ -0,0 +1,83 @@
+package notify
+
+import (
+	"fmt"
+	"time"
+)
+
+// PasswordResetEmail link (ou token) para redefinir a senha
+func PasswordResetEmail(to, name, link string, ttl time.Duration) Email {
+	return Email{
+		To:      to,
+		Subject: "Redefinição de senha - Retech Core",
+		Body: fmt.Sprintf(`Olá, %s!
+
+Recebemos um pedido para redefinir a senha da sua conta no Retech Core.
+Para criar uma nova senha, acesse:
+
+%s
+
+O link vale por %s e só pode ser usado uma vez.
+Se você não fez este pedido, ignore este email: sua senha continua a mesma.
+`, name, link, formatTTL(ttl)),
+	}
+}
+
+// EmailVerificationEmail link (ou token) para confirmar o email da conta
+func EmailVerificationEmail(to, name, link string, ttl time.Duration) Email {
+	return Email{
+		To:      to,
+		Subject: "Confirme seu email - Retech Core",
+		Body: fmt.Sprintf(`Olá, %s!
+
+Confirme o email da sua conta no Retech Core acessando:
+
+%s
+
+O link vale por %s e só pode ser usado uma vez.
+`, name, link, formatTTL(ttl)),
+	}
+}
+
+// InvitationEmail convite para entrar no tenant com um papel
+func InvitationEmail(to, inviter, role, link string, ttl time.Duration) Email {
+	return Email{
+		To:      to,
+		Subject: "Você foi convidado para o Retech Core",
+		Body: fmt.Sprintf(`Olá!
+
+%s convidou você para acessar o Retech Core com o papel %s.
+Para aceitar o convite e criar seu acesso, acesse:
+
+%s
+
+O convite vale por %s e só pode ser usado uma vez.
+Se você não esperava este convite, ignore este email.
+`, inviter, role, link, formatTTL(ttl)),
+	}
+}
+
+// PasswordChangedEmail aviso de senha alterada (troca ou redefinição)
+func PasswordChangedEmail(to, name string, at time.Time) Email {
+	return Email{
+		To:      to,
+		Subject: "Sua senha foi alterada - Retech Core",
+		Body: fmt.Sprintf(`Olá, %s!
+
+A senha da sua conta no Retech Core foi alterada em %s (UTC).
+As sessões abertas antes da alteração foram encerradas.
+
+Se não foi você, redefina sua senha imediatamente e fale com o suporte.
+`, name, at.UTC().Format("02/01/2006 15:04")),
+	}
+}
+
+func formatTTL(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		if h := int(d / time.Hour); h > 1 {
+			return fmt.Sprintf("%d horas", h)
+		}
+		return "1 hora"
+	}
+	return fmt.Sprintf("%d minutos", int(d/time.Minute))
+}
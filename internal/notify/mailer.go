@@ -0,0 +1,189 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Email mensagem de texto simples
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// smtpTimeout prazo máximo de uma conversa SMTP (conexão + envio) quando o contexto não define um menor
+const smtpTimeout = 30 * time.Second
+
+// Mailer envio de emails transacionais (redefinição de senha, verificação de email, avisos)
+type Mailer interface {
+	Send(ctx context.Context, msg Email) error
+}
+
+// NewMailerFromEnv SMTP quando SMTP_HOST está definido; caso contrário, LogMailer
+// (registra destinatário/assunto no log e grava o email em MAIL_OUTBOX_DIR, se definido) para desenvolvimento e testes
+func NewMailerFromEnv(log zerolog.Logger) Mailer {
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "Retech Core <no-reply@retech-core.local>"
+	}
+
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+	}
+
+	log.Warn().Msg("SMTP_HOST não definido: emails não serão enviados (LogMailer; conteúdo apenas em MAIL_OUTBOX_DIR)")
+	return NewLogMailer(os.Getenv("MAIL_OUTBOX_DIR"), from, log)
+}
+
+// SMTPMailer envia via SMTP (STARTTLS quando o servidor oferece; autenticação PLAIN se houver usuário)
+type SMTPMailer struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	m := &SMTPMailer{
+		addr: net.JoinHostPort(host, port),
+		host: host,
+		from: from,
+	}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+// Send respeita o contexto: conexão com DialContext, prazo na conexão e cancelamento interrompe a conversa
+func (m *SMTPMailer) Send(ctx context.Context, msg Email) error {
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(smtpTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() {
+		_ = conn.SetDeadline(time.Now()) // Desbloqueia leituras/escritas pendentes
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: servidor não oferece AUTH")
+		}
+		if err := c.Auth(m.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(envelopeAddress(m.from)); err != nil {
+		return err
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(buildMessage(m.from, msg)); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// LogMailer não envia: registra destinatário e assunto no log (o corpo leva tokens e não vai para o log)
+// e, com dir, grava um .eml por mensagem
+type LogMailer struct {
+	dir  string
+	from string
+	log  zerolog.Logger
+}
+
+func NewLogMailer(dir, from string, log zerolog.Logger) *LogMailer {
+	return &LogMailer{dir: dir, from: from, log: log}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Email) error {
+	m.log.Info().Str("to", msg.To).Str("subject", msg.Subject).Bool("outbox", m.dir != "").Msg("📧 [mail] email não enviado (LogMailer)")
+	if m.dir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), sanitizeFileName(msg.To))
+	return os.WriteFile(filepath.Join(m.dir, name), buildMessage(m.from, msg), 0o644)
+}
+
+func buildMessage(from string, msg Email) []byte {
+	var sb strings.Builder
+	sb.WriteString("From: " + from + "\r\n")
+	sb.WriteString("To: " + msg.To + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	sb.WriteString("Date: " + time.Now().UTC().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(sb.String())
+}
+
+// envelopeAddress extrai o endereço de "Nome <email>"
+func envelopeAddress(from string) string {
+	if i, j := strings.LastIndex(from, "<"), strings.LastIndex(from, ">"); i >= 0 && j > i {
+		return from[i+1 : j]
+	}
+	return from
+}
+
+func sanitizeFileName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '@' || r == '.' || r == '-' || r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, s)
+}
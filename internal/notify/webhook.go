@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Webhook envia eventos por POST JSON (assinados com HMAC-SHA256 quando há secret)
+type Webhook struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// WebhookEvent corpo enviado ao webhook
+type WebhookEvent struct {
+	Event     string      `json:"event"` // apikey.expiring, apikey.expired, ...
+	Timestamp time.Time   `json:"timestamp"`
+	Data      interface{} `json:"data"`
+}
+
+func NewWebhook(url, secret string) *Webhook {
+	return &Webhook{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewWebhookFromEnv usa NOTIFY_WEBHOOK_URL e NOTIFY_WEBHOOK_SECRET (sem URL, os envios são ignorados)
+func NewWebhookFromEnv() *Webhook {
+	return NewWebhook(os.Getenv("NOTIFY_WEBHOOK_URL"), os.Getenv("NOTIFY_WEBHOOK_SECRET"))
+}
+
+// Enabled indica se há URL configurada
+func (w *Webhook) Enabled() bool {
+	return w != nil && w.url != ""
+}
+
+// Send publica o evento; respostas fora de 2xx são erro (o chamador decide se tenta de novo)
+func (w *Webhook) Send(ctx context.Context, event string, data interface{}) error {
+	if !w.Enabled() {
+		return nil
+	}
+
+	body, err := json.Marshal(WebhookEvent{Event: event, Timestamp: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "retech-core-webhook/1.0")
+	req.Header.Set("X-Retech-Event", event)
+	if w.secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.secret))
+		mac.Write(body)
+		req.Header.Set("X-Retech-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s respondeu %d", event, resp.StatusCode)
+	}
+	return nil
+}
@@ -10,7 +10,21 @@ import (
 	"github.com/theretech/retech-core/internal/domain"
 )
 
-type APIKeysRepo struct{ col *mongo.Collection }
+type APIKeysRepo struct {
+	col      *mongo.Collection
+	onChange []func(ctx context.Context, keyId string)
+}
+
+// OnChange registra um callback chamado após revogar/estender/alterar restrições de uma key (ex: invalidar cache)
+func (r *APIKeysRepo) OnChange(fn func(ctx context.Context, keyId string)) {
+	r.onChange = append(r.onChange, fn)
+}
+
+func (r *APIKeysRepo) changed(ctx context.Context, keyId string) {
+	for _, fn := range r.onChange {
+		fn(ctx, keyId)
+	}
+}
 
 func NewAPIKeysRepo(db *mongo.Database) *APIKeysRepo {
 	return &APIKeysRepo{col: db.Collection("api_keys")}
@@ -24,6 +38,9 @@ func (r *APIKeysRepo) Insert(ctx context.Context, k *domain.APIKey) error {
 
 func (r *APIKeysRepo) Revoke(ctx context.Context, keyId string) error {
 	_, err := r.col.UpdateOne(ctx, bson.M{"keyId": keyId}, bson.M{"$set": bson.M{"revoked": true}})
+	if err == nil {
+		r.changed(ctx, keyId)
+	}
 	return err
 }
 
@@ -48,3 +65,79 @@ func (r *APIKeysRepo) CountByOwner(ctx context.Context, ownerId string) (int64,
 		"revoked": false,
 	})
 }
+
+// FindExpiringToNotify keys ativas que expiram até 'until' e ainda não receberam o aviso "expira em breve"
+func (r *APIKeysRepo) FindExpiringToNotify(ctx context.Context, now, until time.Time) ([]domain.APIKey, error) {
+	return r.find(ctx, bson.M{
+		"revoked":            false,
+		"expiresAt":          bson.M{"$gt": now, "$lte": until},
+		"expiringNotifiedAt": bson.M{"$exists": false},
+	})
+}
+
+// FindExpiredToNotify keys não revogadas que expiraram desde 'since' e ainda não receberam o aviso de expiração
+func (r *APIKeysRepo) FindExpiredToNotify(ctx context.Context, since, now time.Time) ([]domain.APIKey, error) {
+	return r.find(ctx, bson.M{
+		"revoked":           false,
+		"expiresAt":         bson.M{"$gt": since, "$lte": now},
+		"expiredNotifiedAt": bson.M{"$exists": false},
+	})
+}
+
+// MarkNotified registra o envio de um aviso (campo: expiringNotifiedAt ou expiredNotifiedAt)
+func (r *APIKeysRepo) MarkNotified(ctx context.Context, keyId, campo string, at time.Time) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"keyId": keyId}, bson.M{"$set": bson.M{campo: at}})
+	return err
+}
+
+// Extend altera a validade da key e zera os avisos de expiração já enviados
+func (r *APIKeysRepo) Extend(ctx context.Context, keyId string, expiresAt time.Time) error {
+	_, err := r.col.UpdateOne(ctx, bson.M{"keyId": keyId}, bson.M{
+		"$set":   bson.M{"expiresAt": expiresAt},
+		"$unset": bson.M{"expiringNotifiedAt": "", "expiredNotifiedAt": ""},
+	})
+	if err == nil {
+		r.changed(ctx, keyId)
+	}
+	return err
+}
+
+// KeyIDsByOwner keyIds (inclusive revogadas) de um tenant
+func (r *APIKeysRepo) KeyIDsByOwner(ctx context.Context, ownerId string) ([]string, error) {
+	keys, err := r.find(ctx, bson.M{"ownerId": ownerId})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(keys))
+	for i, k := range keys {
+		ids[i] = k.KeyID
+	}
+	return ids, nil
+}
+
+func (r *APIKeysRepo) find(ctx context.Context, filter bson.M) ([]domain.APIKey, error) {
+	cur, err := r.col.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var keys []domain.APIKey
+	if err := cur.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// UpdateRestrictions grava as restrições de uso da key (nil ou vazias removem as restrições)
+func (r *APIKeysRepo) UpdateRestrictions(ctx context.Context, keyId string, restrictions *domain.APIKeyRestrictions) error {
+	update := bson.M{"$set": bson.M{"restrictions": restrictions}}
+	if restrictions.Empty() {
+		update = bson.M{"$unset": bson.M{"restrictions": ""}}
+	}
+	_, err := r.col.UpdateOne(ctx, bson.M{"keyId": keyId}, update)
+	if err == nil {
+		r.changed(ctx, keyId)
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/theretech/retech-core/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type BancosRepo struct {
+	coll *mongo.Collection
+}
+
+func NewBancosRepo(db *mongo.Database) *BancosRepo {
+	return &BancosRepo{coll: db.Collection("bancos")}
+}
+
+// FindAll retorna todos os bancos ordenados por código
+func (r *BancosRepo) FindAll(ctx context.Context) ([]domain.Banco, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "codigo", Value: 1}})
+	cursor, err := r.coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var bancos []domain.Banco
+	if err := cursor.All(ctx, &bancos); err != nil {
+		return nil, err
+	}
+	return bancos, nil
+}
+
+// FindByCodigo retorna um banco pelo código COMPE (ex: "001")
+func (r *BancosRepo) FindByCodigo(ctx context.Context, codigo string) (*domain.Banco, error) {
+	var banco domain.Banco
+	err := r.coll.FindOne(ctx, bson.M{"codigo": codigo}).Decode(&banco)
+	if err != nil {
+		return nil, err
+	}
+	return &banco, nil
+}
+
+// InsertMany insere múltiplos bancos
+func (r *BancosRepo) InsertMany(ctx context.Context, bancos []domain.Banco) error {
+	if len(bancos) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(bancos))
+	now := time.Now()
+	for i, b := range bancos {
+		b.CreatedAt = now
+		b.UpdatedAt = now
+		docs[i] = b
+	}
+
+	_, err := r.coll.InsertMany(ctx, docs)
+	return err
+}
+
+// Count retorna a quantidade de bancos
+func (r *BancosRepo) Count(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{})
+}
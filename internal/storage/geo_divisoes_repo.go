@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/theretech/retech-core/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GeoDivisoesCollections collection pré-agregada de cada nível da divisão regional
+var GeoDivisoesCollections = map[string]string{
+	domain.GeoNivelRegiao:              "geo_regioes",
+	domain.GeoNivelMesorregiao:         "geo_mesorregioes",
+	domain.GeoNivelMicrorregiao:        "geo_microrregioes",
+	domain.GeoNivelRegiaoIntermediaria: "geo_regioes_intermediarias",
+	domain.GeoNivelRegiaoImediata:      "geo_regioes_imediatas",
+}
+
+type GeoDivisoesRepo struct {
+	db *mongo.Database
+}
+
+func NewGeoDivisoesRepo(db *mongo.Database) *GeoDivisoesRepo {
+	return &GeoDivisoesRepo{db: db}
+}
+
+func (r *GeoDivisoesRepo) coll(nivel string) (*mongo.Collection, error) {
+	name, ok := GeoDivisoesCollections[nivel]
+	if !ok {
+		return nil, fmt.Errorf("nível geográfico desconhecido: %s", nivel)
+	}
+	return r.db.Collection(name), nil
+}
+
+// FindAll retorna as divisões do nível (opcionalmente de uma UF)
+func (r *GeoDivisoesRepo) FindAll(ctx context.Context, nivel, uf string) ([]domain.GeoDivisao, error) {
+	coll, err := r.coll(nivel)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{}
+	if uf != "" {
+		filter["UF.sigla"] = strings.ToUpper(uf)
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "nome", Value: 1}})
+	cursor, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	divisoes := []domain.GeoDivisao{}
+	if err := cursor.All(ctx, &divisoes); err != nil {
+		return nil, err
+	}
+	return divisoes, nil
+}
+
+// FindByID retorna uma divisão pelo ID do IBGE
+func (r *GeoDivisoesRepo) FindByID(ctx context.Context, nivel string, id int) (*domain.GeoDivisao, error) {
+	coll, err := r.coll(nivel)
+	if err != nil {
+		return nil, err
+	}
+
+	var divisao domain.GeoDivisao
+	if err := coll.FindOne(ctx, bson.M{"id": id}).Decode(&divisao); err != nil {
+		return nil, err
+	}
+	return &divisao, nil
+}
+
+// FindRegiaoBySigla retorna uma região pela sigla ("N", "NE", "CO", "SE", "S")
+func (r *GeoDivisoesRepo) FindRegiaoBySigla(ctx context.Context, sigla string) (*domain.GeoDivisao, error) {
+	coll, err := r.coll(domain.GeoNivelRegiao)
+	if err != nil {
+		return nil, err
+	}
+
+	var divisao domain.GeoDivisao
+	if err := coll.FindOne(ctx, bson.M{"sigla": strings.ToUpper(sigla)}).Decode(&divisao); err != nil {
+		return nil, err
+	}
+	return &divisao, nil
+}
+
+// ReplaceAll regrava as divisões do nível (usado no seed)
+func (r *GeoDivisoesRepo) ReplaceAll(ctx context.Context, nivel string, divisoes []domain.GeoDivisao) error {
+	coll, err := r.coll(nivel)
+	if err != nil {
+		return err
+	}
+
+	if _, err := coll.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	if len(divisoes) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(divisoes))
+	now := time.Now()
+	for i, d := range divisoes {
+		d.UpdatedAt = now
+		docs[i] = d
+	}
+
+	_, err = coll.InsertMany(ctx, docs)
+	return err
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/theretech/retech-core/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type GeoMalhasRepo struct {
+	coll *mongo.Collection
+}
+
+func NewGeoMalhasRepo(db *mongo.Database) *GeoMalhasRepo {
+	return &GeoMalhasRepo{coll: db.Collection("geo_malhas")}
+}
+
+// FindByCodigo retorna a malha de um município/UF apenas com a geometria da qualidade pedida
+func (r *GeoMalhasRepo) FindByCodigo(ctx context.Context, tipo string, codigo int, qualidade string) (*domain.GeoMalha, error) {
+	projection := bson.M{"geometria": 1, "tipo": 1, "codigo": 1, "pontos": 1, "updatedAt": 1}
+	if qualidade != domain.GeoQualidadeMaxima {
+		projection = bson.M{"simplificadas." + qualidade: 1, "tipo": 1, "codigo": 1, "pontos": 1, "updatedAt": 1}
+	}
+
+	var malha domain.GeoMalha
+	err := r.coll.FindOne(ctx, bson.M{"tipo": tipo, "codigo": codigo}, options.FindOne().SetProjection(projection)).Decode(&malha)
+	if err != nil {
+		return nil, err
+	}
+	return &malha, nil
+}
+
+// FindContaining retorna o código da malha do tipo que contém o ponto (geometria original, índice 2dsphere)
+func (r *GeoMalhasRepo) FindContaining(ctx context.Context, tipo string, lng, lat float64) (int, error) {
+	filter := bson.M{
+		"tipo": tipo,
+		"geometria": bson.M{"$geoIntersects": bson.M{
+			"$geometry": bson.M{"type": "Point", "coordinates": bson.A{lng, lat}},
+		}},
+	}
+
+	var malha domain.GeoMalha
+	opts := options.FindOne().SetProjection(bson.M{"codigo": 1})
+	if err := r.coll.FindOne(ctx, filter, opts).Decode(&malha); err != nil {
+		return 0, err
+	}
+	return malha.Codigo, nil
+}
+
+// Upsert grava a malha (usado no seed)
+func (r *GeoMalhasRepo) Upsert(ctx context.Context, malha domain.GeoMalha) error {
+	malha.UpdatedAt = time.Now()
+	_, err := r.coll.ReplaceOne(ctx, bson.M{"tipo": malha.Tipo, "codigo": malha.Codigo}, malha, options.Replace().SetUpsert(true))
+	return err
+}
+
+// Count retorna a quantidade de malhas do tipo
+func (r *GeoMalhasRepo) Count(ctx context.Context, tipo string) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{"tipo": tipo})
+}
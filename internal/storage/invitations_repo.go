@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/theretech/retech-core/internal/domain"
+)
+
+type InvitationsRepo struct {
+	col *mongo.Collection
+}
+
+func NewInvitationsRepo(db *mongo.Database) *InvitationsRepo {
+	return &InvitationsRepo{col: db.Collection("tenant_invitations")}
+}
+
+func (r *InvitationsRepo) Insert(ctx context.Context, inv *domain.Invitation) error {
+	inv.ID = ""
+	result, err := r.col.InsertOne(ctx, inv)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		inv.ID = oid.Hex()
+	}
+	return nil
+}
+
+// ByTokenHash convite pelo hash do token (nil se não existe)
+func (r *InvitationsRepo) ByTokenHash(ctx context.Context, tokenHash string) (*domain.Invitation, error) {
+	return r.findOne(ctx, bson.M{"tokenHash": tokenHash})
+}
+
+// ByID convite do tenant (nil se não existe ou é de outro tenant)
+func (r *InvitationsRepo) ByID(ctx context.Context, tenantID, id string) (*domain.Invitation, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+	return r.findOne(ctx, bson.M{"_id": oid, "tenantId": tenantID})
+}
+
+// ListOpen convites do tenant ainda não aceitos nem revogados (inclui expirados), mais recentes primeiro
+func (r *InvitationsRepo) ListOpen(ctx context.Context, tenantID string) ([]domain.Invitation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.col.Find(ctx, bson.M{
+		"tenantId":   tenantID,
+		"acceptedAt": bson.M{"$exists": false},
+		"revokedAt":  bson.M{"$exists": false},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	out := []domain.Invitation{}
+	if err := cursor.All(ctx, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Revoke revoga um convite em aberto (false se não existe ou já foi aceito/revogado)
+func (r *InvitationsRepo) Revoke(ctx context.Context, tenantID, id string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+	res, err := r.col.UpdateOne(ctx, bson.M{
+		"_id":        oid,
+		"tenantId":   tenantID,
+		"acceptedAt": bson.M{"$exists": false},
+		"revokedAt":  bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+// RevokeOpenByEmail revoga os convites em aberto do email no tenant (reenvio substitui o convite anterior)
+func (r *InvitationsRepo) RevokeOpenByEmail(ctx context.Context, tenantID, email string) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{
+		"tenantId":   tenantID,
+		"email":      email,
+		"acceptedAt": bson.M{"$exists": false},
+		"revokedAt":  bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"revokedAt": time.Now().UTC()}})
+	return err
+}
+
+// Accept marca o convite como aceito de forma atômica (uso único: false se já aceito, revogado ou expirado)
+func (r *InvitationsRepo) Accept(ctx context.Context, id, userID string) (bool, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, nil
+	}
+	now := time.Now().UTC()
+	res, err := r.col.UpdateOne(ctx, bson.M{
+		"_id":        oid,
+		"acceptedAt": bson.M{"$exists": false},
+		"revokedAt":  bson.M{"$exists": false},
+		"expiresAt":  bson.M{"$gt": now},
+	}, bson.M{"$set": bson.M{"acceptedAt": now, "userId": userID}})
+	if err != nil {
+		return false, err
+	}
+	return res.ModifiedCount > 0, nil
+}
+
+func (r *InvitationsRepo) findOne(ctx context.Context, filter bson.M) (*domain.Invitation, error) {
+	var inv domain.Invitation
+	err := r.col.FindOne(ctx, filter).Decode(&inv)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &inv, nil
+}
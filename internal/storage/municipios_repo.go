@@ -2,6 +2,8 @@ package storage
 
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -12,11 +14,19 @@ import (
 )
 
 type MunicipiosRepo struct {
-	coll *mongo.Collection
+	coll  *mongo.Collection
+	dados *mongo.Collection // Dados complementares (população, área, SIAFI, TSE, DDD...)
+
+	alteracoes *mongo.Collection // Histórico da divisão municipal (criações, renomeações, incorporações)
 }
 
 func NewMunicipiosRepo(db *mongo.Database) *MunicipiosRepo {
-	return &MunicipiosRepo{coll: db.Collection("municipios")}
+	return &MunicipiosRepo{
+		coll:  db.Collection("municipios"),
+		dados: db.Collection("municipios_dados"),
+
+		alteracoes: db.Collection("municipios_alteracoes"),
+	}
 }
 
 // FindAll retorna todos os municípios
@@ -64,10 +74,97 @@ func (r *MunicipiosRepo) FindByID(ctx context.Context, id int) (*domain.Municipi
 	return &municipio, nil
 }
 
-// Search busca municípios por nome (case-insensitive, parcial)
+// FindByDivisao retorna os municípios de uma divisão regional (região, meso/microrregião, região intermediária/imediata)
+func (r *MunicipiosRepo) FindByDivisao(ctx context.Context, nivel string, id int) ([]domain.Municipio, error) {
+	campo, ok := domain.GeoCampoMunicipio[nivel]
+	if !ok {
+		return nil, fmt.Errorf("nível geográfico desconhecido: %s", nivel)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "nome", Value: 1}})
+	cursor, err := r.coll.Find(ctx, bson.M{campo: id}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	municipios := []domain.Municipio{}
+	if err := cursor.All(ctx, &municipios); err != nil {
+		return nil, err
+	}
+	return municipios, nil
+}
+
+// FindPage lista municípios com filtros, ordenação e paginação por cursor.
+// Retorna hasMore = true quando existem itens após a página.
+func (r *MunicipiosRepo) FindPage(ctx context.Context, q domain.MunicipioQuery) ([]domain.Municipio, bool, error) {
+	filter := bson.M{}
+	if q.UF != "" {
+		filter["microrregiao.mesorregiao.UF.sigla"] = strings.ToUpper(q.UF)
+	}
+	if q.Q != "" {
+		filter["nome"] = bson.M{"$regex": regexp.QuoteMeta(q.Q), "$options": "i"}
+	}
+
+	// Ordenação sempre com id como desempate (cursor estável)
+	dir, op := 1, "$gt"
+	if strings.HasPrefix(q.Sort, "-") {
+		dir, op = -1, "$lt"
+	}
+	sort := bson.D{{Key: "nome", Value: dir}, {Key: "id", Value: dir}}
+	if strings.TrimPrefix(q.Sort, "-") == "id" {
+		sort = bson.D{{Key: "id", Value: dir}}
+	}
+
+	if q.Cursor != nil {
+		if strings.TrimPrefix(q.Sort, "-") == "id" {
+			filter["id"] = bson.M{op: q.Cursor.ID}
+		} else {
+			filter["$or"] = bson.A{
+				bson.M{"nome": bson.M{op: q.Cursor.Nome}},
+				bson.M{"nome": q.Cursor.Nome, "id": bson.M{op: q.Cursor.ID}},
+			}
+		}
+	}
+
+	opts := options.Find().SetSort(sort)
+	if q.Limit > 0 {
+		opts.SetLimit(int64(q.Limit + 1)) // +1 para saber se há próxima página
+	}
+
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	defer cursor.Close(ctx)
+
+	municipios := []domain.Municipio{}
+	if err := cursor.All(ctx, &municipios); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := q.Limit > 0 && len(municipios) > q.Limit
+	if hasMore {
+		municipios = municipios[:q.Limit]
+	}
+	return municipios, hasMore, nil
+}
+
+// FindByCodigoSemDV busca pelo código de 6 dígitos (sem o dígito verificador, usado em bases antigas)
+func (r *MunicipiosRepo) FindByCodigoSemDV(ctx context.Context, codigo int) (*domain.Municipio, error) {
+	filter := bson.M{"id": bson.M{"$gte": codigo * 10, "$lte": codigo*10 + 9}}
+	var municipio domain.Municipio
+	if err := r.coll.FindOne(ctx, filter).Decode(&municipio); err != nil {
+		return nil, err
+	}
+	return &municipio, nil
+}
+
+// Search busca municípios por nome (case-insensitive, parcial; o texto é tratado como literal).
+// Para nomes sem acento, abreviados ou com erros de digitação use o resolver (/geo/municipios/resolver).
 func (r *MunicipiosRepo) Search(ctx context.Context, query string, uf string) ([]domain.Municipio, error) {
 	filter := bson.M{
-		"nome": bson.M{"$regex": query, "$options": "i"},
+		"nome": bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"},
 	}
 	
 	if uf != "" {
@@ -125,9 +222,102 @@ func (r *MunicipiosRepo) Count(ctx context.Context) (int64, error) {
 	return r.coll.CountDocuments(ctx, bson.M{})
 }
 
+// LastUpdatedAt retorna o maior updatedAt dos municípios (zero se a coleção estiver vazia)
+func (r *MunicipiosRepo) LastUpdatedAt(ctx context.Context) (time.Time, error) {
+	var last struct {
+		UpdatedAt time.Time `bson:"updatedAt"`
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "updatedAt", Value: -1}}).SetProjection(bson.M{"updatedAt": 1})
+	if err := r.coll.FindOne(ctx, bson.M{}, opts).Decode(&last); err != nil && err != mongo.ErrNoDocuments {
+		return time.Time{}, err
+	}
+	return last.UpdatedAt, nil
+}
+
 // DeleteAll remove todos os municípios (usado para re-seed)
 func (r *MunicipiosRepo) DeleteAll(ctx context.Context) error {
 	_, err := r.coll.DeleteMany(ctx, bson.M{})
 	return err
 }
 
+// FindDados retorna os dados complementares de um município
+func (r *MunicipiosRepo) FindDados(ctx context.Context, id int) (*domain.MunicipioDados, error) {
+	var dados domain.MunicipioDados
+	if err := r.dados.FindOne(ctx, bson.M{"id": id}).Decode(&dados); err != nil {
+		return nil, err
+	}
+	return &dados, nil
+}
+
+// FindDadosByCodigo busca os dados complementares por código alternativo ("siafi" ou "tse")
+func (r *MunicipiosRepo) FindDadosByCodigo(ctx context.Context, campo, codigo string) (*domain.MunicipioDados, error) {
+	if campo != "siafi" && campo != "tse" {
+		return nil, fmt.Errorf("código alternativo desconhecido: %s", campo)
+	}
+
+	var dados domain.MunicipioDados
+	if err := r.dados.FindOne(ctx, bson.M{campo: codigo}).Decode(&dados); err != nil {
+		return nil, err
+	}
+	return &dados, nil
+}
+
+// CountDados retorna a quantidade de municípios com dados complementares
+func (r *MunicipiosRepo) CountDados(ctx context.Context) (int64, error) {
+	return r.dados.CountDocuments(ctx, bson.M{})
+}
+
+// UpsertDados grava os dados complementares (usado no seed)
+func (r *MunicipiosRepo) UpsertDados(ctx context.Context, dados []domain.MunicipioDados) error {
+	if len(dados) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(dados))
+	for _, d := range dados {
+		d.UpdatedAt = now
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"id": d.ID}).
+			SetReplacement(d).
+			SetUpsert(true))
+	}
+
+	_, err := r.dados.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// FindAlteracoes retorna todo o histórico de alterações (coleção pequena, ordenada por vigência)
+func (r *MunicipiosRepo) FindAlteracoes(ctx context.Context) ([]domain.MunicipioAlteracao, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "data", Value: 1}, {Key: "municipio", Value: 1}})
+	cursor, err := r.alteracoes.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	alteracoes := []domain.MunicipioAlteracao{}
+	if err := cursor.All(ctx, &alteracoes); err != nil {
+		return nil, err
+	}
+	return alteracoes, nil
+}
+
+// ReplaceAlteracoes substitui o histórico de alterações (usado no seed)
+func (r *MunicipiosRepo) ReplaceAlteracoes(ctx context.Context, alteracoes []domain.MunicipioAlteracao) error {
+	if _, err := r.alteracoes.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	if len(alteracoes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	docs := make([]interface{}, len(alteracoes))
+	for i, a := range alteracoes {
+		a.UpdatedAt = now
+		docs[i] = a
+	}
+	_, err := r.alteracoes.InsertMany(ctx, docs)
+	return err
+}
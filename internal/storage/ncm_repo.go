@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/theretech/retech-core/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type NCMRepo struct {
+	coll *mongo.Collection
+	cest *mongo.Collection
+}
+
+func NewNCMRepo(db *mongo.Database) *NCMRepo {
+	return &NCMRepo{
+		coll: db.Collection("ncm"),
+		cest: db.Collection("cest"),
+	}
+}
+
+// FindByCodigo retorna um NCM pelo código (apenas dígitos)
+func (r *NCMRepo) FindByCodigo(ctx context.Context, codigo string) (*domain.NCM, error) {
+	var ncm domain.NCM
+	err := r.coll.FindOne(ctx, bson.M{"codigo": codigo}).Decode(&ncm)
+	if err != nil {
+		return nil, err
+	}
+	return &ncm, nil
+}
+
+// FindFilhos retorna os códigos imediatamente abaixo na hierarquia
+func (r *NCMRepo) FindFilhos(ctx context.Context, codigo string) ([]domain.NCM, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "codigo", Value: 1}}).
+		SetProjection(bson.M{"hierarquia": 0})
+	cursor, err := r.coll.Find(ctx, bson.M{"codigoPai": codigo}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	filhos := []domain.NCM{}
+	if err := cursor.All(ctx, &filhos); err != nil {
+		return nil, err
+	}
+	return filhos, nil
+}
+
+// Search busca NCMs cujo campo busca contém todos os termos (já normalizados)
+func (r *NCMRepo) Search(ctx context.Context, termos []string, nivel string, limit, skip int64) ([]domain.NCM, int64, error) {
+	and := bson.A{}
+	for _, termo := range termos {
+		and = append(and, bson.M{"busca": bson.M{"$regex": regexp.QuoteMeta(termo)}})
+	}
+	filter := bson.M{}
+	if len(and) > 0 {
+		filter["$and"] = and
+	}
+	if nivel != "" {
+		filter["nivel"] = nivel
+	}
+
+	total, err := r.coll.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "codigo", Value: 1}}).
+		SetLimit(limit).
+		SetSkip(skip)
+	cursor, err := r.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	ncms := []domain.NCM{}
+	if err := cursor.All(ctx, &ncms); err != nil {
+		return nil, 0, err
+	}
+	return ncms, total, nil
+}
+
+// FindCESTByNCM retorna os CESTs aplicáveis a um NCM (o CEST pode estar vinculado a capítulo/posição)
+func (r *NCMRepo) FindCESTByNCM(ctx context.Context, codigo string) ([]domain.CEST, error) {
+	prefixos := bson.A{}
+	for size := 2; size <= len(codigo); size++ {
+		prefixos = append(prefixos, codigo[:size])
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "cest", Value: 1}})
+	cursor, err := r.cest.Find(ctx, bson.M{"ncm": bson.M{"$in": prefixos}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	cests := []domain.CEST{}
+	if err := cursor.All(ctx, &cests); err != nil {
+		return nil, err
+	}
+	return cests, nil
+}
+
+// UpsertMany insere/atualiza NCMs pelo código e remove os que não estão mais na tabela
+func (r *NCMRepo) UpsertMany(ctx context.Context, ncms []domain.NCM) (inserted, updated, removed int64, err error) {
+	if len(ncms) == 0 {
+		return 0, 0, 0, nil
+	}
+
+	now := time.Now()
+	codigos := make([]string, 0, len(ncms))
+	models := make([]mongo.WriteModel, 0, len(ncms))
+	for _, ncm := range ncms {
+		codigos = append(codigos, ncm.Codigo)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"codigo": ncm.Codigo}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"codigoFormatado": ncm.CodigoFormatado,
+					"descricao":       ncm.Descricao,
+					"nivel":           ncm.Nivel,
+					"codigoPai":       ncm.CodigoPai,
+					"hierarquia":      ncm.Hierarquia,
+					"dataInicio":      ncm.DataInicio,
+					"dataFim":         ncm.DataFim,
+					"atoLegal":        ncm.AtoLegal,
+					"busca":           ncm.Busca,
+					"updatedAt":       now,
+				},
+				"$setOnInsert": bson.M{
+					"createdAt": now,
+				},
+			}).
+			SetUpsert(true))
+	}
+
+	result, err := r.coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	deleted, err := r.coll.DeleteMany(ctx, bson.M{"codigo": bson.M{"$nin": codigos}})
+	if err != nil {
+		return result.UpsertedCount, result.ModifiedCount, 0, err
+	}
+
+	return result.UpsertedCount, result.ModifiedCount, deleted.DeletedCount, nil
+}
+
+// ReplaceCEST substitui toda a tabela CEST
+func (r *NCMRepo) ReplaceCEST(ctx context.Context, cests []domain.CEST) error {
+	if _, err := r.cest.DeleteMany(ctx, bson.M{}); err != nil {
+		return err
+	}
+	if len(cests) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(cests))
+	for i, c := range cests {
+		c.NCM = domain.NormalizeNCM(c.NCM)
+		docs[i] = c
+	}
+
+	_, err := r.cest.InsertMany(ctx, docs)
+	return err
+}
+
+// Count retorna a quantidade de NCMs
+func (r *NCMRepo) Count(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{})
+}
+
+// CountCEST retorna a quantidade de CESTs
+func (r *NCMRepo) CountCEST(ctx context.Context) (int64, error) {
+	return r.cest.CountDocuments(ctx, bson.M{})
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/theretech/retech-core/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type PlacasFaixasRepo struct {
+	coll *mongo.Collection
+}
+
+func NewPlacasFaixasRepo(db *mongo.Database) *PlacasFaixasRepo {
+	return &PlacasFaixasRepo{coll: db.Collection("placas_faixas")}
+}
+
+// FindByPrefixo retorna a faixa que contém o prefixo de 3 letras (ex: "ABC")
+func (r *PlacasFaixasRepo) FindByPrefixo(ctx context.Context, prefixo string) (*domain.PlacaFaixa, error) {
+	// Maior início <= prefixo; depois confere se o prefixo não passa do fim
+	opts := options.FindOne().SetSort(bson.D{{Key: "inicio", Value: -1}})
+	var faixa domain.PlacaFaixa
+	err := r.coll.FindOne(ctx, bson.M{"inicio": bson.M{"$lte": prefixo}}, opts).Decode(&faixa)
+	if err != nil {
+		return nil, err
+	}
+	if prefixo > faixa.Fim {
+		return nil, mongo.ErrNoDocuments
+	}
+	return &faixa, nil
+}
+
+// InsertMany insere múltiplas faixas
+func (r *PlacasFaixasRepo) InsertMany(ctx context.Context, faixas []domain.PlacaFaixa) error {
+	if len(faixas) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, len(faixas))
+	for i, f := range faixas {
+		docs[i] = f
+	}
+
+	_, err := r.coll.InsertMany(ctx, docs)
+	return err
+}
+
+// Count retorna a quantidade de faixas
+func (r *PlacasFaixasRepo) Count(ctx context.Context) (int64, error) {
+	return r.coll.CountDocuments(ctx, bson.M{})
+}
@@ -15,7 +15,19 @@ const (
 )
 
 type SettingsRepo struct {
-	col *mongo.Collection
+	col      *mongo.Collection
+	onChange []func(ctx context.Context)
+}
+
+// OnChange registra um callback chamado após atualizar as configurações (ex: invalidar cache)
+func (r *SettingsRepo) OnChange(fn func(ctx context.Context)) {
+	r.onChange = append(r.onChange, fn)
+}
+
+func (r *SettingsRepo) changed(ctx context.Context) {
+	for _, fn := range r.onChange {
+		fn(ctx)
+	}
 }
 
 func NewSettingsRepo(db *mongo.Database) *SettingsRepo {
@@ -69,6 +81,7 @@ func (r *SettingsRepo) Update(ctx context.Context, settings *domain.SystemSettin
 			fmt.Printf("❌ Erro ao inserir: %v\n", err)
 		} else {
 			fmt.Println("✅ Documento criado com sucesso!")
+			r.changed(ctx)
 		}
 		return err
 	}
@@ -101,6 +114,7 @@ func (r *SettingsRepo) Update(ctx context.Context, settings *domain.SystemSettin
 		fmt.Printf("❌ Erro ao atualizar: %v\n", err)
 	} else {
 		fmt.Println("✅ Documento atualizado com sucesso!")
+		r.changed(ctx)
 	}
 
 	return err
@@ -12,7 +12,19 @@ import (
 )
 
 type TenantsRepo struct {
-	col *mongo.Collection
+	col      *mongo.Collection
+	onChange []func(ctx context.Context, tenantID string)
+}
+
+// OnChange registra um callback chamado após atualizar/remover um tenant (ex: invalidar cache)
+func (r *TenantsRepo) OnChange(fn func(ctx context.Context, tenantID string)) {
+	r.onChange = append(r.onChange, fn)
+}
+
+func (r *TenantsRepo) changed(ctx context.Context, tenantID string) {
+	for _, fn := range r.onChange {
+		fn(ctx, tenantID)
+	}
 }
 
 func NewTenantsRepo(db *mongo.Database) *TenantsRepo {
@@ -69,6 +81,9 @@ func (r *TenantsRepo) Update(ctx context.Context, tenantID string, updates map[s
 		bson.M{"tenantId": tenantID},
 		bson.M{"$set": updates},
 	)
+	if err == nil {
+		r.changed(ctx, tenantID)
+	}
 	return err
 }
 
@@ -78,6 +93,7 @@ func (r *TenantsRepo) Delete(ctx context.Context, idOrTenantID string) error {
 	if err != nil {
 		return err
 	}
+	defer r.changed(ctx, idOrTenantID)
 
 	// Se não deletou nada (tenantId vazio ou não encontrado), tentar por _id
 	if result.DeletedCount == 0 {
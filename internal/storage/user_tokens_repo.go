@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/theretech/retech-core/internal/domain"
+)
+
+// UserTokensRepo tokens de uso único (redefinição de senha, verificação de email)
+type UserTokensRepo struct {
+	col *mongo.Collection
+}
+
+func NewUserTokensRepo(db *mongo.Database) *UserTokensRepo {
+	return &UserTokensRepo{col: db.Collection("user_tokens")}
+}
+
+// Issue invalida os tokens ainda não usados do usuário para a finalidade e grava o novo
+func (r *UserTokensRepo) Issue(ctx context.Context, t *domain.UserToken) error {
+	if err := r.InvalidateAll(ctx, t.UserID, t.Purpose); err != nil {
+		return err
+	}
+
+	t.ID = ""
+	result, err := r.col.InsertOne(ctx, t)
+	if err != nil {
+		return err
+	}
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		t.ID = oid.Hex()
+	}
+	return nil
+}
+
+// Consume marca o token como usado de forma atômica (nil se não existe, já foi usado ou expirou)
+func (r *UserTokensRepo) Consume(ctx context.Context, purpose, tokenHash string) (*domain.UserToken, error) {
+	now := time.Now().UTC()
+	var t domain.UserToken
+	err := r.col.FindOneAndUpdate(ctx, bson.M{
+		"tokenHash": tokenHash,
+		"purpose":   purpose,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}, bson.M{"$set": bson.M{"usedAt": now}}, options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&t)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// InvalidateAll invalida os tokens ainda não usados do usuário para a finalidade
+func (r *UserTokensRepo) InvalidateAll(ctx context.Context, userID, purpose string) error {
+	_, err := r.col.UpdateMany(ctx, bson.M{
+		"userId":  userID,
+		"purpose": purpose,
+		"usedAt":  bson.M{"$exists": false},
+	}, bson.M{"$set": bson.M{"usedAt": time.Now().UTC()}})
+	return err
+}
+
+// IssuedSince indica se já foi emitido token para o usuário/finalidade desde o instante (limita reenvios)
+func (r *UserTokensRepo) IssuedSince(ctx context.Context, userID, purpose string, since time.Time) (bool, error) {
+	n, err := r.col.CountDocuments(ctx, bson.M{
+		"userId":    userID,
+		"purpose":   purpose,
+		"createdAt": bson.M{"$gte": since},
+	}, options.Count().SetLimit(1))
+	return n > 0, err
+}
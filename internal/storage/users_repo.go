@@ -47,6 +47,14 @@ func (r *UsersRepo) Create(ctx context.Context, user *domain.User, password stri
 	return nil
 }
 
+// userIDFilter filtro por _id (ObjectID gerado no insert; IDs em string continuam aceitos)
+func userIDFilter(id string) bson.M {
+	if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+		return bson.M{"_id": oid}
+	}
+	return bson.M{"_id": id}
+}
+
 // FindByEmail busca usuário por email
 func (r *UsersRepo) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
@@ -60,7 +68,7 @@ func (r *UsersRepo) FindByEmail(ctx context.Context, email string) (*domain.User
 // FindByID busca usuário por ID
 func (r *UsersRepo) FindByID(ctx context.Context, id string) (*domain.User, error) {
 	var user domain.User
-	err := r.coll.FindOne(ctx, bson.M{"_id": id}).Decode(&user)
+	err := r.coll.FindOne(ctx, userIDFilter(id)).Decode(&user)
 	if err != nil {
 		return nil, err
 	}
@@ -78,7 +86,7 @@ func (r *UsersRepo) UpdateLastLogin(ctx context.Context, userID string) error {
 	now := time.Now()
 	_, err := r.coll.UpdateOne(
 		ctx,
-		bson.M{"_id": userID},
+		userIDFilter(userID),
 		bson.M{"$set": bson.M{"lastLogin": now, "updatedAt": now}},
 	)
 	return err
@@ -89,7 +97,7 @@ func (r *UsersRepo) Update(ctx context.Context, user *domain.User) error {
 	user.UpdatedAt = time.Now()
 	_, err := r.coll.UpdateOne(
 		ctx,
-		bson.M{"_id": user.ID},
+		userIDFilter(user.ID),
 		bson.M{"$set": user},
 	)
 	return err
@@ -102,12 +110,47 @@ func (r *UsersRepo) UpdatePassword(ctx context.Context, userID, newPassword stri
 		return err
 	}
 
+	now := time.Now()
 	_, err = r.coll.UpdateOne(
 		ctx,
-		bson.M{"_id": userID},
+		userIDFilter(userID),
+		bson.M{"$set": bson.M{
+			"password":          string(hashedPassword),
+			"passwordChangedAt": now,
+			"updatedAt":         now,
+		}},
+	)
+	return err
+}
+
+// MarkEmailVerified confirma o email do usuário (somente se ainda for o email para o qual o token foi enviado)
+func (r *UsersRepo) MarkEmailVerified(ctx context.Context, userID, email string) (bool, error) {
+	filter := userIDFilter(userID)
+	filter["email"] = email
+	now := time.Now()
+	res, err := r.coll.UpdateOne(
+		ctx,
+		filter,
+		bson.M{"$set": bson.M{
+			"emailVerified":   true,
+			"emailVerifiedAt": now,
+			"updatedAt":       now,
+		}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return res.MatchedCount > 0, nil
+}
+
+// UpdateTenantRole altera o papel do usuário no tenant
+func (r *UsersRepo) UpdateTenantRole(ctx context.Context, userID string, role domain.TenantRole) error {
+	_, err := r.coll.UpdateOne(
+		ctx,
+		userIDFilter(userID),
 		bson.M{"$set": bson.M{
-			"password":  string(hashedPassword),
-			"updatedAt": time.Now(),
+			"tenantRole": role,
+			"updatedAt":  time.Now(),
 		}},
 	)
 	return err
@@ -115,7 +158,7 @@ func (r *UsersRepo) UpdatePassword(ctx context.Context, userID, newPassword stri
 
 // Delete deleta um usuário
 func (r *UsersRepo) Delete(ctx context.Context, id string) error {
-	_, err := r.coll.DeleteOne(ctx, bson.M{"_id": id})
+	_, err := r.coll.DeleteOne(ctx, userIDFilter(id))
 	return err
 }
 